@@ -13,6 +13,23 @@ type Config struct {
 	Storage  StorageStack `json:"storage" yaml:"storage"`
 	NodeKeys NodeInfoKeys `json:"nodeInfoKeys" yaml:"nodeInfoKeys"`
 	NodeInfo v1.NodeInfo  `json:"nodeInfo,omitempty"`
+	// SnapshotAutoExtend extends COW snapshots of LVM before they are full
+	SnapshotAutoExtend SnapshotAutoExtend `json:"snapshotAutoExtend" yaml:"snapshotAutoExtend"`
+}
+
+// SnapshotAutoExtend is the policy of extending COW snapshots of LVM.
+// A COW snapshot is invalidated once its COW space is full, and then its data is lost.
+type SnapshotAutoExtend struct {
+	// Disabled stops extending snapshots. Usage of snapshots is still monitored.
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled"`
+	// IntervalSeconds is the interval of polling usage of snapshots
+	IntervalSeconds int `json:"intervalSeconds,omitempty" yaml:"intervalSeconds"`
+	// ThresholdPercent is the usage percent of COW space to extend the snapshot
+	ThresholdPercent int `json:"thresholdPercent,omitempty" yaml:"thresholdPercent"`
+	// ExtendPercent is the percent of current snapshot size to add each time
+	ExtendPercent int `json:"extendPercent,omitempty" yaml:"extendPercent"`
+	// MaxSizePercent caps the snapshot size, in percent of origin volume size
+	MaxSizePercent int `json:"maxSizePercent,omitempty" yaml:"maxSizePercent"`
 }
 
 type NodeInfoKeys struct {
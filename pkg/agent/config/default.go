@@ -7,9 +7,35 @@ const (
 	SigmaLabelKeyRoom     = "lite.io/room"
 )
 
+const (
+	DefaultSnapshotMonitorIntervalSeconds = 30
+	DefaultSnapshotExtendThresholdPercent = 80
+	DefaultSnapshotExtendPercent          = 20
+	DefaultSnapshotExtendMaxSizePercent   = 100
+)
+
 func SetDefaults(cfg *Config) {
 	// set label key
 	SetNodeInfoDefaults(&cfg.NodeKeys)
+	SetSnapshotAutoExtendDefaults(&cfg.SnapshotAutoExtend)
+}
+
+func SetSnapshotAutoExtendDefaults(cfg *SnapshotAutoExtend) {
+	if cfg.IntervalSeconds <= 0 {
+		cfg.IntervalSeconds = DefaultSnapshotMonitorIntervalSeconds
+	}
+
+	if cfg.ThresholdPercent <= 0 || cfg.ThresholdPercent > 100 {
+		cfg.ThresholdPercent = DefaultSnapshotExtendThresholdPercent
+	}
+
+	if cfg.ExtendPercent <= 0 {
+		cfg.ExtendPercent = DefaultSnapshotExtendPercent
+	}
+
+	if cfg.MaxSizePercent <= 0 {
+		cfg.MaxSizePercent = DefaultSnapshotExtendMaxSizePercent
+	}
 }
 
 func SetNodeInfoDefaults(cfg *NodeInfoKeys) {
@@ -62,6 +62,8 @@ type StoragePoolManager struct {
 	runnableGroup *runnable.RunnableGroup
 	// lister is used to list metric target components from AntstorVolume
 	lister metric.MetricTargetListerIface
+	// ioStats is the IO load of pool, which is reported to StoragePool status
+	ioStats *metric.PoolIOStats
 }
 
 func NewStoragePoolManager(opt Option, kubeCli kubernetes.Interface, storeCli versioned.Interface) (spm *StoragePoolManager, err error) {
@@ -93,6 +95,8 @@ func NewStoragePoolManager(opt Option, kubeCli kubernetes.Interface, storeCli ve
 		return
 	}
 
+	spm.ioStats = metric.NewPoolIOStats(spm.PoolService.SpdkService())
+
 	spm.sp = spm.PoolService.GetStoragePool()
 	// set node id
 	spm.sp.Spec.NodeInfo.ID = spm.Opt.NodeID
@@ -176,8 +180,9 @@ func (spm *StoragePoolManager) Start() {
 	var ctx = context.Background()
 	spm.runnableGroup = runnable.NewRunnableGroup(errCh)
 	spm.runnableGroup.AddDefault(agentsync.NewMigrationReconciler(spm.Opt.NodeID, spm.storeCli, spm.PoolService.SpdkService()))
-	spm.runnableGroup.AddDefault(agentsync.NewSnapshotSyncer(spm.storeCli, spm.PoolService))
-	spm.runnableGroup.AddDefault(agentsync.NewVolumeSyncer(spm.storeCli, spm.PoolService, spm.lister))
+	spm.runnableGroup.AddDefault(agentsync.NewSnapshotSyncer(spm.storeCli, spm.PoolService, spm.cfg.SnapshotAutoExtend))
+	spm.runnableGroup.AddDefault(agentsync.NewVolumeSyncer(spm.storeCli, spm.kubeCli, spm.PoolService, spm.lister))
+	spm.runnableGroup.AddDefault(agentsync.NewBackupSyncer(spm.storeCli, spm.kubeCli, spm.PoolService))
 	spm.runnableGroup.AddDefault(agentsync.NewDataControlReconciler(spm.Opt.NodeID, spm.storeCli))
 
 	spm.runnableGroup.AddDefault(&HeartbeatService{
@@ -189,11 +194,15 @@ func (spm *StoragePoolManager) Start() {
 	spm.runnableGroup.AddDefault(agentsync.NewPoolSyncer(spm.PoolService,
 		spm.storeCli,
 		kubeutil.NewKubeNodeInfoGetter(spm.kubeCli),
-		spm.cfg))
+		spm.cfg,
+		spm.ioStats))
 
 	// init exporter collector
 	if spm.Opt.MetricListenAddr != "" {
-		spm.runnableGroup.AddDefault(metric.NewCollector(10*time.Second, spm.lister, spm.PoolService.SpdkService()))
+		spm.runnableGroup.AddDefault(metric.NewCollector(10*time.Second, spm.lister, spm.PoolService.SpdkService(), spm.ioStats))
+	} else {
+		// IO stats of pool is always collected for scheduling
+		spm.runnableGroup.AddDefault(metric.NewIOStatsCollector(10*time.Second, spm.lister, spm.ioStats))
 	}
 
 	spm.runnableGroup.Start(ctx)
@@ -21,12 +21,15 @@ type Collector struct {
 	writers  []metricWriter
 }
 
-func NewCollector(interval time.Duration, lister MetricTargetListerIface, spdkSvc spdk.SpdkServiceIface) *Collector {
+func NewCollector(interval time.Duration, lister MetricTargetListerIface, spdkSvc spdk.SpdkServiceIface, ioStats *PoolIOStats) *Collector {
 	writers := []metricWriter{
 		NewSpdkLvolMetricWriter(spdkSvc),
 		NewSpdkSubsystemMetricWriter(spdkSvc),
 		NewBlockDeviceMetricWriter(),
 	}
+	if ioStats != nil {
+		writers = append(writers, ioStats)
+	}
 
 	return &Collector{
 		interval: interval,
@@ -35,6 +38,15 @@ func NewCollector(interval time.Duration, lister MetricTargetListerIface, spdkSv
 	}
 }
 
+// NewIOStatsCollector only collects IO stats of pool, without writing Prometheus metrics
+func NewIOStatsCollector(interval time.Duration, lister MetricTargetListerIface, ioStats *PoolIOStats) *Collector {
+	return &Collector{
+		interval: interval,
+		lister:   lister,
+		writers:  []metricWriter{ioStats},
+	}
+}
+
 func (c *Collector) Start(ctx context.Context) (err error) {
 	ticker := time.NewTicker(c.interval)
 	wg := sync.WaitGroup{}
@@ -0,0 +1,198 @@
+package metric
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/toolkits/nux"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/spdk"
+)
+
+const (
+	// ioStatsWindowSize is the count of samples to calculate the rolling average
+	ioStatsWindowSize = 6
+	sectorSize        = 512
+)
+
+// ioCounters are the accumulated IO counters of one metric target
+type ioCounters struct {
+	readOps, writeOps     uint64
+	readBytes, writeBytes uint64
+	// unit: us
+	queueTime uint64
+}
+
+// PoolIOStats collects IO counters of all volumes on the pool, and calculates the rolling average of IO load.
+// Block device stats are used for KernelLVol volumes, and SPDK subsystem stats are used for the others.
+type PoolIOStats struct {
+	spdkSvc       spdk.SpdkServiceIface
+	listDiskStats func() ([]*nux.DiskStats, error)
+
+	lock     sync.Mutex
+	lastTime time.Time
+	// key is id of metric target
+	lastCounters map[string]ioCounters
+	window       []v1.PoolIOStats
+}
+
+func NewPoolIOStats(spdkSvc spdk.SpdkServiceIface) *PoolIOStats {
+	return &PoolIOStats{
+		spdkSvc:       spdkSvc,
+		listDiskStats: nux.ListDiskStats,
+	}
+}
+
+func (s *PoolIOStats) name() string {
+	return "pool_iostats"
+}
+
+func (s *PoolIOStats) writeMetrics(list []metricTarget) (err error) {
+	counters, err := s.readCounters(list)
+	if err != nil {
+		return
+	}
+	s.addSample(time.Now(), counters)
+	return
+}
+
+// Summary returns the rolling average of IO load. Nil is returned if there is no enough samples.
+func (s *PoolIOStats) Summary() (stats *v1.PoolIOStats) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.window) == 0 {
+		return
+	}
+
+	stats = &v1.PoolIOStats{}
+	for _, item := range s.window {
+		stats.ReadIOPS += item.ReadIOPS
+		stats.WriteIOPS += item.WriteIOPS
+		stats.ReadBps += item.ReadBps
+		stats.WriteBps += item.WriteBps
+		stats.QueueTimeUs += item.QueueTimeUs
+	}
+	cnt := int64(len(s.window))
+	stats.ReadIOPS /= cnt
+	stats.WriteIOPS /= cnt
+	stats.ReadBps /= cnt
+	stats.WriteBps /= cnt
+	stats.QueueTimeUs /= cnt
+	stats.UpdateTime = metav1.NewTime(s.lastTime)
+
+	return
+}
+
+func (s *PoolIOStats) readCounters(list []metricTarget) (counters map[string]ioCounters, err error) {
+	var (
+		devMap    = make(map[uint64]metricTarget, len(list))
+		subsysMap = make(map[string]metricTarget, len(list))
+	)
+	counters = make(map[string]ioCounters, len(list))
+
+	for _, item := range list {
+		// LVM volume may also be exported by SPDK subsystem. Count it only once by block device stats.
+		if item.itemID.devID > 0 {
+			devMap[item.itemID.devID] = item
+		} else if item.itemID.subsysNQN != "" {
+			subsysMap[item.itemID.subsysNQN] = item
+		}
+	}
+
+	if len(devMap) > 0 {
+		var diskStats []*nux.DiskStats
+		diskStats, err = s.listDiskStats()
+		if err != nil {
+			err = fmt.Errorf("error of reading blkdev stats: %w", err)
+			return
+		}
+		for _, diskStat := range diskStats {
+			deviceId := uint64(diskStat.Major*256 + diskStat.Minor)
+			if blk, has := devMap[deviceId]; has {
+				counters[blk.id()] = ioCounters{
+					readOps:    diskStat.ReadRequests,
+					writeOps:   diskStat.WriteRequests,
+					readBytes:  diskStat.ReadSectors * sectorSize,
+					writeBytes: diskStat.WriteSectors * sectorSize,
+					queueTime:  diskStat.MsecWeightedTotal * 1000,
+				}
+			}
+		}
+	}
+
+	if len(subsysMap) > 0 && s.spdkSvc != nil {
+		var allStats []spdk.SubsystemStatResp
+		allStats, err = s.spdkSvc.GetTargetStats()
+		if err != nil {
+			err = fmt.Errorf("error of getting subsystem stat: %w", err)
+			return
+		}
+		for _, stat := range allStats {
+			if tgt, has := subsysMap[stat.SubsysName]; has {
+				counters[tgt.id()] = ioCounters{
+					readOps:    stat.NumReadOps,
+					writeOps:   stat.NumWriteOps,
+					readBytes:  stat.BytesRead,
+					writeBytes: stat.BytesWrite,
+					queueTime:  stat.TimeInQueue,
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// addSample calculates IO load since last sample, and pushes it to the window.
+// Targets which are new or whose counters are reset are skipped.
+func (s *PoolIOStats) addSample(now time.Time, counters map[string]ioCounters) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	defer func() {
+		s.lastTime = now
+		s.lastCounters = counters
+	}()
+
+	if s.lastTime.IsZero() || !now.After(s.lastTime) {
+		return
+	}
+
+	var (
+		delta   ioCounters
+		elapsed = now.Sub(s.lastTime).Seconds()
+	)
+	for id, cur := range counters {
+		last, has := s.lastCounters[id]
+		if !has || cur.readOps < last.readOps || cur.writeOps < last.writeOps ||
+			cur.readBytes < last.readBytes || cur.writeBytes < last.writeBytes || cur.queueTime < last.queueTime {
+			continue
+		}
+		delta.readOps += cur.readOps - last.readOps
+		delta.writeOps += cur.writeOps - last.writeOps
+		delta.readBytes += cur.readBytes - last.readBytes
+		delta.writeBytes += cur.writeBytes - last.writeBytes
+		delta.queueTime += cur.queueTime - last.queueTime
+	}
+
+	sample := v1.PoolIOStats{
+		ReadIOPS:  int64(float64(delta.readOps) / elapsed),
+		WriteIOPS: int64(float64(delta.writeOps) / elapsed),
+		ReadBps:   int64(float64(delta.readBytes) / elapsed),
+		WriteBps:  int64(float64(delta.writeBytes) / elapsed),
+	}
+	if ops := delta.readOps + delta.writeOps; ops > 0 {
+		sample.QueueTimeUs = int64(delta.queueTime / ops)
+	}
+	klog.V(4).Infof("pool io stats sample %+v", sample)
+
+	s.window = append(s.window, sample)
+	if len(s.window) > ioStatsWindowSize {
+		s.window = s.window[len(s.window)-ioStatsWindowSize:]
+	}
+}
@@ -0,0 +1,57 @@
+package metric
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/toolkits/nux"
+)
+
+func TestPoolIOStats(t *testing.T) {
+	var (
+		stats = NewPoolIOStats(nil)
+		now   = time.Now()
+		tgt   = metricTarget{volUUID: "uuid-1", itemID: itemIdentity{devID: 253*256 + 1, devPath: "/dev/vg/lv1"}}
+		disk  = &nux.DiskStats{Major: 253, Minor: 1}
+	)
+	stats.listDiskStats = func() ([]*nux.DiskStats, error) {
+		return []*nux.DiskStats{disk}, nil
+	}
+
+	// first sample has no delta
+	counters, err := stats.readCounters([]metricTarget{tgt})
+	assert.NoError(t, err)
+	stats.addSample(now, counters)
+	assert.Nil(t, stats.Summary())
+
+	// 10s later, 1000 reads of 4KiB, 500 writes, total queue time 3s
+	disk.ReadRequests = 1000
+	disk.ReadSectors = 1000 * 8
+	disk.WriteRequests = 500
+	disk.MsecWeightedTotal = 3000
+	counters, err = stats.readCounters([]metricTarget{tgt})
+	assert.NoError(t, err)
+	stats.addSample(now.Add(10*time.Second), counters)
+
+	summary := stats.Summary()
+	assert.NotNil(t, summary)
+	assert.Equal(t, int64(100), summary.ReadIOPS)
+	assert.Equal(t, int64(50), summary.WriteIOPS)
+	assert.Equal(t, int64(100*4096), summary.ReadBps)
+	assert.Equal(t, int64(2000), summary.QueueTimeUs)
+
+	// idle for 10s, average of the window is halved
+	counters, err = stats.readCounters([]metricTarget{tgt})
+	assert.NoError(t, err)
+	stats.addSample(now.Add(20*time.Second), counters)
+	summary = stats.Summary()
+	assert.Equal(t, int64(50), summary.ReadIOPS)
+
+	// window is limited
+	for i := 3; i < 3+ioStatsWindowSize; i++ {
+		stats.addSample(now.Add(time.Duration(i)*10*time.Second), counters)
+	}
+	assert.Len(t, stats.window, ioStatsWindowSize)
+	assert.Equal(t, int64(0), stats.Summary().ReadIOPS)
+}
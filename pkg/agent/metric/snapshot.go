@@ -0,0 +1,96 @@
+package metric
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	lvmSnapshotMetricSubsystem = "lvm_snapshot"
+
+	snapshotDataPercent = "data_percent"
+	snapshotSize        = "size_bytes"
+	snapshotOriginSize  = "origin_size_bytes"
+	snapshotInvalid     = "invalid"
+	snapshotExtend      = "auto_extend_total"
+)
+
+var (
+	// node is Node Name; snapshot is name of AntstorSnapshot; volume is name of origin AntstorVolume; lv is name of snapshot LV
+	snapshotLabelKeys = []string{"node", "snapshot", "volume", "lv"}
+
+	snapshotDataPercentGaugeVec = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: lvmSnapshotMetricSubsystem,
+		Name:      snapshotDataPercent,
+		Help:      "Used percent of COW space of snapshot",
+	}, snapshotLabelKeys)
+
+	snapshotSizeGaugeVec = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: lvmSnapshotMetricSubsystem,
+		Name:      snapshotSize,
+		Help:      "Size of COW space of snapshot",
+	}, snapshotLabelKeys)
+
+	snapshotOriginSizeGaugeVec = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: lvmSnapshotMetricSubsystem,
+		Name:      snapshotOriginSize,
+		Help:      "Size of origin volume of snapshot",
+	}, snapshotLabelKeys)
+
+	snapshotInvalidGaugeVec = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: lvmSnapshotMetricSubsystem,
+		Name:      snapshotInvalid,
+		Help:      "1 if snapshot is invalidated because its COW space is full",
+	}, snapshotLabelKeys)
+
+	snapshotExtendCounterVec = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: lvmSnapshotMetricSubsystem,
+		Name:      snapshotExtend,
+		Help:      "Total times of extending snapshot automatically",
+	}, snapshotLabelKeys)
+)
+
+func init() {
+	Registry.MustRegister(snapshotDataPercentGaugeVec)
+	Registry.MustRegister(snapshotSizeGaugeVec)
+	Registry.MustRegister(snapshotOriginSizeGaugeVec)
+	Registry.MustRegister(snapshotInvalidGaugeVec)
+	Registry.MustRegister(snapshotExtendCounterVec)
+}
+
+// SnapshotMetricLabels identifies the metrics of a snapshot LV
+type SnapshotMetricLabels struct {
+	Node     string
+	Snapshot string
+	Volume   string
+	LV       string
+}
+
+func (l SnapshotMetricLabels) values() []string {
+	return []string{l.Node, l.Snapshot, l.Volume, l.LV}
+}
+
+// SetSnapshotUsage sets the usage of COW space of snapshot
+func SetSnapshotUsage(l SnapshotMetricLabels, sizeByte, originSizeByte uint64, dataPercent float64, invalid bool) {
+	var invalidVal float64
+	if invalid {
+		invalidVal = 1
+	}
+	snapshotDataPercentGaugeVec.WithLabelValues(l.values()...).Set(dataPercent)
+	snapshotSizeGaugeVec.WithLabelValues(l.values()...).Set(float64(sizeByte))
+	snapshotOriginSizeGaugeVec.WithLabelValues(l.values()...).Set(float64(originSizeByte))
+	snapshotInvalidGaugeVec.WithLabelValues(l.values()...).Set(invalidVal)
+}
+
+// IncSnapshotExtend counts extending the snapshot
+func IncSnapshotExtend(l SnapshotMetricLabels) {
+	snapshotExtendCounterVec.WithLabelValues(l.values()...).Inc()
+}
+
+// DeleteSnapshotUsage removes metrics of the snapshot which is deleted or not monitored any more
+func DeleteSnapshotUsage(l SnapshotMetricLabels) {
+	snapshotDataPercentGaugeVec.DeleteLabelValues(l.values()...)
+	snapshotSizeGaugeVec.DeleteLabelValues(l.values()...)
+	snapshotOriginSizeGaugeVec.DeleteLabelValues(l.values()...)
+	snapshotInvalidGaugeVec.DeleteLabelValues(l.values()...)
+	snapshotExtendCounterVec.DeleteLabelValues(l.values()...)
+}
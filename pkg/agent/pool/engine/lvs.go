@@ -143,8 +143,45 @@ func (pe *SpdkLvsPoolEngine) CreateSnapshot(req CreateSnapshotRequest) (err erro
 	return
 }
 
-func (pe *SpdkLvsPoolEngine) RestoreSnapshot(snapshotName string) (err error) {
-	err = fmt.Errorf("SPDK LVS not support RestoreSnapshot")
+// RestoreSnapshot rolls back the origin lvol. SPDK has no merge, so the origin lvol is deleted and cloned from the snapshot again.
+// The new clone is inflated, and the snapshot is kept.
+func (pe *SpdkLvsPoolEngine) RestoreSnapshot(req RestoreSnapshotRequest) (err error) {
+	klog.Info("restoring snapshot of Spdk lvol ", req)
+	if req.OriginName == "" {
+		return fmt.Errorf("origin of snapshot %s is empty", req.SnapshotName)
+	}
+
+	// origin may be deleted in a previous failed restoring
+	var list []spdk.Bdev
+	list, err = pe.spdk.BdevGetBdevs(spdk.BdevGetBdevsReq{
+		BdevName: fmt.Sprintf("%s/%s", pe.LvsName, req.OriginName),
+	})
+	if err != nil && !spdk.IsNotFoundDeviceError(err) {
+		return
+	}
+	if len(list) > 0 {
+		err = pe.spdk.DeleteLvol(spdk.DeleteLvolReq{
+			LVStore:  pe.LvsName,
+			LvolName: req.OriginName,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	_, err = pe.spdk.CreateLvolClone(spdk.CreateLvolCloneReq{
+		LVStore:   pe.LvsName,
+		SnapName:  req.SnapshotName,
+		CloneName: req.OriginName,
+	})
+	if err != nil {
+		return
+	}
+
+	err = pe.spdk.InflateLvol(spdk.InflateLvolReq{
+		LVStore:  pe.LvsName,
+		LvolName: req.OriginName,
+	})
 	return
 }
 
@@ -160,3 +197,26 @@ func (pe *SpdkLvsPoolEngine) ExpandVolume(req ExpandVolumeRequest) (err error) {
 
 	return
 }
+
+// GetSnapshotUsage is not supported. SPDK lvol snapshot is read-only and shares clusters with its origin, it never runs out of space by itself.
+func (pe *SpdkLvsPoolEngine) GetSnapshotUsage(snapName string) (usage SnapshotUsage, err error) {
+	err = fmt.Errorf("snapshot usage of SPDK lvol %s is not supported", snapName)
+	return
+}
+
+// FreezeVolume pauses the NVMe-oF subsystem of the lvol, which is the only I/O path of the lvol
+func (pe *SpdkLvsPoolEngine) FreezeVolume(req FreezeVolumeRequest) (err error) {
+	klog.Info("freezing SPDK lvol ", req)
+	if req.TargetNQN == "" {
+		return
+	}
+	return pe.spdk.PauseSubsystem(req.TargetNQN)
+}
+
+func (pe *SpdkLvsPoolEngine) ThawVolume(req FreezeVolumeRequest) (err error) {
+	klog.Info("thawing SPDK lvol ", req)
+	if req.TargetNQN == "" {
+		return
+	}
+	return pe.spdk.ResumeSubsystem(req.TargetNQN)
+}
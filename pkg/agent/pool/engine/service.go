@@ -27,8 +27,13 @@ type VolumeServiceIface interface {
 	DeleteVolume(volName string) (err error)
 	GetVolume(volName string) (vol VolumeInfo, err error)
 	CreateSnapshot(req CreateSnapshotRequest) (err error)
-	RestoreSnapshot(snapshotName string) (err error)
+	RestoreSnapshot(req RestoreSnapshotRequest) (err error)
 	ExpandVolume(req ExpandVolumeRequest) (err error)
+	// FreezeVolume blocks I/O of the volume until ThawVolume. Both are idempotent.
+	FreezeVolume(req FreezeVolumeRequest) (err error)
+	ThawVolume(req FreezeVolumeRequest) (err error)
+	// GetSnapshotUsage returns the usage of COW space of snapshot. Only LVM COW snapshots have it.
+	GetSnapshotUsage(snapName string) (usage SnapshotUsage, err error)
 }
 
 type PoolingInfoIface interface {
@@ -80,6 +85,29 @@ type CreateSnapshotRequest struct {
 	SizeByte     uint64
 }
 
+type RestoreSnapshotRequest struct {
+	SnapshotName string
+	// OriginName is the volume to roll back. LVM gets origin from the snapshot LV,
+	// and uses OriginName only to activate the thin LV if the snapshot is already merged.
+	OriginName string
+}
+
+type FreezeVolumeRequest struct {
+	VolName string
+	// TargetNQN is the NVMe-oF subsystem of the volume. SPDK lvol is frozen by pausing its subsystem.
+	// If it is empty, the lvol is not exposed and has no I/O to block.
+	TargetNQN string
+}
+
+type SnapshotUsage struct {
+	SizeByte       uint64
+	OriginSizeByte uint64
+	// DataPercent is the used percent of COW space, from 0 to 100
+	DataPercent float64
+	// Invalid is true if the COW space is full and the snapshot is dropped by LVM
+	Invalid bool
+}
+
 type ExpandVolumeRequest struct {
 	VolName    string
 	TargetSize uint64
@@ -96,7 +96,8 @@ func (pe *LvmPoolEngine) TotalAndFreeSize() (total, free, virtualFree uint64, da
 					lvs, _ := lvm.LvmUtil.ListLVInVG(pe.VgName)
 					var used uint64
 					for _, lv := range lvs {
-						if lv.LvLayout == "thin,sparse" {
+						// thin snapshots share blocks with their origins, they are not counted in virtual size
+						if lv.LvLayout == "thin,sparse" && lv.Origin == "" {
 							used += lv.SizeByte
 						}
 					}
@@ -203,9 +204,26 @@ func (pe *LvmPoolEngine) CreateSnapshot(req CreateSnapshotRequest) (err error) {
 	return
 }
 
-func (pe *LvmPoolEngine) RestoreSnapshot(snapshotName string) (err error) {
-	klog.Info("restoring snapshot of LVM ", snapshotName)
-	err = pe.mergeSnapshot(snapshotName)
+func (pe *LvmPoolEngine) RestoreSnapshot(req RestoreSnapshotRequest) (err error) {
+	klog.Info("restoring snapshot of LVM ", req.SnapshotName)
+	// snapshot LV is removed after merging, so the restoring is already done by a previous retry
+	snapExist, _, _, err := isVolumeExistent(pe.VgName, req.SnapshotName)
+	if err != nil {
+		return
+	}
+	if !snapExist {
+		klog.Infof("snapshot %s not exists, it is already merged", req.SnapshotName)
+		// the previous retry may fail to activate thin LV after merging
+		if pe.IsThin && req.OriginName != "" {
+			err = lvm.LvmUtil.ActivateLV(pe.VgName, req.OriginName)
+			if err != nil {
+				klog.Error(err)
+			}
+		}
+		return
+	}
+
+	err = pe.mergeSnapshot(req.SnapshotName)
 	if err != nil {
 		return
 	}
@@ -230,6 +248,42 @@ func (pe *LvmPoolEngine) ExpandVolume(req ExpandVolumeRequest) (err error) {
 	return
 }
 
+// FreezeVolume suspends the device-mapper device of LV. The filesystem mounted on it is frozen too.
+func (pe *LvmPoolEngine) FreezeVolume(req FreezeVolumeRequest) (err error) {
+	klog.Info("freezing LVM vol ", req.VolName)
+	return lvm.LvmUtil.SuspendLV(pe.VgName, req.VolName)
+}
+
+func (pe *LvmPoolEngine) ThawVolume(req FreezeVolumeRequest) (err error) {
+	klog.Info("thawing LVM vol ", req.VolName)
+	return lvm.LvmUtil.ResumeLV(pe.VgName, req.VolName)
+}
+
+// GetSnapshotUsage reads data_percent and attributes of COW snapshot LV
+func (pe *LvmPoolEngine) GetSnapshotUsage(snapName string) (usage SnapshotUsage, err error) {
+	volExists, _, target, err := isVolumeExistent(pe.VgName, snapName)
+	if err != nil {
+		return
+	}
+	if !volExists {
+		err = fmt.Errorf("snapshot LV %s not found", snapName)
+		return
+	}
+	if target.Origin == "" {
+		err = fmt.Errorf("LV %s is not a snapshot", snapName)
+		return
+	}
+
+	usage.SizeByte = target.SizeByte
+	usage.OriginSizeByte, _ = strconv.ParseUint(strings.TrimSuffix(target.OriginSize, "B"), 10, 64)
+	usage.DataPercent, _ = strconv.ParseFloat(target.DataPercent, 64)
+	// the 5th char of lv_attr is state, "I" means invalid snapshot
+	if len(target.LvAttr) > 4 && target.LvAttr[4] == 'I' {
+		usage.Invalid = true
+	}
+	return
+}
+
 func (pe *LvmPoolEngine) allocate(name string, size uint64, lvLayout v1.LVLayout) (vol v1.KernelLvol, err error) {
 	var vgName = pe.VgName
 	var volExists, hasLinearLV bool
@@ -319,6 +373,20 @@ func (pe *LvmPoolEngine) createSnapshot(snapVol, originVol string, size uint64)
 		return
 	}
 
+	// thin snapshot is created in the thin pool of origin, it has no size
+	if pe.IsThin {
+		if volExists {
+			klog.Infof("thin snapshot %s already exists", snapVol)
+			return
+		}
+		klog.Infof("create thin snap %s of %s", snapVol, originVol)
+		err = lvm.LvmUtil.CreateThinSnapshot(pe.VgName, snapVol, originVol)
+		if err != nil {
+			klog.Errorf("create thin snapshot %s failed: %+v", snapVol, err)
+		}
+		return
+	}
+
 	if !volExists {
 		// If there is any linear volume, create linear LV.
 		// Otherwise, create stripe LV.
@@ -382,6 +450,14 @@ func (pe *LvmPoolEngine) mergeSnapshot(snapName string) (err error) {
 
 	klog.Info("start merging snap ", snapName, snapVol)
 
+	if pe.IsThin {
+		err = lvm.LvmUtil.MergeThinSnapshot(vgName, snapName, snapVol.Origin)
+		if err != nil {
+			klog.Error(err)
+		}
+		return
+	}
+
 	err = lvm.LvmUtil.MergeSnapshot(vgName, snapName)
 	if err != nil {
 		klog.Error(err)
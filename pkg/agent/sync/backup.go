@@ -0,0 +1,269 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/util/s3"
+)
+
+// Layout of backups in the bucket:
+//
+//	<prefix>/backups/<namespace>/<name>/manifest.json  manifest of each backup
+//	<prefix>/chunks/<sha256[:2]>/<sha256>               data of chunks, shared by all backups of the prefix
+//
+// Chunks are addressed by their sha256, so a chunk which is stored by the base backup is not uploaded again.
+// The manifest lists all chunks of the backup, so a backup is restored without its base.
+//
+// When a SnapshotBackup is deleted, the controller deletes its manifest. Chunks are not deleted with the backup, because they are
+// referenced by other backups of the prefix. A chunk is garbage if no manifest under <prefix>/backups lists it.
+// Chunks of a prefix can be collected by listing all manifests and deleting the chunks not referenced by them,
+// while no backup is uploading to the prefix, because chunks are uploaded before the manifest of a new backup.
+
+// objectStore reads and writes objects of a bucket. It is implemented by s3.Client.
+type objectStore interface {
+	PutObject(ctx context.Context, bucket, key string, data []byte) error
+	GetObject(ctx context.Context, bucket, key string) ([]byte, error)
+}
+
+// backupManifest describes the chunks of a backup. It is uploaded after all chunks are uploaded.
+type backupManifest struct {
+	// Backup is namespace/name of SnapshotBackup
+	Backup string `json:"backup"`
+	// Snapshot is namespace/name of the snapshot
+	Snapshot string `json:"snapshot"`
+	// Volume is namespace/name of origin volume of the snapshot
+	Volume string `json:"volume"`
+	// BaseBackup is namespace/name of the base backup
+	BaseBackup     string `json:"baseBackup,omitempty"`
+	SizeBytes      int64  `json:"sizeBytes"`
+	ChunkSizeBytes int64  `json:"chunkSizeBytes"`
+	// Chunks are the hex sha256 of each chunk. Zero chunk is empty string.
+	Chunks    []string  `json:"chunks"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// backupProgress is the counters of uploading a backup
+type backupProgress struct {
+	ProcessedBytes  int64
+	UploadedBytes   int64
+	UploadedChunks  int64
+	UnchangedChunks int64
+	ZeroChunks      int64
+}
+
+func backupManifestKey(prefix, ns, name string) string {
+	return path.Join(prefix, "backups", ns, name, "manifest.json")
+}
+
+func backupChunkKey(prefix, sum string) string {
+	return path.Join(prefix, "chunks", sum[:2], sum)
+}
+
+// newBackupStore returns the client of the target of backup. Credentials are read from the Secret of target.
+func newBackupStore(kubeCli kubernetes.Interface, backup *v1.SnapshotBackup) (cli *s3.Client, err error) {
+	var (
+		target               = backup.Spec.Target
+		secretNS, secretName = backup.CredentialsSecret()
+		secret               *corev1.Secret
+	)
+	secret, err = kubeCli.CoreV1().Secrets(secretNS).Get(context.Background(), secretName, metav1.GetOptions{})
+	if err != nil {
+		return
+	}
+
+	return s3.NewClient(s3.Config{
+		Endpoint:  target.Endpoint,
+		Region:    target.Region,
+		AccessKey: string(secret.Data[v1.BackupSecretAccessKeyIDKey]),
+		SecretKey: string(secret.Data[v1.BackupSecretSecretAccessKeyKey]),
+		Insecure:  target.Insecure,
+	})
+}
+
+func getBackupManifest(ctx context.Context, store objectStore, bucket, key string) (manifest *backupManifest, err error) {
+	data, err := store.GetObject(ctx, bucket, key)
+	if err != nil {
+		return
+	}
+	manifest = &backupManifest{}
+	if err = json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", key, err)
+	}
+	return
+}
+
+func putBackupManifest(ctx context.Context, store objectStore, bucket, key string, manifest *backupManifest) (err error) {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return
+	}
+	return store.PutObject(ctx, bucket, key, data)
+}
+
+// uploadChunks reads size bytes of device src chunk by chunk, and uploads the chunks which are not zero and not stored by base.
+// manifest.SizeBytes and manifest.ChunkSizeBytes must be set, and manifest.Chunks is filled.
+// progress is called periodically and when all chunks are uploaded. It may be nil.
+func uploadChunks(ctx context.Context, store objectStore, bucket, prefix, src string, manifest, base *backupManifest, progress func(p backupProgress)) (err error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var (
+		chunkSize    = manifest.ChunkSizeBytes
+		buf          = make([]byte, chunkSize)
+		stored       = make(map[string]bool)
+		p            backupProgress
+		lastReported = time.Now()
+		n            int
+	)
+	// chunks of the same size in the base backup are already stored
+	if base != nil && base.ChunkSizeBytes == chunkSize {
+		for _, sum := range base.Chunks {
+			if sum != "" {
+				stored[sum] = true
+			}
+		}
+	}
+
+	manifest.Chunks = make([]string, 0, (manifest.SizeBytes+chunkSize-1)/chunkSize)
+	for p.ProcessedBytes < manifest.SizeBytes {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		toRead := chunkSize
+		if remain := manifest.SizeBytes - p.ProcessedBytes; remain < toRead {
+			toRead = remain
+		}
+		n, err = io.ReadFull(f, buf[:toRead])
+		if err != nil {
+			return fmt.Errorf("read %s at offset %d failed: %w", src, p.ProcessedBytes, err)
+		}
+
+		var sum string
+		if isZero(buf[:n]) {
+			p.ZeroChunks++
+		} else {
+			hash := sha256.Sum256(buf[:n])
+			sum = hex.EncodeToString(hash[:])
+			if stored[sum] {
+				p.UnchangedChunks++
+			} else {
+				if err = store.PutObject(ctx, bucket, backupChunkKey(prefix, sum), buf[:n]); err != nil {
+					return fmt.Errorf("upload chunk %d failed: %w", len(manifest.Chunks), err)
+				}
+				stored[sum] = true
+				p.UploadedChunks++
+				p.UploadedBytes += int64(n)
+			}
+		}
+		manifest.Chunks = append(manifest.Chunks, sum)
+		p.ProcessedBytes += int64(n)
+
+		if progress != nil && time.Since(lastReported) >= copyProgressInterval {
+			progress(p)
+			lastReported = time.Now()
+		}
+	}
+
+	if progress != nil {
+		progress(p)
+	}
+	return
+}
+
+// restoreChunks downloads the chunks of manifest and writes them to device dst. The checksum of each chunk is verified.
+// Zero chunks are written as zero, because the device may have stale data, unless skipZero is true for the device reading zero of unwritten blocks.
+// progress is called with the written bytes periodically and when restore is finished. It may be nil.
+func restoreChunks(ctx context.Context, store objectStore, bucket, prefix, dst string, manifest *backupManifest, skipZero bool, progress func(written int64)) (err error) {
+	f, err := os.OpenFile(dst, os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var (
+		zero         = make([]byte, manifest.ChunkSizeBytes)
+		written      int64
+		lastReported = time.Now()
+		data         []byte
+	)
+	for idx, sum := range manifest.Chunks {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		size := manifest.ChunkSizeBytes
+		if remain := manifest.SizeBytes - written; remain < size {
+			size = remain
+		}
+		if sum == "" && skipZero {
+			written += size
+			continue
+		}
+		if sum == "" {
+			data = zero[:size]
+		} else {
+			data, err = store.GetObject(ctx, bucket, backupChunkKey(prefix, sum))
+			if err != nil {
+				return fmt.Errorf("download chunk %d failed: %w", idx, err)
+			}
+			hash := sha256.Sum256(data)
+			if hex.EncodeToString(hash[:]) != sum || int64(len(data)) != size {
+				return fmt.Errorf("chunk %d is corrupted, expect sha256 %s and %d bytes, got %d bytes", idx, sum, size, len(data))
+			}
+		}
+		if _, err = f.WriteAt(data, written); err != nil {
+			return fmt.Errorf("write %s at offset %d failed: %w", dst, written, err)
+		}
+		written += size
+
+		if progress != nil && time.Since(lastReported) >= copyProgressInterval {
+			progress(written)
+			lastReported = time.Now()
+		}
+	}
+
+	if err = f.Sync(); err != nil {
+		return
+	}
+	if progress != nil {
+		progress(written)
+	}
+	return
+}
+
+func isZero(data []byte) bool {
+	var zero [4096]byte
+	for len(data) > 0 {
+		n := len(data)
+		if n > len(zero) {
+			n = len(zero)
+		}
+		if !bytes.Equal(data[:n], zero[:n]) {
+			return false
+		}
+		data = data[n:]
+	}
+	return true
+}
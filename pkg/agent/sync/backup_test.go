@@ -0,0 +1,99 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/util/s3"
+)
+
+type memStore struct {
+	lock    sync.Mutex
+	objects map[string][]byte
+	puts    int
+}
+
+func (m *memStore) PutObject(ctx context.Context, bucket, key string, data []byte) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.objects[bucket+"/"+key] = append([]byte(nil), data...)
+	m.puts++
+	return nil
+}
+
+func (m *memStore) GetObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	data, has := m.objects[bucket+"/"+key]
+	if !has {
+		return nil, s3.ErrNotFound
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func TestBackupAndRestoreChunks(t *testing.T) {
+	const chunkSize = 4096
+	var (
+		dir   = t.TempDir()
+		src   = filepath.Join(dir, "snap")
+		dst   = filepath.Join(dir, "vol")
+		size  = int64(chunkSize*4 + 100)
+		data  = make([]byte, size)
+		store = &memStore{objects: make(map[string][]byte)}
+		ctx   = context.Background()
+	)
+	// chunk 1 is zero
+	rand.Read(data[:chunkSize])
+	rand.Read(data[chunkSize*2:])
+	assert.NoError(t, os.WriteFile(src, data, 0644))
+
+	full := &backupManifest{SizeBytes: size, ChunkSizeBytes: chunkSize}
+	var progress backupProgress
+	err := uploadChunks(ctx, store, "bkt", "pre", src, full, nil, func(p backupProgress) { progress = p })
+	assert.NoError(t, err)
+	assert.Len(t, full.Chunks, 5)
+	assert.Equal(t, "", full.Chunks[1])
+	assert.Equal(t, backupProgress{ProcessedBytes: size, UploadedBytes: size - chunkSize, UploadedChunks: 4, ZeroChunks: 1}, progress)
+	assert.NoError(t, putBackupManifest(ctx, store, "bkt", backupManifestKey("pre", v1.DefaultNamespace, "full"), full))
+
+	// only the changed chunk is uploaded by the incremental backup
+	rand.Read(data[chunkSize*3 : chunkSize*4])
+	assert.NoError(t, os.WriteFile(src, data, 0644))
+	base, err := getBackupManifest(ctx, store, "bkt", backupManifestKey("pre", v1.DefaultNamespace, "full"))
+	assert.NoError(t, err)
+	incr := &backupManifest{SizeBytes: size, ChunkSizeBytes: chunkSize}
+	err = uploadChunks(ctx, store, "bkt", "pre", src, incr, base, func(p backupProgress) { progress = p })
+	assert.NoError(t, err)
+	assert.Equal(t, backupProgress{ProcessedBytes: size, UploadedBytes: chunkSize, UploadedChunks: 1, UnchangedChunks: 3, ZeroChunks: 1}, progress)
+
+	// stale data in the zero chunk is overwritten
+	assert.NoError(t, os.WriteFile(dst, bytes.Repeat([]byte{0xff}, int(size)), 0644))
+	var written int64
+	err = restoreChunks(ctx, store, "bkt", "pre", dst, incr, false, func(n int64) { written = n })
+	assert.NoError(t, err)
+	assert.Equal(t, size, written)
+	out, err := os.ReadFile(dst)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(data, out))
+
+	// zero chunk is skipped for thin volume
+	assert.NoError(t, os.WriteFile(dst, bytes.Repeat([]byte{0xff}, int(size)), 0644))
+	assert.NoError(t, restoreChunks(ctx, store, "bkt", "pre", dst, incr, true, nil))
+	out, err = os.ReadFile(dst)
+	assert.NoError(t, err)
+	assert.Equal(t, bytes.Repeat([]byte{0xff}, chunkSize), out[chunkSize:chunkSize*2])
+	assert.True(t, bytes.Equal(data[chunkSize*2:], out[chunkSize*2:]))
+
+	// corrupted chunk is detected
+	store.objects["bkt/"+backupChunkKey("pre", incr.Chunks[0])][0] ^= 0xff
+	err = restoreChunks(ctx, store, "bkt", "pre", dst, incr, false, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,83 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// copyChunkSize is the size of each read and write of blockCopy
+	copyChunkSize = 4 << 20
+	// copyProgressInterval is the minimal interval of reporting copy progress
+	copyProgressInterval = 5 * time.Second
+)
+
+// blockCopy copies total bytes from device src to device dst, and syncs dst after data is written.
+// progress is called with the copied bytes periodically and when copy is finished. It may be nil.
+func blockCopy(ctx context.Context, src, dst string, total int64, progress func(copied int64)) (err error) {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return
+	}
+	defer srcFile.Close()
+
+	dstFile, err := os.OpenFile(dst, os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	defer dstFile.Close()
+
+	var (
+		buf          = make([]byte, copyChunkSize)
+		copied       int64
+		lastReported = time.Now()
+		n            int
+	)
+	for copied < total {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		toRead := int64(len(buf))
+		if remain := total - copied; remain < toRead {
+			toRead = remain
+		}
+		n, err = io.ReadFull(srcFile, buf[:toRead])
+		if err != nil {
+			return fmt.Errorf("read %s at offset %d failed: %w", src, copied, err)
+		}
+		if _, err = dstFile.Write(buf[:n]); err != nil {
+			return fmt.Errorf("write %s at offset %d failed: %w", dst, copied, err)
+		}
+		copied += int64(n)
+
+		if progress != nil && time.Since(lastReported) >= copyProgressInterval {
+			progress(copied)
+			lastReported = time.Now()
+		}
+	}
+
+	if err = dstFile.Sync(); err != nil {
+		return
+	}
+	if progress != nil {
+		progress(copied)
+	}
+
+	return
+}
+
+// deviceSize returns the size of block device or file
+func deviceSize(path string) (size int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	return f.Seek(0, io.SeekEnd)
+}
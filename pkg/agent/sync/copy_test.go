@@ -0,0 +1,129 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/generated/clientset/versioned/fake"
+)
+
+func TestBlockCopy(t *testing.T) {
+	var (
+		dir   = t.TempDir()
+		src   = filepath.Join(dir, "src")
+		dst   = filepath.Join(dir, "dst")
+		total = int64(copyChunkSize*2 + 4096)
+		data  = make([]byte, total+4096)
+	)
+	rand.Read(data)
+	assert.NoError(t, os.WriteFile(src, data, 0644))
+	// dst is bigger than src, like a cloned volume with larger size
+	assert.NoError(t, os.WriteFile(dst, make([]byte, total+8192), 0644))
+
+	var reported []int64
+	err := blockCopy(context.Background(), src, dst, total, func(copied int64) {
+		reported = append(reported, copied)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{total}, reported)
+
+	out, err := os.ReadFile(dst)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(data[:total], out[:total]))
+	assert.Equal(t, make([]byte, 8192), out[total:])
+
+	// source is smaller than total
+	err = blockCopy(context.Background(), src, dst, total*2, nil)
+	assert.Error(t, err)
+
+	// canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = blockCopy(ctx, src, dst, total, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCopySourceOf(t *testing.T) {
+	vol := &v1.AntstorVolume{}
+	vol.Name = "vol-1"
+	vol.Spec.Type = v1.VolumeTypeKernelLVol
+
+	_, _, _, ok := copySourceOf(vol)
+	assert.False(t, ok)
+
+	vol.Labels = map[string]string{
+		v1.VolumeSourceSnapNameLabelKey:      "snap-1",
+		v1.VolumeSourceSnapNamespaceLabelKey: "obnvmf",
+	}
+	ns, name, temporary, ok := copySourceOf(vol)
+	assert.True(t, ok)
+	assert.False(t, temporary)
+	assert.Equal(t, "obnvmf/snap-1", ns+"/"+name)
+
+	vol.Labels = map[string]string{
+		v1.VolumeSourceVolNameLabelKey:      "src",
+		v1.VolumeSourceVolNamespaceLabelKey: "obnvmf",
+	}
+	ns, name, temporary, ok = copySourceOf(vol)
+	assert.True(t, ok)
+	assert.True(t, temporary)
+	assert.Equal(t, "obnvmf/vol-1-clone-src", ns+"/"+name)
+
+	// SPDK lvol is cloned without copy
+	vol.Spec.Type = v1.VolumeTypeSpdkLVol
+	_, _, _, ok = copySourceOf(vol)
+	assert.False(t, ok)
+}
+
+func TestRunCopyRetry(t *testing.T) {
+	backoff := dataCopyRetryBaseBackoff
+	dataCopyRetryBaseBackoff = 0
+	defer func() {
+		dataCopyRetryBaseBackoff = backoff
+	}()
+	var (
+		dir = t.TempDir()
+		vol = &v1.AntstorVolume{}
+	)
+	vol.Namespace = v1.DefaultNamespace
+	vol.Name = "vol-1"
+	vol.Spec.Type = v1.VolumeTypeKernelLVol
+	vol.Spec.KernelLvol = &v1.KernelLvol{DevPath: filepath.Join(dir, "dst")}
+	vol.Status.DataCopy = &v1.DataCopyStatus{
+		Phase:      v1.DataCopyPhaseCopying,
+		TotalBytes: 4096,
+	}
+	assert.NoError(t, os.WriteFile(vol.Spec.KernelLvol.DevPath, make([]byte, 4096), 0644))
+
+	var (
+		cli = fake.NewSimpleClientset(vol)
+		vs  = &VolumeSyncer{storeCli: cli, copies: make(map[string]context.CancelFunc)}
+	)
+	getDataCopy := func() *v1.DataCopyStatus {
+		latest, err := cli.VolumeV1().AntstorVolumes(vol.Namespace).Get(context.Background(), vol.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		return latest.Status.DataCopy
+	}
+
+	// source device is missing, copy is started again
+	vs.runCopy(context.Background(), vol.DeepCopy(), filepath.Join(dir, "src"))
+	dc := getDataCopy()
+	assert.Equal(t, v1.DataCopyPhasePending, dc.Phase)
+	assert.Equal(t, int32(1), dc.Retries)
+	assert.NotEmpty(t, dc.Message)
+
+	// too many retries
+	vol.Status.DataCopy.Retries = maxDataCopyRetries
+	vs.runCopy(context.Background(), vol.DeepCopy(), filepath.Join(dir, "src"))
+	dc = getDataCopy()
+	assert.Equal(t, v1.DataCopyPhaseFailed, dc.Phase)
+	assert.False(t, vs.isCopying(vol.Name))
+}
@@ -0,0 +1,286 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"lite.io/liteio/pkg/agent/pool"
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/generated/clientset/versioned"
+)
+
+// BackupSyncer uploads data of snapshots on this node to object storage, for the SnapshotBackups scheduled to this node by the controller
+type BackupSyncer struct {
+	nodeID      string
+	poolService pool.StoragePoolServiceIface
+	// storeCli is used to read/write SnapshotBackups and AntstorSnapshots from APIServer
+	storeCli versioned.Interface
+	// kubeCli is used to read credentials of object storage
+	kubeCli kubernetes.Interface
+
+	// uploads are the cancel funcs of running uploads, key is namespace/name of backup
+	uploads    map[string]context.CancelFunc
+	uploadLock sync.Mutex
+}
+
+func NewBackupSyncer(storeCli versioned.Interface, kubeCli kubernetes.Interface, poolSvc pool.StoragePoolServiceIface) *BackupSyncer {
+	return &BackupSyncer{
+		nodeID:      poolSvc.GetStoragePool().Name,
+		poolService: poolSvc,
+		storeCli:    storeCli,
+		kubeCli:     kubeCli,
+		uploads:     make(map[string]context.CancelFunc),
+	}
+}
+
+func (bs *BackupSyncer) Start(ctx context.Context) (err error) {
+	backupListWatcher := cache.NewFilteredListWatchFromClient(bs.storeCli.VolumeV1().RESTClient(), "snapshotbackups",
+		v1.DefaultNamespace, func(options *metav1.ListOptions) {
+			options.LabelSelector = fmt.Sprintf("%s=%s", v1.TargetNodeIdLabelKey, bs.nodeID)
+		})
+
+	backupSyncLoop := NewSyncLoop("BackupLoop", backupListWatcher, &v1.SnapshotBackup{}, func(name string) (err error) {
+		return bs.syncOneBackup(name)
+	})
+
+	backupSyncLoop.RunLoop(ctx.Done())
+	return
+}
+
+func (bs *BackupSyncer) syncOneBackup(nsName string) (err error) {
+	ns, name, err := cache.SplitMetaNamespaceKey(nsName)
+	if err != nil {
+		return
+	}
+	backup, err := bs.storeCli.VolumeV1().SnapshotBackups(ns).Get(context.Background(), name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		bs.cancelUpload(nsName)
+		return nil
+	}
+	if err != nil {
+		return
+	}
+
+	if backup.DeletionTimestamp != nil {
+		klog.Infof("backup %s is being deleted, stop uploading", nsName)
+		bs.cancelUpload(nsName)
+		return
+	}
+	if backup.IsFinished() || backup.Status.NodeID != bs.nodeID {
+		return
+	}
+
+	bs.uploadLock.Lock()
+	defer bs.uploadLock.Unlock()
+	if _, has := bs.uploads[nsName]; has {
+		klog.Infof("backup %s is being uploaded", nsName)
+		return
+	}
+
+	// an upload interrupted by restart of agent is started over
+	ctx, cancel := context.WithCancel(context.Background())
+	bs.uploads[nsName] = cancel
+	klog.Infof("start uploading backup %s of snapshot %s", nsName, backup.Spec.SnapshotName)
+	go bs.runUpload(ctx, backup.DeepCopy())
+
+	return
+}
+
+func (bs *BackupSyncer) runUpload(ctx context.Context, backup *v1.SnapshotBackup) {
+	var key = backup.Namespace + "/" + backup.Name
+	err := bs.upload(ctx, backup)
+
+	bs.uploadLock.Lock()
+	delete(bs.uploads, key)
+	bs.uploadLock.Unlock()
+
+	// backup is being deleted
+	if ctx.Err() != nil {
+		klog.Infof("uploading backup %s is canceled", key)
+		return
+	}
+
+	if err != nil {
+		klog.Errorf("uploading backup %s failed: %+v", key, err)
+		errUpdate := bs.updateBackupStatus(backup, func(status *v1.SnapshotBackupStatus) {
+			status.Phase = v1.SnapshotBackupPhaseFailed
+			status.Message = err.Error()
+			status.CompletionTime = &metav1.Time{Time: time.Now()}
+		})
+		if errUpdate != nil {
+			klog.Error(errUpdate)
+		}
+		return
+	}
+	klog.Infof("uploading backup %s is completed", key)
+}
+
+// upload reads the snapshot chunk by chunk, uploads the changed chunks and the manifest, and sets the backup to Completed
+func (bs *BackupSyncer) upload(ctx context.Context, backup *v1.SnapshotBackup) (err error) {
+	var (
+		target   = backup.Spec.Target
+		snapshot *v1.AntstorSnapshot
+		base     *backupManifest
+		src      string
+		release  func()
+		size     int64
+	)
+	snapshot, err = bs.storeCli.VolumeV1().AntstorSnapshots(backup.Namespace).Get(ctx, backup.Spec.SnapshotName, metav1.GetOptions{})
+	if err != nil {
+		return
+	}
+	if snapshot.Status.Status != v1.SnapshotStatusReady {
+		return fmt.Errorf("snapshot %s is %q, not ready", snapshot.Name, snapshot.Status.Status)
+	}
+
+	store, err := newBackupStore(bs.kubeCli, backup)
+	if err != nil {
+		return
+	}
+
+	if backup.Status.BaseBackup != "" {
+		var baseBackup *v1.SnapshotBackup
+		baseBackup, err = bs.storeCli.VolumeV1().SnapshotBackups(backup.Namespace).Get(ctx, backup.Status.BaseBackup, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("get base backup %s failed: %w", backup.Status.BaseBackup, err)
+		}
+		base, err = getBackupManifest(ctx, store, target.Bucket, baseBackup.Status.ManifestKey)
+		if err != nil {
+			return fmt.Errorf("get manifest of base backup %s failed: %w", baseBackup.Name, err)
+		}
+	}
+
+	src, release, err = lvolDevice(bs.poolService, snapshot.Spec.VolType, snapshot.Spec.KernelLvol.DevPath, snapshot.Spec.SpdkLvol.FullName())
+	if err != nil {
+		return
+	}
+	defer release()
+	size, err = deviceSize(src)
+	if err != nil {
+		return
+	}
+
+	var manifest = &backupManifest{
+		Backup:         backup.Namespace + "/" + backup.Name,
+		Snapshot:       snapshot.Namespace + "/" + snapshot.Name,
+		Volume:         snapshot.Spec.OriginVolNamespace + "/" + snapshot.Spec.OriginVolName,
+		SizeBytes:      size,
+		ChunkSizeBytes: backup.ChunkSize(),
+	}
+	if base != nil {
+		manifest.BaseBackup = base.Backup
+	}
+
+	err = bs.updateBackupStatus(backup, func(status *v1.SnapshotBackupStatus) {
+		status.Phase = v1.SnapshotBackupPhaseUploading
+		status.Message = ""
+		status.SizeBytes = size
+		status.TotalChunks = (size + manifest.ChunkSizeBytes - 1) / manifest.ChunkSizeBytes
+		status.ProcessedBytes = 0
+		status.UploadedBytes = 0
+		status.UploadedChunks = 0
+		status.UnchangedChunks = 0
+		status.ZeroChunks = 0
+		status.StartTime = &metav1.Time{Time: time.Now()}
+	})
+	if err != nil {
+		return
+	}
+
+	var progress backupProgress
+	err = uploadChunks(ctx, store, target.Bucket, target.Prefix, src, manifest, base, func(p backupProgress) {
+		progress = p
+		errUpdate := bs.updateBackupStatus(backup, func(status *v1.SnapshotBackupStatus) {
+			setBackupProgress(status, p)
+		})
+		if errUpdate != nil {
+			klog.Error(errUpdate)
+		}
+	})
+	if err != nil {
+		return
+	}
+
+	manifest.CreatedAt = time.Now()
+	manifestKey := backupManifestKey(target.Prefix, backup.Namespace, backup.Name)
+	if err = putBackupManifest(ctx, store, target.Bucket, manifestKey, manifest); err != nil {
+		return
+	}
+
+	return bs.updateBackupStatus(backup, func(status *v1.SnapshotBackupStatus) {
+		setBackupProgress(status, progress)
+		status.Phase = v1.SnapshotBackupPhaseCompleted
+		status.ManifestKey = manifestKey
+		status.Message = ""
+		status.CompletionTime = &metav1.Time{Time: time.Now()}
+	})
+}
+
+func (bs *BackupSyncer) cancelUpload(key string) {
+	bs.uploadLock.Lock()
+	defer bs.uploadLock.Unlock()
+	if cancel, has := bs.uploads[key]; has {
+		cancel()
+		delete(bs.uploads, key)
+	}
+}
+
+// updateBackupStatus updates status of the latest backup by fn, and sets the updated status back to backup
+func (bs *BackupSyncer) updateBackupStatus(backup *v1.SnapshotBackup, fn func(status *v1.SnapshotBackupStatus)) (err error) {
+	var cli = bs.storeCli.VolumeV1().SnapshotBackups(backup.Namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := cli.Get(context.Background(), backup.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		fn(&latest.Status)
+		updated, err := cli.UpdateStatus(context.Background(), latest, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		backup.ResourceVersion = updated.ResourceVersion
+		backup.Status = updated.Status
+		return nil
+	})
+}
+
+func setBackupProgress(status *v1.SnapshotBackupStatus, p backupProgress) {
+	status.ProcessedBytes = p.ProcessedBytes
+	status.UploadedBytes = p.UploadedBytes
+	status.UploadedChunks = p.UploadedChunks
+	status.UnchangedChunks = p.UnchangedChunks
+	status.ZeroChunks = p.ZeroChunks
+}
+
+// lvolDevice returns the block device of LVM or SPDK lvol. SPDK lvol is exported as a NBD device, which is stopped by release.
+func lvolDevice(poolSvc pool.StoragePoolServiceIface, volType v1.VolumeType, devPath, spdkLvolName string) (dev string, release func(), err error) {
+	release = func() {}
+	switch volType {
+	case v1.VolumeTypeKernelLVol:
+		if devPath == "" {
+			return "", release, fmt.Errorf("device path of LV is empty")
+		}
+		return devPath, release, nil
+	case v1.VolumeTypeSpdkLVol:
+		dev, err = poolSvc.SpdkService().StartNbdDisk(spdkLvolName)
+		if err != nil {
+			return
+		}
+		release = func() {
+			if errStop := poolSvc.SpdkService().StopNbdDisk(dev); errStop != nil {
+				klog.Errorf("stop nbd disk %s of lvol %s failed: %+v", dev, spdkLvolName, errStop)
+			}
+		}
+		return
+	}
+	return "", release, fmt.Errorf("volume type %q is not supported", volType)
+}
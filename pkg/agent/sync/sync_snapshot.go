@@ -6,13 +6,18 @@ import (
 	"strconv"
 	"time"
 
+	"lite.io/liteio/pkg/agent/config"
+	"lite.io/liteio/pkg/agent/metric"
 	"lite.io/liteio/pkg/agent/pool"
 	"lite.io/liteio/pkg/agent/pool/engine"
 	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
 	"lite.io/liteio/pkg/generated/clientset/versioned"
+	"lite.io/liteio/pkg/spdk"
+	spdkrpc "lite.io/liteio/pkg/spdk/jsonrpc/client"
 	"lite.io/liteio/pkg/util/misc"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 )
@@ -21,12 +26,19 @@ type SnapshotSyncer struct {
 	poolService pool.StoragePoolServiceIface
 	// storeCli is used to read/write StoragePool, AntstorVolumes from APIServer
 	storeCli versioned.Interface
+	// syncLoop requeues the member snapshot of SnapshotGroup to check freeze timeout
+	syncLoop *SyncLoop
+	// autoExtend is the policy of extending COW snapshots of LVM
+	autoExtend config.SnapshotAutoExtend
+	// monitored is the snapshots whose usage metrics are exported
+	monitored map[metric.SnapshotMetricLabels]bool
 }
 
-func NewSnapshotSyncer(storeCli versioned.Interface, poolSvc pool.StoragePoolServiceIface) *SnapshotSyncer {
+func NewSnapshotSyncer(storeCli versioned.Interface, poolSvc pool.StoragePoolServiceIface, autoExtend config.SnapshotAutoExtend) *SnapshotSyncer {
 	return &SnapshotSyncer{
 		poolService: poolSvc,
 		storeCli:    storeCli,
+		autoExtend:  autoExtend,
 	}
 }
 
@@ -37,10 +49,14 @@ func (ss *SnapshotSyncer) Start(ctx context.Context) (err error) {
 			options.LabelSelector = fmt.Sprintf("%s=%s", v1.TargetNodeIdLabelKey, poolName)
 		})
 
-	snapSyncLoop := NewSyncLoop("SnapshotLoop", snapListWatcher, &v1.AntstorSnapshot{}, func(name string) (err error) {
+	ss.syncLoop = NewSyncLoop("SnapshotLoop", snapListWatcher, &v1.AntstorSnapshot{}, func(name string) (err error) {
 		return ss.syncOneSnapshot(name)
 	})
-	snapSyncLoop.RunLoop(ctx.Done())
+	// COW snapshots of LVM are invalidated once they are full
+	if ss.poolService.Mode() == v1.PoolModeKernelLVM {
+		go wait.Until(ss.monitorSnapshots, time.Duration(ss.autoExtend.IntervalSeconds)*time.Second, ctx.Done())
+	}
+	ss.syncLoop.RunLoop(ctx.Done())
 	return
 }
 
@@ -65,6 +81,40 @@ func (ss *SnapshotSyncer) syncOneSnapshot(nsName string) (err error) {
 
 		// TODO: recondier snapshot deletion constraint
 
+		// member snapshot of SnapshotGroup is deleted before it is cut, its volume may be still frozen
+		if misc.InSliceString(v1.VolumeFreezeFinalizer, snapshot.Finalizers) {
+			err = ss.thawGroupMember(snapshot)
+			if err != nil {
+				klog.Error(err)
+				return
+			}
+			_, err = snapCli.Update(context.Background(), snapshot, metav1.UpdateOptions{})
+			if err != nil {
+				klog.Error(err)
+			}
+			return
+		}
+
+		// data of snapshot is being copied to a volume. The agent copying data removes the finalizer after copy is done.
+		if misc.InSliceString(v1.DataCopySourceFinalizer, snapshot.Finalizers) {
+			klog.Infof("snapshot %s is the source of data copy, wait for the copy to finish", name)
+			return
+		}
+
+		// data of snapshot is being uploaded. The controller removes the finalizer after the backups of the snapshot are finished.
+		if misc.InSliceString(v1.SnapshotBackupFinalizer, snapshot.Finalizers) {
+			klog.Infof("snapshot %s is being backed up, wait for the backup to finish", name)
+			return
+		}
+
+		if snapshot.Status.ExportTarget != nil {
+			err = ss.unexportSnapshot(snapshot)
+			if err != nil {
+				klog.Error(err)
+			}
+			return
+		}
+
 		if misc.InSliceString(v1.SnapshotFinalizer, snapshot.Finalizers) {
 			klog.Infof("deleting snapshot %s lvol, vol type %s", name, string(snapshot.Spec.VolType))
 			var volName string
@@ -99,6 +149,22 @@ func (ss *SnapshotSyncer) syncOneSnapshot(nsName string) (err error) {
 	}
 
 	klog.Infof("start syncing snapshot %s lvol", name)
+	if snapshot.Status.Status == v1.SnapshotStatusReady && snapshot.Spec.ExportToNodeID != "" && snapshot.Status.ExportTarget == nil {
+		err = ss.exportSnapshot(snapshot)
+		if err != nil {
+			klog.Error(err)
+		}
+		return
+	}
+	// data copy is finished
+	if snapshot.Spec.ExportToNodeID == "" && snapshot.Status.ExportTarget != nil {
+		err = ss.unexportSnapshot(snapshot)
+		if err != nil {
+			klog.Error(err)
+		}
+		return
+	}
+
 	if snapshot.Status.Status == v1.SnapshotStatusReady || snapshot.Status.Status == v1.SnapshotStatusMerged {
 		klog.Infof("snapshot %s is already Ready, stop syncing", name)
 		return
@@ -112,6 +178,7 @@ func (ss *SnapshotSyncer) syncOneSnapshot(nsName string) (err error) {
 		if misc.InSliceString(v1.SnapshotFinalizer, snapshot.Finalizers) {
 			klog.Infof("update snapshot %s to ready", name)
 			snapshot.Status.Status = v1.SnapshotStatusReady
+			snapshot.Status.Consistency = snapshotConsistency(snapshot)
 			_, err = snapCli.UpdateStatus(context.Background(), snapshot, metav1.UpdateOptions{})
 			if err != nil {
 				klog.Error(err)
@@ -119,11 +186,43 @@ func (ss *SnapshotSyncer) syncOneSnapshot(nsName string) (err error) {
 			return
 		}
 
+		// member snapshot of SnapshotGroup is created after origin volumes of all members are frozen
+		var _, isGroupMember = snapshot.Labels[v1.SnapshotGroupLabelKey]
+		if isGroupMember {
+			var cut bool
+			cut, err = ss.syncGroupMember(snapshot)
+			if err != nil {
+				klog.Error(err)
+			}
+			if err != nil || !cut {
+				return
+			}
+		}
+
+		// filesystem of origin volume is frozen on the host node before the cut. Members of SnapshotGroup are crash consistent.
+		if snapshot.Spec.FsFreezeTimeoutSeconds > 0 && !isGroupMember {
+			var cut bool
+			cut, err = ss.syncFsFreeze(snapshot)
+			if err != nil {
+				klog.Error(err)
+			}
+			if err != nil || !cut {
+				return
+			}
+		}
+
 		// do create
 		var originName, snapName string
 		var sp = ss.poolService.GetStoragePool()
+		var _, isCopySource = snapshot.Labels[v1.SnapshotCopyForLabelKey]
+		var _, isScheduled = snapshot.Labels[v1.SnapshotScheduleLabelKey]
 		if ss.poolService.Mode() == v1.PoolModeKernelLVM {
 			snapName = fmt.Sprintf("%s_snap", snapshot.Spec.OriginVolName)
+			// temporary snapshot for cloning may co-exist with the snapshot of user, and scheduled or group snapshots co-exist with each other.
+			// Thin snapshots are chained in the thin pool, any number of them co-exist.
+			if isCopySource || isScheduled || isGroupMember || snapshot.Spec.IsThin {
+				snapName = snapshot.Name
+			}
 			vgName := sp.Spec.KernelLVM.Name
 			originName = snapshot.Spec.OriginVolName
 
@@ -134,6 +233,9 @@ func (ss *SnapshotSyncer) syncOneSnapshot(nsName string) (err error) {
 		} else if ss.poolService.Mode() == v1.PoolModeSpdkLVStore {
 			lvsName := sp.Spec.SpdkLVStore.Name
 			snapName = fmt.Sprintf("%s_snap", snapshot.Spec.OriginVolName)
+			if isScheduled || isGroupMember {
+				snapName = snapshot.Name
+			}
 			originName = fmt.Sprintf("%s/%s", lvsName, snapshot.Spec.OriginVolName)
 
 			snapshot.Spec.SpdkLvol.Name = snapName
@@ -152,22 +254,48 @@ func (ss *SnapshotSyncer) syncOneSnapshot(nsName string) (err error) {
 			return
 		}
 
+		// the snapshot is cut, the origin volume is not frozen any more
+		if isGroupMember {
+			err = ss.thawGroupMember(snapshot)
+			if err != nil {
+				klog.Error(err)
+				return
+			}
+		}
+
 		// update Finalizer and Spec.KernelLVM
 		snapshot.Finalizers = append(snapshot.Finalizers, v1.SnapshotFinalizer)
 		_, err = snapCli.Update(context.Background(), snapshot, metav1.UpdateOptions{})
+		// the node plugin records thawing the filesystem on timeout during the cut, so the snapshot is not application consistent
+		if errors.IsConflict(err) && snapshot.Spec.FsFreezeTimeoutSeconds > 0 {
+			klog.Infof("snapshot %s is updated during the cut, record the cut with the latest snapshot", name)
+			err = ss.recordCut(snapshot)
+		}
 		if err != nil {
 			klog.Error(err)
 		}
 		return
 	}
 
-	// merge snapshot lvol
-	if snapshot.Spec.VolType == v1.VolumeTypeKernelLVol && snapshot.Status.Status == v1.SnapshotStatusMerging {
+	// merge snapshot lvol, which rolls back the origin volume to the snapshot
+	if snapshot.Status.Status == v1.SnapshotStatusMerging {
 		klog.Info("start merging snapshot")
 
-		// merge is finished
+		if misc.InSliceString(v1.DataCopySourceFinalizer, snapshot.Finalizers) {
+			klog.Infof("snapshot %s is the source of data copy, wait for the copy to finish before merging", name)
+			return
+		}
+		if misc.InSliceString(v1.SnapshotBackupFinalizer, snapshot.Finalizers) {
+			klog.Infof("snapshot %s is being backed up, wait for the backup to finish before merging", name)
+			return
+		}
+
+		// merge is finished. SPDK snapshot is kept after the origin is rolled back, so it becomes Ready again
 		if _, has := snapshot.Labels[v1.MergeFinishTimestampLabelKey]; has {
 			snapshot.Status.Status = v1.SnapshotStatusMerged
+			if snapshot.Spec.VolType == v1.VolumeTypeSpdkLVol {
+				snapshot.Status.Status = v1.SnapshotStatusReady
+			}
 			_, err = snapCli.UpdateStatus(context.Background(), snapshot, metav1.UpdateOptions{})
 			if err != nil {
 				klog.Error(err)
@@ -175,15 +303,53 @@ func (ss *SnapshotSyncer) syncOneSnapshot(nsName string) (err error) {
 			return
 		}
 
-		err = ss.poolService.PoolEngine().RestoreSnapshot(snapshot.Spec.KernelLvol.Name)
+		var volume *v1.AntstorVolume
+		volume, err = ss.storeCli.VolumeV1().AntstorVolumes(snapshot.Spec.OriginVolNamespace).Get(context.Background(), snapshot.Spec.OriginVolName, metav1.GetOptions{})
+		if err != nil {
+			klog.Error(err)
+			return
+		}
+
+		var req = engine.RestoreSnapshotRequest{
+			SnapshotName: snapshot.Spec.KernelLvol.Name,
+		}
+		if volume.Spec.KernelLvol != nil {
+			req.OriginName = volume.Spec.KernelLvol.Name
+		}
+		if snapshot.Spec.VolType == v1.VolumeTypeSpdkLVol {
+			req.SnapshotName = snapshot.Spec.SpdkLvol.Name
+			req.OriginName = volume.Spec.SpdkLvol.Name
+		}
+
+		// the target of volume holds the device open, remove it before merging and rebuild it afterwards
+		if volume.Spec.SpdkTarget != nil {
+			err = ss.poolService.Access().RemoveAccces(volumeAccess(volume, nil))
+			if err != nil {
+				klog.Error(err)
+				return
+			}
+		}
+
+		err = ss.poolService.PoolEngine().RestoreSnapshot(req)
 		if err != nil {
 			klog.Error(err)
 			return
 		}
 
+		if volume.Spec.SpdkTarget != nil {
+			err = ss.rebuildVolumeTarget(volume)
+			if err != nil {
+				klog.Error(err)
+				return
+			}
+		}
+
 		if _, has := snapshot.Labels[v1.MergeFinishTimestampLabelKey]; !has {
 			snapshot.Labels[v1.MergeFinishTimestampLabelKey] = strconv.Itoa(int(time.Now().Unix()))
-			snapshot.Status.Status = v1.SnapshotStatusMerged
+			// the controller sets snapshot to Merging if the label exists
+			if snapshot.Spec.VolType == v1.VolumeTypeSpdkLVol {
+				delete(snapshot.Labels, v1.MergeStartTimestampLabelKey)
+			}
 
 			_, err = snapCli.Update(context.Background(), snapshot, metav1.UpdateOptions{})
 			if err != nil {
@@ -197,3 +363,138 @@ func (ss *SnapshotSyncer) syncOneSnapshot(nsName string) (err error) {
 
 	return
 }
+
+// rebuildVolumeTarget creates the target of volume with the same SpdkTarget, so that the host reconnects to it
+func (ss *SnapshotSyncer) rebuildVolumeTarget(volume *v1.AntstorVolume) (err error) {
+	var allowHosts []string
+	if volume.Spec.HostNode != nil && volume.Spec.HostNode.ID != "" {
+		var hostPool *v1.StoragePool
+		hostPool, err = ss.storeCli.VolumeV1().StoragePools(v1.DefaultNamespace).Get(context.Background(), volume.Spec.HostNode.ID, metav1.GetOptions{})
+		if err != nil {
+			return
+		}
+		if hostNQN, has := hostPool.Annotations[v1.AnnotationHostNQN]; has {
+			allowHosts = append(allowHosts, hostNQN)
+		}
+	}
+
+	klog.Infof("rebuilding target of volume %s, target %+v", volume.Name, *volume.Spec.SpdkTarget)
+	_, err = ss.poolService.Access().ExposeAccess(volumeAccess(volume, allowHosts))
+	return
+}
+
+// volumeAccess returns the Access of volume by its SpdkTarget
+func volumeAccess(volume *v1.AntstorVolume, allowHosts []string) (a pool.Access) {
+	var target = volume.Spec.SpdkTarget
+	a = pool.Access{
+		OpenAccess: spdk.Target{
+			NQN:          target.SubsysNQN,
+			SerialNumber: target.SerialNum,
+			NSUUID:       target.NSUUID,
+			TransAddr:    target.Address,
+			TransType:    target.TransType,
+			AddrFam:      target.AddrFam,
+			SvcID:        target.SvcID,
+		},
+		AllowHostNQN: allowHosts,
+	}
+	switch volume.Spec.Type {
+	case v1.VolumeTypeKernelLVol:
+		a.AIO = &pool.AioVolume{
+			DevPath:  volume.Spec.KernelLvol.DevPath,
+			BdevName: target.BdevName,
+		}
+	case v1.VolumeTypeSpdkLVol:
+		a.LVol = &pool.SpdkLVolume{
+			LvsName:  volume.Spec.SpdkLvol.LvsName,
+			LvolName: volume.Spec.SpdkLvol.Name,
+		}
+	}
+	return
+}
+
+// exportSnapshot exposes the snapshot LV by NVMe-oF, so that the agent on ExportToNodeID can read data of the snapshot
+func (ss *SnapshotSyncer) exportSnapshot(snapshot *v1.AntstorSnapshot) (err error) {
+	var (
+		sp         = ss.poolService.GetStoragePool()
+		nodeIP     = sp.Spec.NodeInfo.IP
+		destPool   *v1.StoragePool
+		allowHosts []string
+		resp       spdk.Target
+	)
+
+	if snapshot.Spec.VolType != v1.VolumeTypeKernelLVol || snapshot.Spec.KernelLvol.DevPath == "" {
+		return fmt.Errorf("cannot export snapshot %s, only KernelLVol snapshot is supported", snapshot.Name)
+	}
+	if snapshot.Spec.Uuid == "" {
+		return fmt.Errorf("cannot export snapshot %s, no uuid", snapshot.Name)
+	}
+	if nodeIP == "" {
+		return fmt.Errorf("cannot get node ip (%+v) to export snapshot", sp.Spec.NodeInfo)
+	}
+
+	destPool, err = ss.storeCli.VolumeV1().StoragePools(v1.DefaultNamespace).Get(context.Background(), snapshot.Spec.ExportToNodeID, metav1.GetOptions{})
+	if err != nil {
+		return
+	}
+	if hostNQN, has := destPool.Annotations[v1.AnnotationHostNQN]; has {
+		allowHosts = append(allowHosts, hostNQN)
+	}
+
+	var target = &v1.SpdkTarget{
+		SubsysNQN: GetNQNFromUUID(snapshot.Spec.Uuid),
+		NSUUID:    snapshot.Spec.Uuid,
+		BdevName:  GetBdevNameFromUUID(snapshot.Spec.Uuid),
+		SerialNum: GetSNFromUUID(snapshot.Spec.Uuid),
+		TransType: spdkrpc.TransportTypeTCP,
+		Address:   nodeIP,
+		AddrFam:   string(spdkrpc.AddrFamilyIPv4),
+	}
+	klog.Infof("exporting snapshot %s to node %s", snapshot.Name, snapshot.Spec.ExportToNodeID)
+	resp, err = ss.poolService.Access().ExposeAccess(pool.Access{
+		AIO: &pool.AioVolume{
+			DevPath:  snapshot.Spec.KernelLvol.DevPath,
+			BdevName: target.BdevName,
+		},
+		OpenAccess: spdk.Target{
+			NQN:          target.SubsysNQN,
+			SerialNumber: target.SerialNum,
+			NSUUID:       target.NSUUID,
+			TransAddr:    target.Address,
+			TransType:    target.TransType,
+			AddrFam:      target.AddrFam,
+		},
+		AllowHostNQN: allowHosts,
+	})
+	if err != nil {
+		return
+	}
+	target.SvcID = resp.SvcID
+
+	snapshot.Status.ExportTarget = target
+	_, err = ss.storeCli.VolumeV1().AntstorSnapshots(snapshot.Namespace).UpdateStatus(context.Background(), snapshot, metav1.UpdateOptions{})
+	return
+}
+
+// unexportSnapshot removes the NVMe-oF target of the snapshot
+func (ss *SnapshotSyncer) unexportSnapshot(snapshot *v1.AntstorSnapshot) (err error) {
+	var target = snapshot.Status.ExportTarget
+	klog.Infof("removing export of snapshot %s, target %+v", snapshot.Name, *target)
+	err = ss.poolService.Access().RemoveAccces(pool.Access{
+		AIO: &pool.AioVolume{
+			BdevName: target.BdevName,
+		},
+		OpenAccess: spdk.Target{
+			TransAddr: target.Address,
+			TransType: target.TransType,
+			NQN:       target.SubsysNQN,
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	snapshot.Status.ExportTarget = nil
+	_, err = ss.storeCli.VolumeV1().AntstorSnapshots(snapshot.Namespace).UpdateStatus(context.Background(), snapshot, metav1.UpdateOptions{})
+	return
+}
@@ -0,0 +1,99 @@
+package sync
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+// syncFsFreeze asks the CSI node plugin on the host node of the origin volume to freeze the filesystem,
+// and waits until it is frozen, it fails, or the request times out. It returns true if the snapshot should be cut now.
+func (ss *SnapshotSyncer) syncFsFreeze(snapshot *v1.AntstorSnapshot) (cut bool, err error) {
+	var (
+		snapCli      = ss.storeCli.VolumeV1().AntstorSnapshots(snapshot.Namespace)
+		timeout      = time.Duration(snapshot.Spec.FsFreezeTimeoutSeconds) * time.Second
+		_, requested = snapshot.Annotations[v1.FsFreezeRequestedAtAnnoKey]
+		_, frozen    = snapshot.Annotations[v1.FsFrozenAtAnnoKey]
+		_, failed    = snapshot.Annotations[v1.FsFreezeErrorAnnoKey]
+		volume       *v1.AntstorVolume
+	)
+
+	if !requested {
+		volume, err = ss.storeCli.VolumeV1().AntstorVolumes(snapshot.Spec.OriginVolNamespace).Get(context.Background(), snapshot.Spec.OriginVolName, metav1.GetOptions{})
+		if err != nil {
+			return
+		}
+		if volume.Spec.HostNode == nil || volume.Spec.HostNode.ID == "" ||
+			volume.Status.CSINodePubParams == nil || volume.Status.CSINodePubParams.StagingTargetPath == "" {
+			klog.Infof("volume %s is not mounted, snapshot %s is cut without freezing filesystem", volume.Name, snapshot.Name)
+			return true, nil
+		}
+
+		klog.Infof("asking node %s to freeze filesystem of volume %s for snapshot %s", volume.Spec.HostNode.ID, volume.Name, snapshot.Name)
+		if snapshot.Labels == nil {
+			snapshot.Labels = make(map[string]string)
+		}
+		if snapshot.Annotations == nil {
+			snapshot.Annotations = make(map[string]string)
+		}
+		snapshot.Labels[v1.FsFreezeNodeLabelKey] = volume.Spec.HostNode.ID
+		snapshot.Annotations[v1.FsFreezePathAnnoKey] = volume.Status.CSINodePubParams.StagingTargetPath
+		snapshot.Annotations[v1.FsFreezeRequestedAtAnnoKey] = strconv.FormatInt(time.Now().Unix(), 10)
+		if _, err = snapCli.Update(context.Background(), snapshot, metav1.UpdateOptions{}); err != nil {
+			return
+		}
+		ss.requeueAfter(snapshot, timeout)
+		return
+	}
+
+	if frozen || failed {
+		return true, nil
+	}
+
+	sec, _ := strconv.ParseInt(snapshot.Annotations[v1.FsFreezeRequestedAtAnnoKey], 10, 64)
+	elapsed := time.Since(time.Unix(sec, 0))
+	if elapsed < timeout {
+		klog.Infof("snapshot %s is waiting for filesystem to be frozen", snapshot.Name)
+		ss.requeueAfter(snapshot, timeout-elapsed)
+		return
+	}
+
+	klog.Infof("filesystem is not frozen in %s, snapshot %s is cut without freezing filesystem", timeout, snapshot.Name)
+	return true, nil
+}
+
+// recordCut adds SnapshotFinalizer and the spec of the created snapshot to the latest snapshot.
+// It is called if the snapshot is updated by the node plugin during the cut.
+func (ss *SnapshotSyncer) recordCut(snapshot *v1.AntstorSnapshot) (err error) {
+	var snapCli = ss.storeCli.VolumeV1().AntstorSnapshots(snapshot.Namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := snapCli.Get(context.Background(), snapshot.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		latest.Spec = snapshot.Spec
+		if !misc.InSliceString(v1.SnapshotFinalizer, latest.Finalizers) {
+			latest.Finalizers = append(latest.Finalizers, v1.SnapshotFinalizer)
+		}
+		_, err = snapCli.Update(context.Background(), latest, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// snapshotConsistency returns the consistency level of a cut snapshot.
+// The snapshot is application consistent if the filesystem is frozen and not thawed before the cut.
+func snapshotConsistency(snapshot *v1.AntstorSnapshot) v1.SnapshotConsistency {
+	_, frozen := snapshot.Annotations[v1.FsFrozenAtAnnoKey]
+	_, failed := snapshot.Annotations[v1.FsFreezeErrorAnnoKey]
+	if frozen && !failed {
+		return v1.SnapshotConsistencyApplication
+	}
+	return v1.SnapshotConsistencyCrash
+}
@@ -0,0 +1,149 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"lite.io/liteio/pkg/agent/pool/engine"
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+// syncGroupMember freezes the origin volume of a member snapshot of SnapshotGroup, after the controller asks for it.
+// The volume is thawed if the controller does not cut the snapshot in time. It returns true if the snapshot should be created now.
+func (ss *SnapshotSyncer) syncGroupMember(snapshot *v1.AntstorSnapshot) (cut bool, err error) {
+	var (
+		snapCli          = ss.storeCli.VolumeV1().AntstorSnapshots(snapshot.Namespace)
+		timeout          = freezeTimeout(snapshot)
+		frozenAt, frozen = snapshot.Annotations[v1.FrozenAtAnnoKey]
+		_, isCut         = snapshot.Annotations[v1.CutAtAnnoKey]
+		_, thawed        = snapshot.Annotations[v1.ThawedAtAnnoKey]
+		volume           *v1.AntstorVolume
+	)
+
+	if _, requested := snapshot.Annotations[v1.FreezeTimeoutAnnoKey]; !requested {
+		klog.Infof("snapshot %s is waiting for other members of group to be scheduled", snapshot.Name)
+		return
+	}
+	if thawed {
+		klog.Infof("volume of snapshot %s is thawed before cut, the snapshot will not be created", snapshot.Name)
+		return
+	}
+	if isCut {
+		if !frozen {
+			klog.Errorf("snapshot %s is cut before its volume is frozen, ignore it", snapshot.Name)
+			return
+		}
+		return true, nil
+	}
+
+	// the finalizer is added before freezing, so the volume is always thawed even if the snapshot is deleted right after freezing
+	if !misc.InSliceString(v1.VolumeFreezeFinalizer, snapshot.Finalizers) {
+		snapshot.Finalizers = append(snapshot.Finalizers, v1.VolumeFreezeFinalizer)
+		_, err = snapCli.Update(context.Background(), snapshot, metav1.UpdateOptions{})
+		return
+	}
+
+	if !frozen {
+		volume, err = ss.storeCli.VolumeV1().AntstorVolumes(snapshot.Spec.OriginVolNamespace).Get(context.Background(), snapshot.Spec.OriginVolName, metav1.GetOptions{})
+		if err != nil {
+			return
+		}
+		klog.Infof("freezing volume %s for snapshot %s, timeout %s", volume.Name, snapshot.Name, timeout)
+		err = ss.poolService.PoolEngine().FreezeVolume(freezeRequest(volume))
+		if err != nil {
+			return
+		}
+		snapshot.Annotations[v1.FrozenAtAnnoKey] = strconv.FormatInt(time.Now().Unix(), 10)
+		_, err = snapCli.Update(context.Background(), snapshot, metav1.UpdateOptions{})
+		if err != nil {
+			return
+		}
+		ss.requeueAfter(snapshot, timeout)
+		return
+	}
+
+	// waiting for the cut
+	sec, _ := strconv.ParseInt(frozenAt, 10, 64)
+	elapsed := time.Since(time.Unix(sec, 0))
+	if elapsed < timeout {
+		ss.requeueAfter(snapshot, timeout-elapsed)
+		return
+	}
+
+	klog.Infof("snapshot %s is not cut in %s, thaw its volume", snapshot.Name, timeout)
+	err = ss.thawGroupMember(snapshot)
+	if err != nil {
+		return
+	}
+	_, err = snapCli.Update(context.Background(), snapshot, metav1.UpdateOptions{})
+	return
+}
+
+// thawGroupMember thaws the origin volume if it is frozen, sets annotation obnvmf/thawed-at and removes VolumeFreezeFinalizer.
+// The caller updates the snapshot.
+func (ss *SnapshotSyncer) thawGroupMember(snapshot *v1.AntstorSnapshot) (err error) {
+	_, frozen := snapshot.Annotations[v1.FrozenAtAnnoKey]
+	_, thawed := snapshot.Annotations[v1.ThawedAtAnnoKey]
+	if frozen && !thawed {
+		volume, getErr := ss.storeCli.VolumeV1().AntstorVolumes(snapshot.Spec.OriginVolNamespace).Get(context.Background(), snapshot.Spec.OriginVolName, metav1.GetOptions{})
+		switch {
+		case errors.IsNotFound(getErr):
+			klog.Infof("volume of snapshot %s is deleted, no need to thaw it", snapshot.Name)
+		case getErr != nil:
+			return getErr
+		default:
+			klog.Infof("thawing volume %s of snapshot %s", volume.Name, snapshot.Name)
+			err = ss.poolService.PoolEngine().ThawVolume(freezeRequest(volume))
+			if err != nil {
+				return
+			}
+		}
+		snapshot.Annotations[v1.ThawedAtAnnoKey] = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+
+	var newFinalizers = make([]string, 0, len(snapshot.Finalizers))
+	for _, item := range snapshot.Finalizers {
+		if item != v1.VolumeFreezeFinalizer {
+			newFinalizers = append(newFinalizers, item)
+		}
+	}
+	snapshot.Finalizers = newFinalizers
+	return
+}
+
+// requeueAfter syncs the snapshot again after d, even if the snapshot is not changed
+func (ss *SnapshotSyncer) requeueAfter(snapshot *v1.AntstorSnapshot, d time.Duration) {
+	if ss.syncLoop == nil || ss.syncLoop.Queue == nil {
+		return
+	}
+	ss.syncLoop.Queue.AddAfter(fmt.Sprintf("%s/%s", snapshot.Namespace, snapshot.Name), d)
+}
+
+// freezeTimeout returns the timeout in annotation obnvmf/freeze-timeout-seconds of snapshot
+func freezeTimeout(snapshot *v1.AntstorSnapshot) time.Duration {
+	sec, err := strconv.Atoi(snapshot.Annotations[v1.FreezeTimeoutAnnoKey])
+	if err != nil || sec <= 0 {
+		sec = v1.DefaultFreezeTimeoutSeconds
+	}
+	return time.Duration(sec) * time.Second
+}
+
+func freezeRequest(volume *v1.AntstorVolume) (req engine.FreezeVolumeRequest) {
+	switch volume.Spec.Type {
+	case v1.VolumeTypeKernelLVol:
+		req.VolName = volume.Spec.KernelLvol.Name
+	case v1.VolumeTypeSpdkLVol:
+		req.VolName = volume.Spec.SpdkLvol.Name
+	}
+	if volume.Spec.SpdkTarget != nil {
+		req.TargetNQN = volume.Spec.SpdkTarget.SubsysNQN
+	}
+	return
+}
@@ -0,0 +1,220 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	"lite.io/liteio/pkg/agent/config"
+	"lite.io/liteio/pkg/agent/metric"
+	"lite.io/liteio/pkg/agent/pool/engine"
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/util"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+// monitorSnapshots polls the usage of COW snapshots of LVM on this node, and exports it as metrics.
+// A snapshot is extended within the snapshot reserved space of its origin volume before its COW space is full,
+// and is set to error once it is invalidated by LVM.
+func (ss *SnapshotSyncer) monitorSnapshots() {
+	var (
+		poolName  = ss.poolService.GetStoragePool().GetName()
+		poolEng   = ss.poolService.PoolEngine()
+		monitored = make(map[metric.SnapshotMetricLabels]bool)
+		// reservedUsed is the sum of Spec.Size of snapshots in the snapshot reserved space of each origin volume
+		reservedUsed = make(map[string]uint64)
+	)
+	list, err := ss.storeCli.VolumeV1().AntstorSnapshots(v1.DefaultNamespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", v1.TargetNodeIdLabelKey, poolName),
+	})
+	if err != nil {
+		klog.Error(err)
+		return
+	}
+	_, free, _, _, _, err := poolEng.TotalAndFreeSize()
+	if err != nil {
+		klog.Error(err)
+		return
+	}
+
+	for i := range list.Items {
+		if inReservedSpace(&list.Items[i]) {
+			reservedUsed[originVolumeKey(&list.Items[i])] += uint64(list.Items[i].Spec.Size)
+		}
+	}
+
+	for i := range list.Items {
+		snapshot := &list.Items[i]
+		if !isCowSnapshot(snapshot) {
+			continue
+		}
+		lvName := snapshot.Spec.KernelLvol.Name
+		usage, err := poolEng.GetSnapshotUsage(lvName)
+		if err != nil {
+			// the LV of a snapshot in error may be already removed
+			if snapshot.Status.Status == v1.SnapshotStatusError {
+				klog.V(4).Infof("get usage of snapshot %s in error failed: %+v", snapshot.Name, err)
+			} else {
+				klog.Errorf("get usage of snapshot %s failed: %+v", snapshot.Name, err)
+			}
+			continue
+		}
+		labels := metric.SnapshotMetricLabels{
+			Node:     poolName,
+			Snapshot: snapshot.Name,
+			Volume:   snapshot.Spec.OriginVolName,
+			LV:       lvName,
+		}
+		monitored[labels] = true
+		metric.SetSnapshotUsage(labels, usage.SizeByte, usage.OriginSizeByte, usage.DataPercent, usage.Invalid)
+
+		// an invalidated snapshot is kept monitored until it is deleted, so lvm_snapshot_invalid stays 1
+		if usage.Invalid && snapshot.Status.Status != v1.SnapshotStatusError {
+			klog.Errorf("snapshot %s is invalidated, its COW space is full", snapshot.Name)
+			snapshot.Status.Status = v1.SnapshotStatusError
+			snapshot.Status.Message = fmt.Sprintf("snapshot LV %s is invalidated because its COW space of %d bytes is full", lvName, usage.SizeByte)
+			_, err = ss.storeCli.VolumeV1().AntstorSnapshots(snapshot.Namespace).UpdateStatus(context.Background(), snapshot, metav1.UpdateOptions{})
+			if err != nil {
+				klog.Error(err)
+			}
+			continue
+		}
+		if usage.Invalid || snapshot.Status.Status == v1.SnapshotStatusError {
+			continue
+		}
+
+		if ss.autoExtend.Disabled || usage.DataPercent < float64(ss.autoExtend.ThresholdPercent) {
+			continue
+		}
+		reserved, err := ss.snapshotReservedSize(snapshot)
+		if err != nil {
+			klog.Errorf("get snapshot reserved space of volume %s failed: %+v", snapshot.Spec.OriginVolName, err)
+			continue
+		}
+		// the snapshot can use the reserved space which is not used by other snapshots of the origin volume
+		var limit uint64
+		var others = reservedUsed[originVolumeKey(snapshot)]
+		if inReservedSpace(snapshot) {
+			others -= uint64(snapshot.Spec.Size)
+		}
+		if reserved > others {
+			limit = reserved - others
+		}
+		target, ok := snapshotExtendTarget(usage, ss.autoExtend, free, limit)
+		if !ok {
+			klog.Warningf("snapshot %s is %.2f%% full, but cannot be extended. size %d, origin size %d, VG free %d, reserved space left %d",
+				snapshot.Name, usage.DataPercent, usage.SizeByte, usage.OriginSizeByte, free, limit)
+			continue
+		}
+		klog.Infof("snapshot %s is %.2f%% full, extend it from %d to %d", snapshot.Name, usage.DataPercent, usage.SizeByte, target)
+		err = poolEng.ExpandVolume(engine.ExpandVolumeRequest{
+			VolName:    lvName,
+			TargetSize: target,
+			OriginSize: usage.SizeByte,
+		})
+		if err != nil {
+			klog.Errorf("extend snapshot %s failed: %+v", snapshot.Name, err)
+			continue
+		}
+		free -= target - usage.SizeByte
+		metric.IncSnapshotExtend(labels)
+
+		// record the new size, so that it is counted in the reserved space by the controller
+		if err = ss.updateSnapshotSize(snapshot, int64(target)); err != nil {
+			klog.Errorf("update size of snapshot %s to %d failed: %+v", snapshot.Name, target, err)
+			continue
+		}
+		if inReservedSpace(snapshot) {
+			reservedUsed[originVolumeKey(snapshot)] += target - uint64(snapshot.Spec.Size)
+		}
+		snapshot.Spec.Size = int64(target)
+	}
+
+	// remove metrics of snapshots which are deleted
+	for labels := range ss.monitored {
+		if !monitored[labels] {
+			metric.DeleteSnapshotUsage(labels)
+		}
+	}
+	ss.monitored = monitored
+}
+
+// isCowSnapshot returns true if the snapshot is a COW snapshot LV, which is already cut and not merged.
+// Snapshots in error are included, so that invalidated snapshots are reported until they are deleted.
+func isCowSnapshot(snapshot *v1.AntstorSnapshot) bool {
+	if snapshot.Spec.VolType != v1.VolumeTypeKernelLVol || snapshot.Spec.IsThin || snapshot.Spec.KernelLvol.Name == "" {
+		return false
+	}
+	if !misc.InSliceString(v1.SnapshotFinalizer, snapshot.Finalizers) {
+		return false
+	}
+	switch snapshot.Status.Status {
+	case v1.SnapshotStatusCreating, v1.SnapshotStatusReady, v1.SnapshotStatusError:
+		return true
+	}
+	return false
+}
+
+// snapshotExtendTarget returns the new size of snapshot. It grows by cfg.ExtendPercent, and is limited by
+// cfg.MaxSizePercent of origin size, the free space of VG and limit, which is the snapshot reserved space it can use.
+// It returns false if the snapshot cannot grow.
+func snapshotExtendTarget(usage engine.SnapshotUsage, cfg config.SnapshotAutoExtend, free, limit uint64) (target uint64, ok bool) {
+	var (
+		fourMiB = uint64(util.FourMiB)
+		maxSize = usage.OriginSizeByte * uint64(cfg.MaxSizePercent) / 100
+	)
+	target = usage.SizeByte + usage.SizeByte*uint64(cfg.ExtendPercent)/100
+	target = (target + fourMiB - 1) / fourMiB * fourMiB
+	if target > maxSize {
+		target = maxSize
+	}
+	if target > usage.SizeByte+free {
+		target = usage.SizeByte + free/fourMiB*fourMiB
+	}
+	if target > limit {
+		target = limit / fourMiB * fourMiB
+	}
+	return target, target > usage.SizeByte
+}
+
+// snapshotReservedSize returns the snapshot reserved space of the origin volume of snapshot
+func (ss *SnapshotSyncer) snapshotReservedSize(snapshot *v1.AntstorSnapshot) (reserved uint64, err error) {
+	volume, err := ss.storeCli.VolumeV1().AntstorVolumes(snapshot.Spec.OriginVolNamespace).Get(context.Background(), snapshot.Spec.OriginVolName, metav1.GetOptions{})
+	if err != nil {
+		return
+	}
+	val, has := volume.Annotations[v1.SnapshotReservedSpaceAnnotationKey]
+	if !has {
+		return 0, nil
+	}
+	return strconv.ParseUint(val, 10, 64)
+}
+
+// updateSnapshotSize sets Spec.Size of the latest snapshot
+func (ss *SnapshotSyncer) updateSnapshotSize(snapshot *v1.AntstorSnapshot, size int64) (err error) {
+	var snapCli = ss.storeCli.VolumeV1().AntstorSnapshots(snapshot.Namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := snapCli.Get(context.Background(), snapshot.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		latest.Spec.Size = size
+		_, err = snapCli.Update(context.Background(), latest, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// inReservedSpace returns true if the snapshot takes the snapshot reserved space of its origin volume.
+// Temporary snapshots for volume cloning are not counted, same as the controller.
+func inReservedSpace(snapshot *v1.AntstorSnapshot) bool {
+	_, isCopySource := snapshot.Labels[v1.SnapshotCopyForLabelKey]
+	return !isCopySource && snapshot.Status.Status != v1.SnapshotStatusMerged
+}
+
+func originVolumeKey(snapshot *v1.AntstorSnapshot) string {
+	return snapshot.Spec.OriginVolNamespace + "/" + snapshot.Spec.OriginVolName
+}
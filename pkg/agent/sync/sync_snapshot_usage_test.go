@@ -0,0 +1,79 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"lite.io/liteio/pkg/agent/config"
+	"lite.io/liteio/pkg/agent/pool/engine"
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+)
+
+func TestSnapshotExtendTarget(t *testing.T) {
+	var cfg config.SnapshotAutoExtend
+	config.SetSnapshotAutoExtendDefaults(&cfg)
+	usage := engine.SnapshotUsage{
+		SizeByte:       1 << 30,
+		OriginSizeByte: 10 << 30,
+		DataPercent:    85,
+	}
+
+	// grow by 20%, aligned up to 4MiB
+	target, ok := snapshotExtendTarget(usage, cfg, 100<<30, 100<<30)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1232<<20), target)
+
+	// limited by VG free space
+	target, ok = snapshotExtendTarget(usage, cfg, 10<<20, 100<<30)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1<<30+8<<20), target)
+
+	// no VG free space
+	_, ok = snapshotExtendTarget(usage, cfg, 1<<20, 100<<30)
+	assert.False(t, ok)
+
+	// limited by the snapshot reserved space left, aligned down to 4MiB
+	target, ok = snapshotExtendTarget(usage, cfg, 100<<30, 1<<30+10<<20)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1<<30+8<<20), target)
+
+	// no snapshot reserved space left
+	_, ok = snapshotExtendTarget(usage, cfg, 100<<30, 1<<30)
+	assert.False(t, ok)
+
+	// limited by origin size
+	usage.SizeByte = 9 << 30
+	target, ok = snapshotExtendTarget(usage, cfg, 100<<30, 100<<30)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(10<<30), target)
+
+	// already at the cap
+	usage.SizeByte = 10 << 30
+	_, ok = snapshotExtendTarget(usage, cfg, 100<<30, 100<<30)
+	assert.False(t, ok)
+}
+
+func TestIsCowSnapshot(t *testing.T) {
+	snapshot := &v1.AntstorSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Finalizers: []string{v1.SnapshotFinalizer}},
+		Spec: v1.AntstorSnapshotSpec{
+			VolType:    v1.VolumeTypeKernelLVol,
+			KernelLvol: v1.KernelLvol{Name: "snap-1"},
+		},
+		Status: v1.AntstorSnapshotStatus{Status: v1.SnapshotStatusReady},
+	}
+	assert.True(t, isCowSnapshot(snapshot))
+
+	// invalidated snapshot is reported until it is deleted
+	snapshot.Status.Status = v1.SnapshotStatusError
+	assert.True(t, isCowSnapshot(snapshot))
+
+	snapshot.Status.Status = v1.SnapshotStatusMerged
+	assert.False(t, isCowSnapshot(snapshot))
+
+	snapshot.Status.Status = v1.SnapshotStatusReady
+	snapshot.Spec.IsThin = true
+	assert.False(t, isCowSnapshot(snapshot))
+}
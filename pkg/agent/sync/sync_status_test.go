@@ -3,9 +3,12 @@ package sync
 import (
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestStatusCompare(t *testing.T) {
@@ -23,3 +26,25 @@ func TestStatusCompare(t *testing.T) {
 
 	t.Log(reflect.DeepEqual(status, dupStatus))
 }
+
+func TestIOStatsUpToDate(t *testing.T) {
+	var now = time.Now()
+	api := &v1.PoolIOStats{ReadIOPS: 100, UpdateTime: metav1.NewTime(now)}
+	real := api.DeepCopy()
+
+	assert.True(t, ioStatsUpToDate(nil, nil))
+	assert.False(t, ioStatsUpToDate(real, nil))
+
+	// only UpdateTime is changed
+	real.UpdateTime = metav1.NewTime(now.Add(time.Minute))
+	assert.True(t, ioStatsUpToDate(real, api))
+
+	// load is changed
+	real.ReadIOPS = 200
+	assert.False(t, ioStatsUpToDate(real, api))
+
+	// same load, but stats in APIServer are about to expire
+	real.ReadIOPS = 100
+	real.UpdateTime = metav1.NewTime(now.Add(ioStatsRefreshInterval))
+	assert.False(t, ioStatsUpToDate(real, api))
+}
@@ -48,14 +48,21 @@ type PoolSyncer struct {
 	// read node info from APIServer
 	nodeGetter kubeutil.NodeInfoGetterIface
 	cfg        config.Config
+	// ioStats provides IO load of the pool. It is optional.
+	ioStats PoolIOStatsGetter
 }
 
-func NewPoolSyncer(poolService pool.StoragePoolServiceIface, storeCli versioned.Interface, nodeGetter kubeutil.NodeInfoGetterIface, cfg config.Config) *PoolSyncer {
+type PoolIOStatsGetter interface {
+	Summary() *v1.PoolIOStats
+}
+
+func NewPoolSyncer(poolService pool.StoragePoolServiceIface, storeCli versioned.Interface, nodeGetter kubeutil.NodeInfoGetterIface, cfg config.Config, ioStats PoolIOStatsGetter) *PoolSyncer {
 	return &PoolSyncer{
 		poolService: poolService,
 		storeCli:    storeCli,
 		nodeGetter:  nodeGetter,
 		cfg:         cfg,
+		ioStats:     ioStats,
 	}
 }
 
@@ -275,6 +282,11 @@ func (ps *PoolSyncer) updatePoolStatus() (err error) {
 	// update pool's status to truth
 	setStatusConditions(pool, ps.poolService)
 	errVG := setStatusVgFree(pool, ps.poolService)
+	if ps.ioStats != nil {
+		if stats := ps.ioStats.Summary(); stats != nil {
+			pool.Status.IOStats = stats
+		}
+	}
 
 	realStatus := pool.Status.DeepCopy()
 
@@ -301,14 +313,16 @@ func (ps *PoolSyncer) updatePoolStatus() (err error) {
 	var condEqual = reflect.DeepEqual(realStatus.Conditions, apiPool.Status.Conditions)
 	var freeByteEqual = realStatus.VGFreeSize.Equal(apiPool.Status.VGFreeSize)
 	var totalByteEqual = realStatus.Capacity[v1.ResourceDiskPoolByte].Equal(apiPool.Status.Capacity[v1.ResourceDiskPoolByte])
+	var ioStatsEqual = ioStatsUpToDate(realStatus.IOStats, apiPool.Status.IOStats)
 
-	if !condEqual || !freeByteEqual || !totalByteEqual {
+	if !condEqual || !freeByteEqual || !totalByteEqual || !ioStatsEqual {
 		// to update status
 		klog.Infof("update StoragePool condition and cap, %+v, server-side status is %+v", *realStatus, apiPool.Status)
 		apiPool.Status.Conditions = realStatus.Conditions
 		apiPool.Status.VGFreeSize = realStatus.VGFreeSize.DeepCopy()
 		apiPool.Status.VGVirtualFreeSize = realStatus.VGVirtualFreeSize.DeepCopy()
 		apiPool.Status.Capacity[v1.ResourceDiskPoolByte] = realStatus.Capacity[v1.ResourceDiskPoolByte]
+		apiPool.Status.IOStats = realStatus.IOStats
 		// APIServer is supposed to check resourceVersion before updating the data.
 		// https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#concurrency-control-and-consistency
 		// https://stackoverflow.com/questions/52910322/kubernetes-resource-versioning
@@ -326,6 +340,21 @@ func (ps *PoolSyncer) updatePoolStatus() (err error) {
 	return
 }
 
+// ioStatsRefreshInterval is the max age of IOStats in APIServer. IOStats older than it are updated even if the load is not changed,
+// so that scheduler does not regard them as expired.
+const ioStatsRefreshInterval = v1.PoolIOStatsExpiration / 2
+
+// ioStatsUpToDate returns true if IOStats in APIServer have the same load as real, and are refreshed recently
+func ioStatsUpToDate(real, api *v1.PoolIOStats) bool {
+	if !real.EqualLoad(api) {
+		return false
+	}
+	if real == nil {
+		return true
+	}
+	return real.UpdateTime.Sub(api.UpdateTime.Time) < ioStatsRefreshInterval
+}
+
 func setStatusConditions(pool *v1.StoragePool, poolSvc pool.StoragePoolServiceIface) {
 	// get spdk condition
 	var status v1.ConditionStatus = v1.StatusError
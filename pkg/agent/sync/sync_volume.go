@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
 	"lite.io/liteio/pkg/agent/metric"
 	"lite.io/liteio/pkg/agent/pool"
@@ -35,6 +36,7 @@ import (
 	"lite.io/liteio/pkg/util/misc"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 )
@@ -45,15 +47,23 @@ type VolumeSyncer struct {
 	poolService pool.StoragePoolServiceIface
 	// storeCli is used to read/write StoragePool, AntstorVolumes from APIServer
 	storeCli versioned.Interface
-	lister   metric.MetricTargetListerIface
+	// kubeCli is used to read credentials of object storage to restore volume from backup
+	kubeCli kubernetes.Interface
+	lister  metric.MetricTargetListerIface
+
+	// copies are the cancel funcs of running data copies, key is volume name
+	copies   map[string]context.CancelFunc
+	copyLock sync.Mutex
 }
 
-func NewVolumeSyncer(storeCli versioned.Interface, poolSvc pool.StoragePoolServiceIface, lister metric.MetricTargetListerIface) *VolumeSyncer {
+func NewVolumeSyncer(storeCli versioned.Interface, kubeCli kubernetes.Interface, poolSvc pool.StoragePoolServiceIface, lister metric.MetricTargetListerIface) *VolumeSyncer {
 	return &VolumeSyncer{
 		nodeID:      poolSvc.GetStoragePool().Name,
 		poolService: poolSvc,
 		storeCli:    storeCli,
+		kubeCli:     kubeCli,
 		lister:      lister,
+		copies:      make(map[string]context.CancelFunc),
 	}
 }
 
@@ -143,6 +153,12 @@ func (vs *VolumeSyncer) syncOneVolume(volume *v1.AntstorVolume) (err error) {
 		return
 	}
 
+	// copy data from the source volume
+	needReturn, err = vs.copyData(volume)
+	if err != nil || needReturn {
+		return
+	}
+
 	// create openaccess
 	needReturn, err = vs.createOpenAccess(volume)
 	if err != nil || needReturn {
@@ -235,6 +251,13 @@ func (vs *VolumeSyncer) expandVolume(vol *v1.AntstorVolume) (err error) {
 }
 
 func (vs *VolumeSyncer) handleDeletion(volume *v1.AntstorVolume) (err error) {
+	// stop copying data and release the temporary snapshot of source volume
+	vs.cancelCopy(volume.Name)
+	if err = vs.releaseCopySource(volume); err != nil {
+		klog.Error(err)
+		return
+	}
+
 	// TODO: reconsider deletion constraint
 	// delete tgt
 	if misc.InSliceString(v1.SpdkTargetFinalizer, volume.Finalizers) ||
@@ -372,12 +395,16 @@ func (vs *VolumeSyncer) createVolume(volume *v1.AntstorVolume) (needReturn bool,
 		hasSnapName, hasSnapNS bool
 		fromSnap               bool
 		snapName, snapNS       string
+		// source volume of clone
+		fromVol              bool
+		srcVolNS, srcVolName string
 		// fsType for LVM
 		fsType string
 		// lv layout
 		lvLayout v1.LVLayout
 	)
 
+	srcVolNS, srcVolName, fromVol = cloneSourceOf(volume)
 	snapName, hasSnapName = volume.Labels[v1.VolumeSourceSnapNameLabelKey]
 	snapNS, hasSnapNS = volume.Labels[v1.VolumeSourceSnapNamespaceLabelKey]
 	fromSnap = hasSnapName && hasSnapNS
@@ -404,13 +431,7 @@ func (vs *VolumeSyncer) createVolume(volume *v1.AntstorVolume) (needReturn bool,
 
 	switch volume.Spec.Type {
 	case v1.VolumeTypeKernelLVol:
-		// Only Spdklvs volume can specify VolumeContentSource
-		if fromSnap {
-			// TODO: update volume status message
-			err = fmt.Errorf("not support creating new lvm volume from a lvm snapshot yet")
-			klog.Error(err)
-			return
-		}
+		// for volume restored from a snapshot, data is copied after the LV is created
 
 		// validate lv layout
 
@@ -447,6 +468,13 @@ func (vs *VolumeSyncer) createVolume(volume *v1.AntstorVolume) (needReturn bool,
 				klog.Error(err, uuid)
 				return
 			}
+		} else if fromVol {
+			klog.Infof("cloning spdk lvol for vol %s from volume %s/%s", volume.Name, srcVolNS, srcVolName)
+			err = vs.cloneSpdkLvol(volume, srcVolNS, srcVolName)
+			if err != nil {
+				klog.Error(err)
+				return
+			}
 		} else {
 			// create new volume
 			req = engine.CreateVolumeRequest{
@@ -0,0 +1,592 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"lite.io/liteio/pkg/agent/pool/engine"
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/spdk"
+	"lite.io/liteio/pkg/spdk/jsonrpc/nvme"
+	"lite.io/liteio/pkg/util"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+// cloneSourceOf returns the namespace and name of the source volume, if the volume is cloned from another volume
+func cloneSourceOf(volume *v1.AntstorVolume) (ns, name string, ok bool) {
+	name, hasName := volume.Labels[v1.VolumeSourceVolNameLabelKey]
+	ns, hasNS := volume.Labels[v1.VolumeSourceVolNamespaceLabelKey]
+	ok = hasName && hasNS
+	return
+}
+
+// maxDataCopyRetries is the max count of copies started again after failure
+const maxDataCopyRetries = 3
+
+// dataCopyRetryBaseBackoff is the backoff before the first retry. It doubles for each retry.
+var dataCopyRetryBaseBackoff = 30 * time.Second
+
+// copySourceSnapshotName is the name of temporary snapshot of source volume, which data is copied from
+func copySourceSnapshotName(volName string) string {
+	return volName + "-clone-src"
+}
+
+// cloneSpdkLvol creates the lvol of volume by cloning a temporary snapshot of the source lvol.
+// The clone is inflated, so it does not depend on the snapshot, which is deleted at last.
+func (vs *VolumeSyncer) cloneSpdkLvol(volume *v1.AntstorVolume, srcNS, srcName string) (err error) {
+	var (
+		lvsName  = vs.poolService.GetStoragePool().Spec.SpdkLVStore.Name
+		snapName = fmt.Sprintf("%s_clone_src", volume.Name)
+		srcVol   *v1.AntstorVolume
+		exists   bool
+	)
+
+	srcVol, err = vs.storeCli.VolumeV1().AntstorVolumes(srcNS).Get(context.Background(), srcName, metav1.GetOptions{})
+	if err != nil {
+		return
+	}
+	if srcVol.Spec.SpdkLvol == nil || srcVol.Spec.SpdkLvol.LvsName != lvsName {
+		return fmt.Errorf("source volume %s/%s is not in lvstore %s", srcNS, srcName, lvsName)
+	}
+
+	err = vs.updateDataCopy(volume, func(dc *v1.DataCopyStatus) {
+		dc.Phase = v1.DataCopyPhaseCopying
+		dc.SourceVolume = srcNS + "/" + srcName
+		dc.TotalBytes = int64(srcVol.Spec.SizeByte)
+		dc.StartTime = &metav1.Time{Time: time.Now()}
+	})
+	if err != nil {
+		return
+	}
+
+	// creating snapshot and clone is retried by the next sync, so skip the existing lvols
+	if exists, err = vs.spdkLvolExists(snapName); err != nil {
+		return
+	}
+	if !exists {
+		klog.Infof("creating snapshot %s of source lvol %s", snapName, srcVol.Spec.SpdkLvol.FullName())
+		_, err = vs.poolService.SpdkService().CreateLvolSnapshot(spdk.CreateLvolSnapReq{
+			LvolFullName: srcVol.Spec.SpdkLvol.FullName(),
+			SnapName:     snapName,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	if exists, err = vs.spdkLvolExists(volume.Name); err != nil {
+		return
+	}
+	if !exists {
+		klog.Infof("cloning lvol %s from snapshot %s", volume.Name, snapName)
+		_, err = vs.poolService.SpdkService().CreateLvolClone(spdk.CreateLvolCloneReq{
+			LVStore:   lvsName,
+			SnapName:  snapName,
+			CloneName: volume.Name,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	klog.Infof("inflating cloned lvol %s", volume.Name)
+	err = vs.poolService.SpdkService().InflateLvol(spdk.InflateLvolReq{
+		LVStore:  lvsName,
+		LvolName: volume.Name,
+	})
+	if err != nil {
+		return
+	}
+
+	err = vs.poolService.SpdkService().DeleteLvol(spdk.DeleteLvolReq{
+		LVStore:  lvsName,
+		LvolName: snapName,
+	})
+	if err != nil {
+		return
+	}
+
+	// clone has the same size as the source lvol
+	if volume.Spec.SizeByte > srcVol.Spec.SizeByte {
+		err = vs.poolService.PoolEngine().ExpandVolume(engine.ExpandVolumeRequest{
+			VolName:    fmt.Sprintf("%s/%s", lvsName, volume.Name),
+			TargetSize: volume.Spec.SizeByte,
+			OriginSize: srcVol.Spec.SizeByte,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	return vs.updateDataCopy(volume, func(dc *v1.DataCopyStatus) {
+		dc.Phase = v1.DataCopyPhaseFinished
+		dc.CopiedBytes = dc.TotalBytes
+		dc.CompletionTime = &metav1.Time{Time: time.Now()}
+	})
+}
+
+func (vs *VolumeSyncer) spdkLvolExists(name string) (exists bool, err error) {
+	var vol engine.VolumeInfo
+	vol, err = vs.poolService.PoolEngine().GetVolume(name)
+	if err != nil {
+		if spdk.IsNotFoundDeviceError(err) {
+			return false, nil
+		}
+		return
+	}
+	return vol.SpdkLvol != nil, nil
+}
+
+// copySourceOf returns the snapshot which data of the LVM volume is copied from.
+// For volume cloned from another volume, the snapshot is a temporary one created by the agent.
+func copySourceOf(volume *v1.AntstorVolume) (snapNS, snapName string, temporary, ok bool) {
+	if volume.Spec.Type != v1.VolumeTypeKernelLVol {
+		return
+	}
+	if ns, _, isClone := cloneSourceOf(volume); isClone {
+		return ns, copySourceSnapshotName(volume.Name), true, true
+	}
+	snapName, hasSnapName := volume.Labels[v1.VolumeSourceSnapNameLabelKey]
+	snapNS, hasSnapNS := volume.Labels[v1.VolumeSourceSnapNamespaceLabelKey]
+	ok = hasSnapName && hasSnapNS
+	return
+}
+
+// copyData copies data of a snapshot to the LVM volume, which is restored from the snapshot or cloned from another volume.
+// For cloning, a temporary snapshot of the source volume is created. If the snapshot is on another node, it is exported by NVMe-oF.
+// The snapshot is protected by DataCopySourceFinalizer during copy.
+// Data is copied in background, and the progress is updated to Status.DataCopy.
+// Volume restored from a SnapshotBackup downloads data from object storage instead.
+func (vs *VolumeSyncer) copyData(volume *v1.AntstorVolume) (needReturn bool, err error) {
+	if backupNS, backupName, ok := restoreSourceOf(volume); ok {
+		return vs.restoreData(volume, backupNS, backupName)
+	}
+
+	snapNS, snapName, temporary, ok := copySourceOf(volume)
+	if !ok {
+		return
+	}
+
+	var dc = volume.Status.DataCopy
+	if dc != nil {
+		switch dc.Phase {
+		case v1.DataCopyPhaseFinished:
+			return
+		case v1.DataCopyPhaseFailed:
+			klog.Errorf("copying data to volume %s failed: %s", volume.Name, dc.Message)
+			return true, nil
+		}
+	}
+
+	if vs.isCopying(volume.Name) {
+		klog.Infof("data of volume %s is being copied", volume.Name)
+		return true, nil
+	}
+
+	var (
+		snapCli = vs.storeCli.VolumeV1().AntstorSnapshots(snapNS)
+		owner   = volume.Namespace + "/" + volume.Name
+		snap    *v1.AntstorSnapshot
+		srcDev  string
+		total   int64
+	)
+	snap, err = snapCli.Get(context.Background(), snapName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if temporary {
+			srcNS, srcName, _ := cloneSourceOf(volume)
+			err = vs.createCopySourceSnapshot(volume, srcNS, srcName, snapName)
+			return true, err
+		}
+		return true, vs.updateDataCopy(volume, func(dc *v1.DataCopyStatus) {
+			dc.Phase = v1.DataCopyPhaseFailed
+			dc.Message = fmt.Sprintf("snapshot %s/%s is not found", snapNS, snapName)
+		})
+	}
+	if err != nil {
+		return
+	}
+
+	// e.g. COW space of the snapshot is full, its data is lost
+	if snap.Status.Status == v1.SnapshotStatusError {
+		return true, vs.updateDataCopy(volume, func(dc *v1.DataCopyStatus) {
+			dc.Phase = v1.DataCopyPhaseFailed
+			dc.Message = fmt.Sprintf("snapshot %s/%s is in error: %s", snapNS, snapName, snap.Status.Message)
+		})
+	}
+	if snap.Status.Status != v1.SnapshotStatusReady {
+		// return error to requeue the volume
+		return true, fmt.Errorf("waiting for snapshot %s/%s to be ready, status %q", snapNS, snapName, snap.Status.Status)
+	}
+
+	if snap.Annotations[v1.DataCopyOwnerAnnoKey] != owner {
+		err = vs.acquireCopySource(volume, snap)
+		return true, err
+	}
+
+	srcDev, err = vs.connectCopySource(snap)
+	if err != nil {
+		return true, err
+	}
+
+	// copy the smaller one of the snapshot and the volume. The volume is not smaller than the source, unless the snapshot is aligned up by LVM.
+	total, err = deviceSize(srcDev)
+	if err != nil {
+		return true, err
+	}
+	if total > int64(volume.Spec.SizeByte) {
+		total = int64(volume.Spec.SizeByte)
+	}
+
+	err = vs.updateDataCopy(volume, func(dc *v1.DataCopyStatus) {
+		dc.Phase = v1.DataCopyPhaseCopying
+		dc.SourceVolume = snap.Spec.OriginVolNamespace + "/" + snap.Spec.OriginVolName
+		dc.SourceSnapshot = snapNS + "/" + snapName
+		dc.TotalBytes = total
+		dc.CopiedBytes = 0
+		dc.Message = ""
+		dc.StartTime = &metav1.Time{Time: time.Now()}
+	})
+	if err != nil {
+		return true, err
+	}
+
+	var (
+		vol         = volume.DeepCopy()
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+	vs.copyLock.Lock()
+	vs.copies[volume.Name] = cancel
+	vs.copyLock.Unlock()
+
+	klog.Infof("start copying data of volume %s from %s to %s", volume.Name, srcDev, vol.Spec.KernelLvol.DevPath)
+	go vs.runCopy(ctx, vol, srcDev)
+
+	return true, nil
+}
+
+// acquireCopySource sets the volume as the copy owner of snapshot. The snapshot is exported to this node if it is on another node.
+func (vs *VolumeSyncer) acquireCopySource(volume *v1.AntstorVolume, snap *v1.AntstorSnapshot) (err error) {
+	var owner = volume.Namespace + "/" + volume.Name
+	if current := snap.Annotations[v1.DataCopyOwnerAnnoKey]; current != "" {
+		return fmt.Errorf("snapshot %s/%s is being copied to volume %s, wait for it to finish", snap.Namespace, snap.Name, current)
+	}
+	if snap.Spec.VolType != v1.VolumeTypeKernelLVol {
+		return fmt.Errorf("snapshot %s/%s is %s, cannot be copied to LVM volume", snap.Namespace, snap.Name, snap.Spec.VolType)
+	}
+
+	if snap.Annotations == nil {
+		snap.Annotations = make(map[string]string)
+	}
+	snap.Annotations[v1.DataCopyOwnerAnnoKey] = owner
+	if !misc.InSliceString(v1.DataCopySourceFinalizer, snap.Finalizers) {
+		snap.Finalizers = append(snap.Finalizers, v1.DataCopySourceFinalizer)
+	}
+	snap.Spec.ExportToNodeID = ""
+	if snap.Spec.OriginVolTargetNodeID != vs.nodeID {
+		snap.Spec.ExportToNodeID = vs.nodeID
+	}
+
+	klog.Infof("copying data of snapshot %s/%s to volume %s, export to node %q", snap.Namespace, snap.Name, owner, snap.Spec.ExportToNodeID)
+	_, err = vs.storeCli.VolumeV1().AntstorSnapshots(snap.Namespace).Update(context.Background(), snap, metav1.UpdateOptions{})
+	if err != nil {
+		return
+	}
+
+	return vs.updateDataCopy(volume, func(dc *v1.DataCopyStatus) {
+		dc.Phase = v1.DataCopyPhasePending
+		dc.SourceVolume = snap.Spec.OriginVolNamespace + "/" + snap.Spec.OriginVolName
+		dc.SourceSnapshot = snap.Namespace + "/" + snap.Name
+	})
+}
+
+func (vs *VolumeSyncer) createCopySourceSnapshot(volume *v1.AntstorVolume, srcNS, srcName, snapName string) (err error) {
+	var srcVol *v1.AntstorVolume
+	srcVol, err = vs.storeCli.VolumeV1().AntstorVolumes(srcNS).Get(context.Background(), srcName, metav1.GetOptions{})
+	if err != nil {
+		return
+	}
+	if srcVol.Spec.Type != v1.VolumeTypeKernelLVol {
+		return fmt.Errorf("source volume %s/%s is %s, cannot be copied to LVM volume", srcNS, srcName, srcVol.Spec.Type)
+	}
+
+	// snapshot only keeps the changed blocks of source volume during copy. Use reserved space of snapshot if it is set.
+	var size = int64(srcVol.Spec.SizeByte)
+	if val, has := srcVol.Annotations[v1.SnapshotReservedSpaceAnnotationKey]; has {
+		if reserved, errParse := strconv.ParseInt(val, 10, 64); errParse == nil && reserved >= util.FourMiB {
+			size = reserved
+		}
+	}
+	size = size / util.FourMiB * util.FourMiB
+
+	var snap = &v1.AntstorSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: srcNS,
+			Name:      snapName,
+			Labels: map[string]string{
+				v1.OriginVolumeNameLabelKey:      srcName,
+				v1.OriginVolumeNamespaceLabelKey: srcNS,
+				v1.SnapshotCopyForLabelKey:       volume.Name,
+			},
+			Annotations: map[string]string{
+				v1.DataCopyOwnerAnnoKey: volume.Namespace + "/" + volume.Name,
+			},
+			Finalizers: []string{v1.DataCopySourceFinalizer},
+		},
+		Spec: v1.AntstorSnapshotSpec{
+			Uuid:               uuid.NewV4().String(),
+			OriginVolName:      srcName,
+			OriginVolNamespace: srcNS,
+			VolType:            srcVol.Spec.Type,
+			Size:               size,
+		},
+	}
+	if srcVol.Spec.TargetNodeId != vs.nodeID {
+		snap.Spec.ExportToNodeID = vs.nodeID
+	}
+
+	klog.Infof("creating snapshot %s/%s to clone volume %s, size %d", srcNS, snapName, volume.Name, size)
+	_, err = vs.storeCli.VolumeV1().AntstorSnapshots(srcNS).Create(context.Background(), snap, metav1.CreateOptions{})
+	if err != nil {
+		return
+	}
+
+	return vs.updateDataCopy(volume, func(dc *v1.DataCopyStatus) {
+		dc.Phase = v1.DataCopyPhasePending
+		dc.SourceVolume = srcNS + "/" + srcName
+		dc.SourceSnapshot = srcNS + "/" + snapName
+	})
+}
+
+// connectCopySource returns the device path of snapshot. Remote snapshot is connected by NVMe-oF.
+func (vs *VolumeSyncer) connectCopySource(snap *v1.AntstorSnapshot) (devPath string, err error) {
+	if snap.Spec.ExportToNodeID == "" {
+		return snap.Spec.KernelLvol.DevPath, nil
+	}
+
+	var (
+		target     = snap.Status.ExportTarget
+		nvmeCli    = nvme.NewClientWithCmdPath(nvmeClientFilePath)
+		subsysList nvme.SubsystemList
+		nvmeList   []nvme.NvmeDevice
+		connected  bool
+		out        []byte
+	)
+	if target == nil {
+		return "", fmt.Errorf("snapshot %s is not exported yet", snap.Name)
+	}
+
+	subsysList, err = nvmeCli.ListSubsystems()
+	if err != nil {
+		return
+	}
+	for _, subsys := range subsysList.Subsystems {
+		if subsys.NQN == target.SubsysNQN {
+			connected = true
+		}
+	}
+	if !connected {
+		out, err = nvmeCli.ConnectTarget(strings.ToLower(target.TransType), target.Address, target.SvcID, target.SubsysNQN, nvme.ConnectTargetOpts{
+			ReconnectDelaySec: 2,
+			CtrlLossTMO:       10,
+		})
+		if err != nil {
+			klog.Error(err, string(out))
+			return
+		}
+		klog.Infof("connect snapshot target %+v, output %s", *target, string(out))
+	}
+
+	nvmeList, err = nvmeCli.ListNvmeDisk()
+	if err != nil {
+		return
+	}
+	for _, item := range nvmeList {
+		if item.SerialNumber == target.SerialNum {
+			return item.DevicePath, nil
+		}
+	}
+
+	return "", fmt.Errorf("not found device of snapshot target %+v", *target)
+}
+
+// runCopy copies data from srcDev to the volume. If copying fails, e.g. the NVMe-oF connection to the snapshot is broken,
+// the phase is set to Pending after a backoff, so that copyData connects the snapshot and copies again.
+// After maxDataCopyRetries, the phase is Failed and the snapshot is released.
+func (vs *VolumeSyncer) runCopy(ctx context.Context, volume *v1.AntstorVolume, srcDev string) {
+	var (
+		total   = volume.Status.DataCopy.TotalBytes
+		retries = volume.Status.DataCopy.Retries
+	)
+	err := blockCopy(ctx, srcDev, volume.Spec.KernelLvol.DevPath, total, func(copied int64) {
+		errUpdate := vs.updateDataCopy(volume, func(dc *v1.DataCopyStatus) {
+			dc.CopiedBytes = copied
+		})
+		if errUpdate != nil {
+			klog.Error(errUpdate)
+		}
+	})
+
+	// volume is still being copied during backoff, so copyData does not start another copy
+	var retry = err != nil && ctx.Err() == nil && retries < maxDataCopyRetries
+	if retry {
+		backoff := dataCopyRetryBackoff(retries)
+		klog.Errorf("copying data of volume %s failed: %+v, retry in %s", volume.Name, err, backoff)
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+	}
+
+	vs.copyLock.Lock()
+	delete(vs.copies, volume.Name)
+	vs.copyLock.Unlock()
+
+	// volume is being deleted
+	if ctx.Err() != nil {
+		klog.Infof("copying data of volume %s is canceled", volume.Name)
+		return
+	}
+
+	// snapshot is kept acquired for the retry
+	if !retry {
+		if errRelease := vs.releaseCopySource(volume); errRelease != nil {
+			klog.Error(errRelease)
+		}
+	}
+
+	if err == nil {
+		klog.Infof("copying data of volume %s is finished", volume.Name)
+	} else if !retry {
+		klog.Errorf("copying data of volume %s failed: %+v", volume.Name, err)
+	}
+	errUpdate := vs.updateDataCopy(volume, func(dc *v1.DataCopyStatus) {
+		if err != nil {
+			dc.Phase = v1.DataCopyPhaseFailed
+			if retry {
+				dc.Phase = v1.DataCopyPhasePending
+				dc.Retries = retries + 1
+			}
+			dc.Message = err.Error()
+			return
+		}
+		dc.Phase = v1.DataCopyPhaseFinished
+		dc.CopiedBytes = total
+		dc.Message = ""
+		dc.CompletionTime = &metav1.Time{Time: time.Now()}
+	})
+	if errUpdate != nil {
+		klog.Error(errUpdate)
+	}
+}
+
+func dataCopyRetryBackoff(retries int32) time.Duration {
+	return dataCopyRetryBaseBackoff << retries
+}
+
+func (vs *VolumeSyncer) isCopying(volName string) bool {
+	vs.copyLock.Lock()
+	defer vs.copyLock.Unlock()
+	_, has := vs.copies[volName]
+	return has
+}
+
+// cancelCopy stops copying data to the volume
+func (vs *VolumeSyncer) cancelCopy(volName string) {
+	vs.copyLock.Lock()
+	defer vs.copyLock.Unlock()
+	if cancel, has := vs.copies[volName]; has {
+		cancel()
+		delete(vs.copies, volName)
+	}
+}
+
+// releaseCopySource disconnects the snapshot and removes DataCopySourceFinalizer. The temporary snapshot is deleted. It is idempotent.
+func (vs *VolumeSyncer) releaseCopySource(volume *v1.AntstorVolume) (err error) {
+	snapNS, snapName, temporary, ok := copySourceOf(volume)
+	if !ok {
+		return
+	}
+
+	var (
+		snapCli = vs.storeCli.VolumeV1().AntstorSnapshots(snapNS)
+		snap    *v1.AntstorSnapshot
+	)
+	snap, err = snapCli.Get(context.Background(), snapName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return
+	}
+	// snapshot is acquired by another volume
+	if snap.Annotations[v1.DataCopyOwnerAnnoKey] != volume.Namespace+"/"+volume.Name {
+		return nil
+	}
+
+	if snap.Status.ExportTarget != nil && snap.Spec.ExportToNodeID == vs.nodeID {
+		var out []byte
+		// "nvme disconnect" command is reentrant. if NQN device is not connected, the command return 0 exit-code.
+		out, err = nvme.NewClientWithCmdPath(nvmeClientFilePath).DisconnectTarget(nvme.DisconnectTargetRequest{
+			NQN: snap.Status.ExportTarget.SubsysNQN,
+		})
+		if err != nil {
+			klog.Error(err, string(out))
+			return
+		}
+	}
+
+	var newFinalizers = make([]string, 0, len(snap.Finalizers))
+	for _, item := range snap.Finalizers {
+		if item != v1.DataCopySourceFinalizer {
+			newFinalizers = append(newFinalizers, item)
+		}
+	}
+	snap.Finalizers = newFinalizers
+	delete(snap.Annotations, v1.DataCopyOwnerAnnoKey)
+	// agent of the snapshot removes the export
+	snap.Spec.ExportToNodeID = ""
+	snap, err = snapCli.Update(context.Background(), snap, metav1.UpdateOptions{})
+	if err != nil {
+		return
+	}
+
+	if temporary && snap.DeletionTimestamp == nil {
+		klog.Infof("deleting snapshot %s/%s", snapNS, snapName)
+		err = snapCli.Delete(context.Background(), snapName, metav1.DeleteOptions{})
+		if errors.IsNotFound(err) {
+			err = nil
+		}
+	}
+
+	return
+}
+
+// updateDataCopy updates Status.DataCopy of the latest volume by fn, and sets the updated status back to volume
+func (vs *VolumeSyncer) updateDataCopy(volume *v1.AntstorVolume, fn func(dc *v1.DataCopyStatus)) (err error) {
+	var cli = vs.storeCli.VolumeV1().AntstorVolumes(volume.Namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := cli.Get(context.Background(), volume.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if latest.Status.DataCopy == nil {
+			latest.Status.DataCopy = &v1.DataCopyStatus{}
+		}
+		fn(latest.Status.DataCopy)
+		updated, err := cli.UpdateStatus(context.Background(), latest, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		volume.ResourceVersion = updated.ResourceVersion
+		volume.Status = updated.Status
+		return nil
+	})
+}
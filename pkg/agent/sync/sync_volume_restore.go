@@ -0,0 +1,166 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+)
+
+// restoreSourceOf returns the SnapshotBackup which data of the volume is restored from
+func restoreSourceOf(volume *v1.AntstorVolume) (ns, name string, ok bool) {
+	val, has := volume.Annotations[v1.VolumeSourceBackupAnnoKey]
+	if !has || val == "" {
+		return
+	}
+	ns, name = v1.ParseSourceBackup(val)
+	return ns, name, true
+}
+
+// restoreData downloads the chunks of a Completed SnapshotBackup to the volume.
+// Data is restored in background, and the progress is updated to Status.DataCopy.
+func (vs *VolumeSyncer) restoreData(volume *v1.AntstorVolume, backupNS, backupName string) (needReturn bool, err error) {
+	var dc = volume.Status.DataCopy
+	if dc != nil {
+		switch dc.Phase {
+		case v1.DataCopyPhaseFinished:
+			return
+		case v1.DataCopyPhaseFailed:
+			klog.Errorf("restoring data to volume %s failed: %s", volume.Name, dc.Message)
+			return true, nil
+		}
+	}
+
+	if vs.isCopying(volume.Name) {
+		klog.Infof("data of volume %s is being restored", volume.Name)
+		return true, nil
+	}
+
+	var (
+		source = backupNS + "/" + backupName
+		backup *v1.SnapshotBackup
+	)
+	backup, err = vs.storeCli.VolumeV1().SnapshotBackups(backupNS).Get(context.Background(), backupName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return true, vs.failDataCopy(volume, fmt.Sprintf("backup %s is not found", source))
+	}
+	if err != nil {
+		return
+	}
+	switch backup.Status.Phase {
+	case v1.SnapshotBackupPhaseCompleted:
+	case v1.SnapshotBackupPhaseFailed:
+		return true, vs.failDataCopy(volume, fmt.Sprintf("backup %s is failed: %s", source, backup.Status.Message))
+	default:
+		// return error to requeue the volume
+		return true, fmt.Errorf("waiting for backup %s to be completed, phase %q", source, backup.Status.Phase)
+	}
+	// CSI checks the namespace of PVC as well, but the annotation may be set on the volume directly
+	if backup.Status.PvcNamespace != volume.Labels[v1.VolumeContextKeyPvcNS] {
+		return true, vs.failDataCopy(volume, fmt.Sprintf("backup %s does not belong to namespace %q", source, volume.Labels[v1.VolumeContextKeyPvcNS]))
+	}
+	if backup.Status.SizeBytes > int64(volume.Spec.SizeByte) {
+		return true, vs.failDataCopy(volume, fmt.Sprintf("backup %s has %d bytes, larger than the volume", source, backup.Status.SizeBytes))
+	}
+
+	store, err := newBackupStore(vs.kubeCli, backup)
+	if err != nil {
+		return true, err
+	}
+	manifest, err := getBackupManifest(context.Background(), store, backup.Spec.Target.Bucket, backup.Status.ManifestKey)
+	if err != nil {
+		return true, err
+	}
+
+	err = vs.updateDataCopy(volume, func(dc *v1.DataCopyStatus) {
+		dc.Phase = v1.DataCopyPhaseCopying
+		dc.SourceBackup = source
+		dc.SourceSnapshot = manifest.Snapshot
+		dc.SourceVolume = manifest.Volume
+		dc.TotalBytes = manifest.SizeBytes
+		dc.CopiedBytes = 0
+		dc.Message = ""
+		dc.StartTime = &metav1.Time{Time: time.Now()}
+	})
+	if err != nil {
+		return true, err
+	}
+
+	var (
+		vol         = volume.DeepCopy()
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+	vs.copyLock.Lock()
+	vs.copies[volume.Name] = cancel
+	vs.copyLock.Unlock()
+
+	klog.Infof("start restoring data of volume %s from backup %s", volume.Name, source)
+	go vs.runRestore(ctx, vol, store, backup.Spec.Target, manifest)
+
+	return true, nil
+}
+
+func (vs *VolumeSyncer) runRestore(ctx context.Context, volume *v1.AntstorVolume, store objectStore, target v1.BackupTarget, manifest *backupManifest) {
+	var devPath, spdkLvolName string
+	if volume.Spec.KernelLvol != nil {
+		devPath = volume.Spec.KernelLvol.DevPath
+	}
+	if volume.Spec.SpdkLvol != nil {
+		spdkLvolName = volume.Spec.SpdkLvol.FullName()
+	}
+
+	dst, release, err := lvolDevice(vs.poolService, volume.Spec.Type, devPath, spdkLvolName)
+	if err == nil {
+		// unprovisioned blocks of thin LV are read as zero
+		err = restoreChunks(ctx, store, target.Bucket, target.Prefix, dst, manifest, volume.IsThinLVol(), func(written int64) {
+			errUpdate := vs.updateDataCopy(volume, func(dc *v1.DataCopyStatus) {
+				dc.CopiedBytes = written
+			})
+			if errUpdate != nil {
+				klog.Error(errUpdate)
+			}
+		})
+		release()
+	}
+
+	vs.copyLock.Lock()
+	delete(vs.copies, volume.Name)
+	vs.copyLock.Unlock()
+
+	// volume is being deleted
+	if ctx.Err() != nil {
+		klog.Infof("restoring data of volume %s is canceled", volume.Name)
+		return
+	}
+
+	if err != nil {
+		klog.Errorf("restoring data of volume %s failed: %+v", volume.Name, err)
+		if errUpdate := vs.failDataCopy(volume, err.Error()); errUpdate != nil {
+			klog.Error(errUpdate)
+		}
+		return
+	}
+
+	klog.Infof("restoring data of volume %s is finished", volume.Name)
+	errUpdate := vs.updateDataCopy(volume, func(dc *v1.DataCopyStatus) {
+		dc.Phase = v1.DataCopyPhaseFinished
+		dc.CopiedBytes = manifest.SizeBytes
+		dc.Message = ""
+		dc.CompletionTime = &metav1.Time{Time: time.Now()}
+	})
+	if errUpdate != nil {
+		klog.Error(errUpdate)
+	}
+}
+
+func (vs *VolumeSyncer) failDataCopy(volume *v1.AntstorVolume, msg string) error {
+	return vs.updateDataCopy(volume, func(dc *v1.DataCopyStatus) {
+		dc.Phase = v1.DataCopyPhaseFailed
+		dc.Message = msg
+	})
+}
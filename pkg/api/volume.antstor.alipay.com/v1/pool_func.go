@@ -20,6 +20,7 @@ package v1
 
 import (
 	"math"
+	"time"
 )
 
 // GetVgTotalBytes get total space of VolumeGroup in byte, including reserved space
@@ -135,3 +136,32 @@ func (sp *StoragePool) Mode() (mode PoolMode) {
 	}
 	return
 }
+
+// IOPS returns the sum of read and write IOPS
+func (s *PoolIOStats) IOPS() int64 {
+	return s.ReadIOPS + s.WriteIOPS
+}
+
+// Bps returns the sum of read and write bandwidth
+func (s *PoolIOStats) Bps() int64 {
+	return s.ReadBps + s.WriteBps
+}
+
+// EqualLoad returns true if s and other have the same IO load, regardless of UpdateTime
+func (s *PoolIOStats) EqualLoad(other *PoolIOStats) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return s.ReadIOPS == other.ReadIOPS && s.WriteIOPS == other.WriteIOPS &&
+		s.ReadBps == other.ReadBps && s.WriteBps == other.WriteBps &&
+		s.QueueTimeUs == other.QueueTimeUs
+}
+
+// FreshIOStats returns IOStats of the pool, or nil if IOStats are not updated within PoolIOStatsExpiration, e.g. the agent is down
+func (sp *StoragePool) FreshIOStats(now time.Time) *PoolIOStats {
+	var stats = sp.Status.IOStats
+	if stats == nil || now.Sub(stats.UpdateTime.Time) > PoolIOStatsExpiration {
+		return nil
+	}
+	return stats
+}
@@ -19,6 +19,8 @@
 package v1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -35,6 +37,10 @@ const (
 	AnnotationTgtSpdkVersion = "obnvmf/tgt-version"
 	// hostnqn Annotation key
 	AnnotationHostNQN = "obnvmf/hostnqn"
+
+	// PoolIOStatsExpiration is the age of IOStats, after which they are ignored by scheduler.
+	// Agent syncs status of pool every 2 minutes, so IOStats are expired after missing several syncs.
+	PoolIOStatsExpiration = 10 * time.Minute
 )
 
 const (
@@ -76,6 +82,21 @@ const (
 	// if Snapshot lvm is created, then this key is added to Finalizer.
 	SnapshotFinalizer = "antstor.alipay.com/snapshot"
 
+	// DataCopySourceFinalizer protects the snapshot from deletion while its data is copied to a volume
+	DataCopySourceFinalizer = "antstor.alipay.com/data-copy-source"
+
+	// VolumeFreezeFinalizer is added to the member snapshot of SnapshotGroup by the agent before freezing the origin volume.
+	// It is removed after the volume is thawed, so a frozen volume is always thawed even if the snapshot is deleted.
+	VolumeFreezeFinalizer = "antstor.alipay.com/volume-freeze"
+
+	// FsFreezeFinalizer is added to the snapshot by the CSI node plugin before freezing the filesystem of the origin volume.
+	// It is removed after the filesystem is thawed.
+	FsFreezeFinalizer = "antstor.alipay.com/fs-freeze"
+
+	// SnapshotBackupFinalizer is added to SnapshotBackup and its snapshot by the controller.
+	// It protects the snapshot from deletion until all backups of the snapshot are finished or deleted.
+	SnapshotBackupFinalizer = "antstor.alipay.com/snapshot-backup"
+
 	// VolumesFinalizer is added, if VolumeGroup owns volumes.
 	VolumesFinalizer = "antstor.alipay.com/volumes"
 
@@ -200,6 +221,23 @@ type StoragePoolStatus struct {
 
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// IOStats is the IO load of the pool, reported by agent
+	// +optional
+	IOStats *PoolIOStats `json:"ioStats,omitempty"`
+}
+
+// PoolIOStats is the rolling average of IO load on volumes of the pool
+type PoolIOStats struct {
+	ReadIOPS  int64 `json:"readIOPS"`
+	WriteIOPS int64 `json:"writeIOPS"`
+	// unit: byte per second
+	ReadBps  int64 `json:"readBps"`
+	WriteBps int64 `json:"writeBps"`
+	// average time of one IO in queue, unit: us
+	QueueTimeUs int64 `json:"queueTimeUs"`
+	// +optional
+	UpdateTime metav1.Time `json:"updateTime,omitempty"`
 }
 
 // +genclient
@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// QuotaNamespace returns the namespace of the PVC which the volume is created for
+func (vol *AntstorVolume) QuotaNamespace() string {
+	return vol.Labels[VolumeContextKeyPvcNS]
+}
+
+// QuotaUsage returns the resources used by the volume.
+// Placement of a volume is undetermined before it is scheduled, and Flexible type is resolved by agent.
+// If worstCase is true, undetermined size is charged to every possible dimension. Otherwise it is not charged.
+func (vol *AntstorVolume) QuotaUsage(worstCase bool) (used corev1.ResourceList) {
+	var size = int64(vol.Spec.SizeByte)
+	used = corev1.ResourceList{
+		QuotaResourceVolumes: *resource.NewQuantity(1, resource.DecimalSI),
+	}
+	addQuantity(used, QuotaResourceStorage, size)
+
+	// local or remote
+	var isLocal, isRemote bool
+	switch {
+	case vol.Spec.TargetNodeId != "" && vol.Spec.HostNode != nil:
+		isLocal = vol.IsLocal()
+		isRemote = !isLocal
+	case vol.Spec.PositionAdvice == MustLocal:
+		isLocal = true
+	case vol.Spec.PositionAdvice == MustRemote:
+		isRemote = true
+	default:
+		isLocal, isRemote = worstCase, worstCase
+	}
+	if isLocal {
+		addQuantity(used, QuotaResourceLocalStorage, size)
+	}
+	if isRemote {
+		addQuantity(used, QuotaResourceRemoteStorage, size)
+	}
+
+	// thin or thick
+	if vol.Spec.IsThin {
+		addQuantity(used, QuotaResourceThinStorage, size)
+	} else {
+		addQuantity(used, QuotaResourceThickStorage, size)
+	}
+
+	// spdk or lvm
+	switch vol.Spec.Type {
+	case VolumeTypeSpdkLVol:
+		addQuantity(used, QuotaResourceSpdkStorage, size)
+	case VolumeTypeKernelLVol:
+		addQuantity(used, QuotaResourceLvmStorage, size)
+	default:
+		if worstCase {
+			addQuantity(used, QuotaResourceSpdkStorage, size)
+			addQuantity(used, QuotaResourceLvmStorage, size)
+		}
+	}
+
+	// thin snapshots need no reserved space
+	if val, has := vol.Annotations[SnapshotReservedSpaceAnnotationKey]; has && !vol.IsThinLVol() {
+		if reserved, err := strconv.ParseInt(val, 10, 64); err == nil && reserved > 0 {
+			addQuantity(used, QuotaResourceSnapshotReservedStorage, reserved)
+		}
+	}
+
+	return
+}
+
+// QuotaUsage returns the resources used by the snapshot
+func (snap *AntstorSnapshot) QuotaUsage() (used corev1.ResourceList) {
+	used = corev1.ResourceList{
+		QuotaResourceSnapshots: *resource.NewQuantity(1, resource.DecimalSI),
+	}
+	// thin snapshot shares blocks with its origin in the thin pool
+	if !snap.Spec.IsThin {
+		addQuantity(used, QuotaResourceSnapshotStorage, snap.Spec.Size)
+	}
+	return
+}
+
+// QuotaUsageOfNamespace sums up the resources used by volumes and snapshots which belong to the namespace.
+// A snapshot belongs to the namespace of its origin volume.
+func QuotaUsageOfNamespace(ns string, vols []AntstorVolume, snaps []AntstorSnapshot) (used corev1.ResourceList) {
+	used = corev1.ResourceList{}
+	// key is namespace/name of volume
+	var volNS = make(map[string]string, len(vols))
+	for i := range vols {
+		vol := &vols[i]
+		volNS[vol.Namespace+"/"+vol.Name] = vol.QuotaNamespace()
+		if vol.QuotaNamespace() == ns {
+			AddResourceList(used, vol.QuotaUsage(false))
+		}
+	}
+
+	for i := range snaps {
+		snap := &snaps[i]
+		snapNS, has := snap.Labels[VolumeContextKeyPvcNS]
+		if !has {
+			snapNS = volNS[snap.Spec.OriginVolNamespace+"/"+snap.Spec.OriginVolName]
+		}
+		if snapNS == ns {
+			AddResourceList(used, snap.QuotaUsage())
+		}
+	}
+
+	return
+}
+
+// ExceededResources returns the resources whose usage will exceed the hard limit after adding req
+func (q *AntstorQuota) ExceededResources(used, req corev1.ResourceList) (exceeded []corev1.ResourceName) {
+	for name, hard := range q.Spec.Hard {
+		reqQuan, has := req[name]
+		if !has || reqQuan.Sign() <= 0 {
+			continue
+		}
+		total := used[name].DeepCopy()
+		total.Add(reqQuan)
+		if total.Cmp(hard) > 0 {
+			exceeded = append(exceeded, name)
+		}
+	}
+	sort.Slice(exceeded, func(i, j int) bool {
+		return exceeded[i] < exceeded[j]
+	})
+	return
+}
+
+// AddResourceList adds resources of b to a
+func AddResourceList(a, b corev1.ResourceList) {
+	for name, quan := range b {
+		if cur, has := a[name]; has {
+			cur.Add(quan)
+			a[name] = cur
+		} else {
+			a[name] = quan.DeepCopy()
+		}
+	}
+}
+
+func addQuantity(list corev1.ResourceList, name corev1.ResourceName, val int64) {
+	AddResourceList(list, corev1.ResourceList{
+		name: *resource.NewQuantity(val, resource.BinarySI),
+	})
+}
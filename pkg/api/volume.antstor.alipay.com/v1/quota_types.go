@@ -0,0 +1,75 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// QuotaResourceVolumes is the count of volumes
+	QuotaResourceVolumes corev1.ResourceName = "volumes"
+	// QuotaResourceStorage is the total size of volumes
+	QuotaResourceStorage corev1.ResourceName = "storage"
+	// QuotaResourceLocalStorage is the size of volumes on the same node with the consumer
+	QuotaResourceLocalStorage corev1.ResourceName = "local.storage"
+	// QuotaResourceRemoteStorage is the size of volumes connected by NVMe-oF
+	QuotaResourceRemoteStorage corev1.ResourceName = "remote.storage"
+	// QuotaResourceThinStorage is the size of thin provisioned volumes
+	QuotaResourceThinStorage corev1.ResourceName = "thin.storage"
+	// QuotaResourceThickStorage is the size of thick provisioned volumes
+	QuotaResourceThickStorage corev1.ResourceName = "thick.storage"
+	// QuotaResourceSpdkStorage is the size of SpdkLVol volumes
+	QuotaResourceSpdkStorage corev1.ResourceName = "spdk.storage"
+	// QuotaResourceLvmStorage is the size of KernelLVol volumes
+	QuotaResourceLvmStorage corev1.ResourceName = "lvm.storage"
+	// QuotaResourceSnapshotReservedStorage is the snapshot space reserved by volumes
+	QuotaResourceSnapshotReservedStorage corev1.ResourceName = "snapshot-reserved.storage"
+	// QuotaResourceSnapshots is the count of snapshots
+	QuotaResourceSnapshots corev1.ResourceName = "snapshots"
+	// QuotaResourceSnapshotStorage is the total size of snapshots. Thin snapshots are not counted.
+	QuotaResourceSnapshotStorage corev1.ResourceName = "snapshots.storage"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=aq
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// AntstorQuota limits the storage used by PVCs in its namespace
+type AntstorQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AntstorQuotaSpec `json:"spec,omitempty"`
+
+	// +optional
+	Status AntstorQuotaStatus `json:"status,omitempty"`
+}
+
+type AntstorQuotaSpec struct {
+	// Hard is the limit of each resource, e.g. storage, local.storage, spdk.storage, snapshots
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+}
+
+type AntstorQuotaStatus struct {
+	// Hard is the enforced limit, copied from spec
+	// +optional
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+	// Used is the current usage computed from AntstorVolumes and AntstorSnapshots
+	// +optional
+	Used corev1.ResourceList `json:"used,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// AntstorQuotaList contains a list of AntstorQuota
+type AntstorQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AntstorQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AntstorQuota{}, &AntstorQuotaList{})
+}
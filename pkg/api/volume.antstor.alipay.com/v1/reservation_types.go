@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StorageReservationIDPrefix distinguishes the reservation ID of StorageReservation from the ID of PVC reservation, which is <namespace>/<name>
+const StorageReservationIDPrefix = "storagereservation/"
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=sr
+// +kubebuilder:printcolumn:name="size",type=string,JSONPath=`.spec.size`
+// +kubebuilder:printcolumn:name="expire",type=string,JSONPath=`.status.expireTime`
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// StorageReservation reserves space on the selected pools. Its reservation ID is storagereservation/<namespace>/<name>.
+// A volume consumes the reservation by annotation obnvmf/reservation-id.
+type StorageReservation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec StorageReservationSpec `json:"spec,omitempty"`
+
+	// +optional
+	Status StorageReservationStatus `json:"status,omitempty"`
+}
+
+type StorageReservationSpec struct {
+	// Size to reserve on each selected pool
+	Size resource.Quantity `json:"size"`
+	// NodeSelector selects pools by labels of node. Empty selector means all nodes.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+	// PoolSelector selects pools by labels of StoragePool. Empty selector means all pools.
+	// +optional
+	PoolSelector *metav1.LabelSelector `json:"poolSelector,omitempty"`
+	// Priority of the reservation. It is preempted by volumes with higher priority.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+	// TTL of the reservation, counted from creation. The StorageReservation is deleted when it expires.
+	// Empty TTL means the reservation never expires.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+type StorageReservationStatus struct {
+	// ExpireTime is the time when the reservation expires
+	// +optional
+	ExpireTime *metav1.Time `json:"expireTime,omitempty"`
+	// ReservedNodes are the nodes holding the reservation
+	// +optional
+	ReservedNodes []string `json:"reservedNodes,omitempty"`
+	// ConsumedNodes are the nodes where a volume has consumed the reservation
+	// +optional
+	ConsumedNodes []string `json:"consumedNodes,omitempty"`
+	// FailedNodes are the selected nodes without enough free space
+	// +optional
+	FailedNodes []string `json:"failedNodes,omitempty"`
+	// PreemptedNodes are the nodes where the reservation is preempted by volumes with higher priority.
+	// The reservation is not reserved on them again.
+	// +optional
+	PreemptedNodes []string `json:"preemptedNodes,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// StorageReservationList contains a list of StorageReservation
+type StorageReservationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StorageReservation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StorageReservation{}, &StorageReservationList{})
+}
+
+// ReservationID returns the ID of reservation in scheduler state
+func (sr *StorageReservation) ReservationID() string {
+	return StorageReservationID(sr.Namespace, sr.Name)
+}
+
+// StorageReservationID returns the reservation ID of the StorageReservation namespace/name
+func StorageReservationID(namespace, name string) string {
+	return StorageReservationIDPrefix + namespace + "/" + name
+}
+
+// ParseStorageReservationID returns namespace and name of the StorageReservation, if id is the reservation ID of a StorageReservation
+func ParseStorageReservationID(id string) (namespace, name string, ok bool) {
+	if !strings.HasPrefix(id, StorageReservationIDPrefix) {
+		return
+	}
+	parts := strings.SplitN(strings.TrimPrefix(id, StorageReservationIDPrefix), "/", 2)
+	if len(parts) != 2 {
+		return
+	}
+	return parts[0], parts[1], true
+}
+
+// ExpireTime returns the time when the reservation expires, or nil if it has no TTL
+func (sr *StorageReservation) ExpireTime() *metav1.Time {
+	if sr.Spec.TTL == nil {
+		return nil
+	}
+	t := metav1.NewTime(sr.CreationTimestamp.Add(sr.Spec.TTL.Duration))
+	return &t
+}
@@ -0,0 +1,180 @@
+package v1
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	SnapshotBackupPhasePending   SnapshotBackupPhase = "Pending"
+	SnapshotBackupPhaseUploading SnapshotBackupPhase = "Uploading"
+	SnapshotBackupPhaseCompleted SnapshotBackupPhase = "Completed"
+	SnapshotBackupPhaseFailed    SnapshotBackupPhase = "Failed"
+
+	// SnapshotBackupSnapshotLabelKey is set on SnapshotBackup by the controller. Value is the name of the snapshot.
+	SnapshotBackupSnapshotLabelKey = "obnvmf/backup-snapshot"
+	// VolumeSourceBackupAnnoKey is set on PVC to restore the volume from a SnapshotBackup. It is copied to AntstorVolume by CSI.
+	// Value is namespace/name of the SnapshotBackup, or only the name if it is in the default namespace.
+	VolumeSourceBackupAnnoKey = "obnvmf/source-backup"
+
+	// BackupSecretAccessKeyIDKey and BackupSecretSecretAccessKeyKey are the keys of credentials in the Secret of BackupTarget
+	BackupSecretAccessKeyIDKey     = "accessKeyID"
+	BackupSecretSecretAccessKeyKey = "secretAccessKey"
+
+	// DefaultBackupChunkSizeBytes is used if spec.chunkSizeBytes is not set
+	DefaultBackupChunkSizeBytes = 4 << 20
+)
+
+// +kubebuilder:validation:Enum=Pending;Uploading;Completed;Failed
+type SnapshotBackupPhase string
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=sbak
+// +kubebuilder:printcolumn:name="snapshot",type=string,JSONPath=`.spec.snapshotName`
+// +kubebuilder:printcolumn:name="phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="base",type=string,JSONPath=`.status.baseBackup`
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// SnapshotBackup uploads data of an AntstorSnapshot to S3-compatible object storage.
+// Data is split into chunks, which are stored by their sha256. The backup only uploads the chunks which are different from its base backup.
+type SnapshotBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SnapshotBackupSpec `json:"spec,omitempty"`
+
+	// +optional
+	Status SnapshotBackupStatus `json:"status,omitempty"`
+}
+
+type SnapshotBackupSpec struct {
+	// SnapshotName is the name of AntstorSnapshot in the same namespace
+	SnapshotName string `json:"snapshotName"`
+	// Target is the bucket to store the backup
+	Target BackupTarget `json:"target"`
+	// ChunkSizeBytes is the size of each chunk. Only chunks of the same size are compared with the base backup.
+	// +kubebuilder:validation:Minimum=1048576
+	// +kubebuilder:validation:Maximum=67108864
+	// +kubebuilder:default=4194304
+	// +optional
+	ChunkSizeBytes int64 `json:"chunkSizeBytes,omitempty"`
+	// BaseBackupName is the name of a Completed SnapshotBackup in the same namespace and target.
+	// If it is not set, a backup of thin snapshot uses the latest Completed backup of the same volume as its base.
+	// +optional
+	BaseBackupName string `json:"baseBackupName,omitempty"`
+}
+
+type BackupTarget struct {
+	// Endpoint is the URL of S3-compatible service, e.g. http://minio.minio:9000
+	Endpoint string `json:"endpoint"`
+	// +optional
+	Region string `json:"region,omitempty"`
+	Bucket string `json:"bucket"`
+	// Prefix is prepended to the keys of objects
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+	// Insecure skips verifying TLS certificate of the endpoint
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+	// CredentialsSecretRef is the Secret which has accessKeyID and secretAccessKey. Namespace defaults to the namespace of backup.
+	CredentialsSecretRef corev1.SecretReference `json:"credentialsSecretRef"`
+}
+
+type SnapshotBackupStatus struct {
+	// +optional
+	Phase SnapshotBackupPhase `json:"phase,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// NodeID is the node of the snapshot, whose agent uploads the data
+	// +optional
+	NodeID string `json:"nodeId,omitempty"`
+	// VolumeName is the name of origin volume of the snapshot
+	// +optional
+	VolumeName string `json:"volumeName,omitempty"`
+	// PvcNamespace is the namespace of PVC of the origin volume. Only PVCs in the namespace can be restored from the backup.
+	// +optional
+	PvcNamespace string `json:"pvcNamespace,omitempty"`
+	// BaseBackup is the name of backup which this backup is incremental to
+	// +optional
+	BaseBackup string `json:"baseBackup,omitempty"`
+	// SizeBytes is the size of snapshot data
+	// +optional
+	SizeBytes int64 `json:"sizeBytes,omitempty"`
+	// ProcessedBytes is the bytes of snapshot which are read and checked
+	// +optional
+	ProcessedBytes int64 `json:"processedBytes,omitempty"`
+	// UploadedBytes is the bytes of chunks which are uploaded
+	// +optional
+	UploadedBytes int64 `json:"uploadedBytes,omitempty"`
+	// +optional
+	TotalChunks int64 `json:"totalChunks,omitempty"`
+	// UploadedChunks is the number of chunks which are uploaded
+	// +optional
+	UploadedChunks int64 `json:"uploadedChunks,omitempty"`
+	// UnchangedChunks is the number of chunks which are already stored by the base backup
+	// +optional
+	UnchangedChunks int64 `json:"unchangedChunks,omitempty"`
+	// ZeroChunks is the number of chunks which are all zero. They are not uploaded.
+	// +optional
+	ZeroChunks int64 `json:"zeroChunks,omitempty"`
+	// ManifestKey is the key of manifest object in the bucket
+	// +optional
+	ManifestKey string `json:"manifestKey,omitempty"`
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// SnapshotBackupList contains a list of SnapshotBackup
+type SnapshotBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SnapshotBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SnapshotBackup{}, &SnapshotBackupList{})
+}
+
+// ParseSourceBackup returns namespace and name of the SnapshotBackup in the value of VolumeSourceBackupAnnoKey
+func ParseSourceBackup(val string) (ns, name string) {
+	ns, name = DefaultNamespace, val
+	if idx := strings.Index(val, "/"); idx >= 0 {
+		ns, name = val[:idx], val[idx+1:]
+	}
+	return
+}
+
+// IsFinished returns true if the backup is Completed or Failed
+func (sb *SnapshotBackup) IsFinished() bool {
+	return sb.Status.Phase == SnapshotBackupPhaseCompleted || sb.Status.Phase == SnapshotBackupPhaseFailed
+}
+
+// ChunkSize returns spec.chunkSizeBytes, or the default chunk size if it is not set
+func (sb *SnapshotBackup) ChunkSize() int64 {
+	if sb.Spec.ChunkSizeBytes > 0 {
+		return sb.Spec.ChunkSizeBytes
+	}
+	return DefaultBackupChunkSizeBytes
+}
+
+// CredentialsSecret returns namespace and name of the Secret of target. Namespace defaults to the namespace of backup.
+func (sb *SnapshotBackup) CredentialsSecret() (ns, name string) {
+	ns, name = sb.Spec.Target.CredentialsSecretRef.Namespace, sb.Spec.Target.CredentialsSecretRef.Name
+	if ns == "" {
+		ns = sb.Namespace
+	}
+	return
+}
+
+// SameTarget returns true if the backups are stored in the same bucket and prefix of the same endpoint
+func (t BackupTarget) SameTarget(other BackupTarget) bool {
+	return t.Endpoint == other.Endpoint && t.Bucket == other.Bucket && t.Prefix == other.Prefix
+}
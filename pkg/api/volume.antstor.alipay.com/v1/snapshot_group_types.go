@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	SnapshotGroupPhasePending      SnapshotGroupPhase = "Pending"
+	SnapshotGroupPhaseFreezing     SnapshotGroupPhase = "Freezing"
+	SnapshotGroupPhaseSnapshotting SnapshotGroupPhase = "Snapshotting"
+	SnapshotGroupPhaseReady        SnapshotGroupPhase = "Ready"
+	SnapshotGroupPhaseFailed       SnapshotGroupPhase = "Failed"
+
+	// SnapshotGroupLabelKey is set on the member snapshots of a SnapshotGroup. Value is the name of SnapshotGroup.
+	SnapshotGroupLabelKey = "obnvmf/snapshot-group"
+	// FreezeTimeoutAnnoKey is set by the controller after all member snapshots are bound to nodes, asking the agent to freeze the origin volume.
+	// Value is the max seconds to keep the volume frozen while waiting for the cut.
+	FreezeTimeoutAnnoKey = "obnvmf/freeze-timeout-seconds"
+	// FrozenAtAnnoKey is set by the agent after the origin volume is frozen. Value is unix timestamp.
+	FrozenAtAnnoKey = "obnvmf/frozen-at"
+	// CutAtAnnoKey is set by the controller after all origin volumes of the group are frozen.
+	// The agent creates the snapshot and then thaws the origin volume. Value is unix timestamp.
+	CutAtAnnoKey = "obnvmf/cut-at"
+	// ThawedAtAnnoKey is set by the agent after the origin volume is thawed. Value is unix timestamp.
+	// If the snapshot is not created when it is set, the freeze timed out and the snapshot is never created.
+	ThawedAtAnnoKey = "obnvmf/thawed-at"
+
+	// DefaultFreezeTimeoutSeconds is used if the snapshot has no valid obnvmf/freeze-timeout-seconds.
+	// It is also the default timeout of freezing filesystem for CSI CreateSnapshot.
+	DefaultFreezeTimeoutSeconds = 10
+)
+
+// +kubebuilder:validation:Enum=Pending;Freezing;Snapshotting;Ready;Failed
+type SnapshotGroupPhase string
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=sgrp
+// +kubebuilder:printcolumn:name="phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="cutTime",type=string,JSONPath=`.status.cutTime`
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// SnapshotGroup takes crash-consistent snapshots of several AntstorVolumes at the same point in time.
+// All volumes are frozen by their agents, then the snapshots are cut, and each volume is thawed after its snapshot is created.
+type SnapshotGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SnapshotGroupSpec `json:"spec,omitempty"`
+
+	// +optional
+	Status SnapshotGroupStatus `json:"status,omitempty"`
+}
+
+type SnapshotGroupSpec struct {
+	// VolumeNames are the names of AntstorVolumes in the same namespace
+	// +optional
+	VolumeNames []string `json:"volumeNames,omitempty"`
+	// Selector selects AntstorVolumes in the same namespace by labels. It is merged with VolumeNames.
+	// Volumes are resolved once when the group starts.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// SnapshotSize is the size of each snapshot. Default is the snapshot reserved space of each volume.
+	// +optional
+	SnapshotSize *resource.Quantity `json:"snapshotSize,omitempty"`
+	// FreezeTimeoutSeconds is the max time that a volume is frozen while waiting for the others.
+	// If not all volumes are frozen in time, the group fails and the volumes are thawed.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	// +optional
+	FreezeTimeoutSeconds int32 `json:"freezeTimeoutSeconds,omitempty"`
+}
+
+type SnapshotGroupStatus struct {
+	// +optional
+	Phase SnapshotGroupPhase `json:"phase,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime is the time of resolving members
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CutTime is the point in time of the snapshots, when all volumes are frozen
+	// +optional
+	CutTime *metav1.Time `json:"cutTime,omitempty"`
+	// CompletionTime is the time that the group becomes Ready or Failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Members are the volumes and their snapshots
+	// +optional
+	Members []SnapshotGroupMember `json:"members,omitempty"`
+}
+
+type SnapshotGroupMember struct {
+	// VolumeName is the name of AntstorVolume
+	VolumeName string `json:"volumeName"`
+	// SnapshotName is the name of AntstorSnapshot of the volume
+	SnapshotName string `json:"snapshotName"`
+	// TargetNodeID is the node of the volume, whose agent freezes the volume and creates the snapshot
+	// +optional
+	TargetNodeID string `json:"targetNodeId,omitempty"`
+	// Status is the status of the snapshot
+	// +optional
+	Status SnapshotStatusName `json:"status,omitempty"`
+	// FrozenTime is the time that the volume is frozen
+	// +optional
+	FrozenTime *metav1.Time `json:"frozenTime,omitempty"`
+	// ThawedTime is the time that the volume is thawed
+	// +optional
+	ThawedTime *metav1.Time `json:"thawedTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// SnapshotGroupList contains a list of SnapshotGroup
+type SnapshotGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SnapshotGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SnapshotGroup{}, &SnapshotGroupList{})
+}
+
+// IsFinished returns true if the group is Ready or Failed
+func (sg *SnapshotGroup) IsFinished() bool {
+	return sg.Status.Phase == SnapshotGroupPhaseReady || sg.Status.Phase == SnapshotGroupPhaseFailed
+}
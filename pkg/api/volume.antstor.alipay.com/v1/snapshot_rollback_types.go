@@ -0,0 +1,84 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	RollbackPhasePending   RollbackPhase = "Pending"
+	RollbackPhaseMerging   RollbackPhase = "Merging"
+	RollbackPhaseSucceeded RollbackPhase = "Succeeded"
+	RollbackPhaseFailed    RollbackPhase = "Failed"
+)
+
+// +kubebuilder:validation:Enum=Pending;Merging;Succeeded;Failed
+type RollbackPhase string
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=srb
+// +kubebuilder:printcolumn:name="volume",type=string,JSONPath=`.spec.volumeName`
+// +kubebuilder:printcolumn:name="snapshot",type=string,JSONPath=`.spec.snapshotName`
+// +kubebuilder:printcolumn:name="group",type=string,JSONPath=`.spec.snapshotGroupName`
+// +kubebuilder:printcolumn:name="phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// SnapshotRollback rolls back an AntstorVolume to its AntstorSnapshot in place, or all volumes of a SnapshotGroup together.
+// LVM snapshot is merged into the volume and consumed. SPDK volume is cloned from the snapshot again, and the snapshot is kept.
+type SnapshotRollback struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SnapshotRollbackSpec `json:"spec,omitempty"`
+
+	// +optional
+	Status SnapshotRollbackStatus `json:"status,omitempty"`
+}
+
+type SnapshotRollbackSpec struct {
+	// VolumeName is the name of AntstorVolume in the same namespace
+	// +optional
+	VolumeName string `json:"volumeName,omitempty"`
+	// SnapshotName is the name of AntstorSnapshot of the volume in the same namespace
+	// +optional
+	SnapshotName string `json:"snapshotName,omitempty"`
+	// SnapshotGroupName is the name of a Ready SnapshotGroup in the same namespace.
+	// If it is set, all member volumes are rolled back to their snapshots together, and VolumeName and SnapshotName are ignored.
+	// +optional
+	SnapshotGroupName string `json:"snapshotGroupName,omitempty"`
+	// Force rolls back the volume even if it is attached to a node. Data in use may be corrupted.
+	// +optional
+	Force bool `json:"force,omitempty"`
+}
+
+type SnapshotRollbackStatus struct {
+	// +optional
+	Phase RollbackPhase `json:"phase,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// StartTime is the time of starting merge, from label obnmvf/merge-start-timestamp of the snapshot
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time of finishing merge, from label obnmvf/merge-finish-timestamp of the snapshot
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// SnapshotRollbackList contains a list of SnapshotRollback
+type SnapshotRollbackList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SnapshotRollback `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SnapshotRollback{}, &SnapshotRollbackList{})
+}
+
+// IsFinished returns true if the rollback succeeded or failed
+func (sr *SnapshotRollback) IsFinished() bool {
+	return sr.Status.Phase == RollbackPhaseSucceeded || sr.Status.Phase == RollbackPhaseFailed
+}
@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=ssch
+// +kubebuilder:printcolumn:name="schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="suspend",type=boolean,JSONPath=`.spec.suspend`
+// +kubebuilder:printcolumn:name="lastSchedule",type=date,JSONPath=`.status.lastScheduleTime`
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// SnapshotSchedule creates AntstorSnapshots of the selected AntstorVolumes by cron, and deletes the old ones by retention.
+type SnapshotSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SnapshotScheduleSpec `json:"spec,omitempty"`
+
+	// +optional
+	Status SnapshotScheduleStatus `json:"status,omitempty"`
+}
+
+type SnapshotScheduleSpec struct {
+	// Schedule is a cron expression in UTC, e.g. "0 2 * * *" or "@daily"
+	Schedule string `json:"schedule"`
+	// Selector selects AntstorVolumes in the same namespace by labels
+	Selector *metav1.LabelSelector `json:"selector"`
+	// Retention decides which scheduled snapshots are deleted
+	// +optional
+	Retention SnapshotRetention `json:"retention,omitempty"`
+	// SnapshotSize is the size of each snapshot. Default is the snapshot reserved space of volume divided by retention.keepLast.
+	// +optional
+	SnapshotSize *resource.Quantity `json:"snapshotSize,omitempty"`
+	// Suspend stops creating new snapshots. Old snapshots are still deleted by retention.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+type SnapshotRetention struct {
+	// KeepLast is the number of latest snapshots of each volume to keep
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	KeepLast int `json:"keepLast,omitempty"`
+	// MaxAge is the max age of snapshots to keep
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+}
+
+type SnapshotScheduleStatus struct {
+	// LastScheduleTime is the last time of schedule
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSuccessfulTime is the last time when snapshots of all selected volumes are created
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// LastFailureTime is the last time when any snapshot failed to be created
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+	// LastFailureMessage is the reason of the last failure
+	// +optional
+	LastFailureMessage string `json:"lastFailureMessage,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// SnapshotScheduleList contains a list of SnapshotSchedule
+type SnapshotScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SnapshotSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SnapshotSchedule{}, &SnapshotScheduleList{})
+}
@@ -21,11 +21,42 @@ const (
 
 	// SnapUuidLabelKey is the key of snapshot's uuid. It is used for listing snapshot by label filter
 	SnapUuidLabelKey = "obnvmf/snap-uuid"
+
+	// SnapshotCopyForLabelKey is set on the temporary snapshot of a source volume. Value is the name of volume being cloned.
+	SnapshotCopyForLabelKey = "obnvmf/copy-for-volume"
+	// DataCopyOwnerAnnoKey is namespace/name of the volume, which is copying data from the snapshot.
+	// Only one volume copies data from a snapshot at a time.
+	DataCopyOwnerAnnoKey = "obnvmf/data-copy-owner"
+	// SnapshotScheduleLabelKey is set on the snapshot created by a SnapshotSchedule. Value is the name of SnapshotSchedule.
+	// A volume may have more than one scheduled snapshot, as long as their sizes fit in the snapshot reserved space.
+	SnapshotScheduleLabelKey = "obnvmf/snapshot-schedule"
+
+	// SnapshotConsistencyCrash means the snapshot has the data on disk at the cut, like after a power loss
+	SnapshotConsistencyCrash SnapshotConsistency = "Crash"
+	// SnapshotConsistencyApplication means the filesystem of the origin volume is frozen during the cut, so no dirty data is lost
+	SnapshotConsistencyApplication SnapshotConsistency = "Application"
+
+	// FsFreezeNodeLabelKey is set by the agent to ask the CSI node plugin to freeze the filesystem of the origin volume.
+	// Value is the ID of the node which mounts the volume. The node plugin removes it after the filesystem is thawed.
+	FsFreezeNodeLabelKey = "obnvmf/fs-freeze-node"
+	// FsFreezePathAnnoKey is set by the agent. Value is the staging target path of the origin volume.
+	FsFreezePathAnnoKey = "obnvmf/fs-freeze-path"
+	// FsFreezeRequestedAtAnnoKey is set by the agent together with FsFreezeNodeLabelKey. Value is unix timestamp.
+	FsFreezeRequestedAtAnnoKey = "obnvmf/fs-freeze-requested-at"
+	// FsFrozenAtAnnoKey is set by the node plugin after the filesystem is frozen. Value is unix timestamp.
+	FsFrozenAtAnnoKey = "obnvmf/fs-frozen-at"
+	// FsThawedAtAnnoKey is set by the node plugin right before the filesystem is thawed. Value is unix timestamp.
+	FsThawedAtAnnoKey = "obnvmf/fs-thawed-at"
+	// FsFreezeErrorAnnoKey is set by the node plugin if the filesystem cannot be frozen
+	FsFreezeErrorAnnoKey = "obnvmf/fs-freeze-error"
 )
 
-// +kubebuilder:validation:Enum=creating;ready;merging;merged
+// +kubebuilder:validation:Enum=creating;ready;merging;merged;error
 type SnapshotStatusName string
 
+// +kubebuilder:validation:Enum=Crash;Application
+type SnapshotConsistency string
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:object:root=true
@@ -34,6 +65,7 @@ type SnapshotStatusName string
 // +kubebuilder:printcolumn:name="volType",type=string,JSONPath=`.spec.volType`
 // +kubebuilder:printcolumn:name="originVol",type=string,JSONPath=`.spec.originVolName`
 // +kubebuilder:printcolumn:name="status",type=string,JSONPath=`.status.status`
+// +kubebuilder:printcolumn:name="consistency",type=string,JSONPath=`.status.consistency`
 // +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
 // AntstorSnapshot is the Schema for the antstorvolumes API
 type AntstorSnapshot struct {
@@ -72,11 +104,38 @@ type AntstorSnapshotSpec struct {
 
 	// +optional
 	OriginVolTargetNodeID string `json:"originVolTargetNodeId"`
+
+	// ExportToNodeID is the node which reads the snapshot over NVMe-oF. If set, agent on the origin node exports the snapshot.
+	// +optional
+	ExportToNodeID string `json:"exportToNodeId,omitempty"`
+
+	// FsFreezeTimeoutSeconds enables freezing the filesystem of the origin volume before the snapshot is cut, if it is positive.
+	// The CSI node plugin on the node which mounts the volume thaws the filesystem after the cut, or after the timeout.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FsFreezeTimeoutSeconds int32 `json:"fsFreezeTimeoutSeconds,omitempty"`
+
+	// IsThin is true if the origin volume is a thin LV. The snapshot is a thin snapshot in the same thin pool,
+	// which shares blocks with the origin and needs no snapshot reserved space. Size is the virtual size of the snapshot.
+	// +optional
+	IsThin bool `json:"isThin,omitempty"`
 }
 
 type AntstorSnapshotStatus struct {
 	// +optional
 	Status SnapshotStatusName `json:"status"`
+
+	// ExportTarget is the NVMe-oF target of the snapshot for ExportToNodeID
+	// +optional
+	ExportTarget *SpdkTarget `json:"exportTarget,omitempty"`
+
+	// Consistency is the consistency level of the snapshot data. It is set by the agent after the snapshot is cut.
+	// +optional
+	Consistency SnapshotConsistency `json:"consistency,omitempty"`
+
+	// Message is the reason of error status, e.g. the COW snapshot is invalidated because its COW space is full
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
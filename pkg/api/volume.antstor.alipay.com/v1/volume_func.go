@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"strconv"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 )
 
-// GetTotalSize gets volume size + reserved snapshot size
+// GetTotalSize gets volume size + reserved snapshot size.
+// Thin snapshots of thin LV take space from the thin pool on demand, so reserved snapshot size is not counted.
 func (vol *AntstorVolume) GetTotalSize() (size uint64) {
 	var reservedSnapSize int
 	var allocatedSize int
@@ -28,6 +30,10 @@ func (vol *AntstorVolume) GetTotalSize() (size uint64) {
 		}
 	}
 
+	if vol.IsThinLVol() {
+		reservedSnapSize = 0
+	}
+
 	if allocatedSize > 0 {
 		size = uint64(allocatedSize + reservedSnapSize)
 	} else {
@@ -36,6 +42,11 @@ func (vol *AntstorVolume) GetTotalSize() (size uint64) {
 	return
 }
 
+// IsThinLVol returns true if the volume is a thin LV in LVM thin pool. Its snapshots are thin snapshots.
+func (vol *AntstorVolume) IsThinLVol() bool {
+	return vol.Spec.IsThin && vol.Spec.Type == VolumeTypeKernelLVol
+}
+
 func (vol *AntstorVolume) IsLocal() bool {
 	return vol.Spec.HostNode.ID == vol.Spec.TargetNodeId
 }
@@ -48,6 +59,39 @@ func (vol *AntstorVolume) ReservationID() string {
 	return ""
 }
 
+// GetCondition returns the condition of the type. Nil is returned if not found.
+func (vol *AntstorVolume) GetCondition(typ VolumeConditionType) *VolumeCondition {
+	for idx := range vol.Status.Conditions {
+		if vol.Status.Conditions[idx].Type == typ {
+			return &vol.Status.Conditions[idx]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or updates the condition. LastTransitionTime is updated only if Status changes.
+// It returns true if anything is changed.
+func (vol *AntstorVolume) SetCondition(cond VolumeCondition) (changed bool) {
+	existing := vol.GetCondition(cond.Type)
+	if existing == nil {
+		cond.LastTransitionTime = metav1.Now()
+		vol.Status.Conditions = append(vol.Status.Conditions, cond)
+		return true
+	}
+
+	if existing.Status != cond.Status {
+		existing.Status = cond.Status
+		existing.LastTransitionTime = metav1.Now()
+		changed = true
+	}
+	if existing.Reason != cond.Reason || existing.Message != cond.Message {
+		existing.Reason = cond.Reason
+		existing.Message = cond.Message
+		changed = true
+	}
+	return
+}
+
 func (vol *SpdkLvol) FullName() string {
 	return fmt.Sprintf("%s/%s", vol.LvsName, vol.Name)
 }
@@ -29,6 +29,8 @@ const (
 	MigrationLabelKeySourceVolumeName = "migrate.obnvmf/source-volume-name"
 	MigrationLabelKeySourceNodeId     = "migrate.obnvmf/source-node-id"
 	MigrationLabelKeyHostNodeId       = "migrate.obnvmf/host-node-id"
+	// MigrationLabelKeyCreatedBy records which component created the migration, e.g. rebalancer
+	MigrationLabelKeyCreatedBy = "migrate.obnvmf/created-by"
 
 	MigrationFinalizerPipeConnected = "obnvmf/migrate-pipe-connected"
 	MigrationFinalizerHostConnected = "obnvmf/migrate-host-connected"
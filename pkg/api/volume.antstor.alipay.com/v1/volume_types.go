@@ -61,6 +61,9 @@ const (
 	// content source info
 	VolumeSourceSnapNameLabelKey      = "obnvmf/volume-source-snap-name"
 	VolumeSourceSnapNamespaceLabelKey = "obnvmf/volume-source-snap-ns"
+	// volume cloned from another volume
+	VolumeSourceVolNameLabelKey      = "obnvmf/volume-source-vol-name"
+	VolumeSourceVolNamespaceLabelKey = "obnvmf/volume-source-vol-ns"
 
 	// key of reservation id
 	ReservationIDKey = "obnvmf/reservation-id"
@@ -71,6 +74,12 @@ const (
 
 	// key of VFIOUSER mode(INTRA_HOST or LOCAL_COPY)
 	VfiouserModeKey = "obnvmf/volume-vfiouser-mode"
+
+	// key of scheduler profile name
+	SchedulerProfileAnnoKey = "obnvmf/scheduler-profile"
+
+	// if value is "true", the volume will not be migrated by rebalancer
+	RebalanceDisabledAnnoKey = "obnvmf/rebalance-disabled"
 )
 
 const (
@@ -110,6 +119,15 @@ const (
 	VolumeStatusReady    VolumeStatus = "ready"
 	VolumeStatusDeleted  VolumeStatus = "deleted"
 
+	// VolumeConditionScheduled is OK if the volume is bound to a StoragePool
+	VolumeConditionScheduled VolumeConditionType = "Scheduled"
+
+	// phases of copying data from the content source into a new volume
+	DataCopyPhasePending  DataCopyPhase = "Pending"
+	DataCopyPhaseCopying  DataCopyPhase = "Copying"
+	DataCopyPhaseFinished DataCopyPhase = "Finished"
+	DataCopyPhaseFailed   DataCopyPhase = "Failed"
+
 	PendingPhase PhaseType = "Pending"
 	ReadyPhase   PhaseType = "Ready"
 
@@ -132,6 +150,8 @@ type VolumeType string
 // +kubebuilder:validation:Enum=creating;ready;deleted
 type VolumeStatus string
 
+type VolumeConditionType string
+
 // +kubebuilder:validation:Enum=MustLocal;PreferLocal;PreferRemote;MustRemote;""
 type VolumePosition string
 
@@ -209,6 +229,11 @@ type AntstorVolumeSpec struct {
 	//+kubebuilder:default=false
 	IsThin bool `json:"isThin,omitempty"`
 
+	// Priority of the volume. If no pool is available, the volume preempts reservations and
+	// not-yet-created volumes with lower priority.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
 	// +optional
 	// StopReconcile is true, reconcile will not process this volume
 	StopReconcile bool `json:"stopReconcile,omitempty"`
@@ -260,6 +285,53 @@ type AntstorVolumeStatus struct {
 
 	// +optional
 	Message string `json:"msg,omitempty"`
+
+	// +optional
+	Conditions []VolumeCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// DataCopy is the progress of copying data from the source volume or snapshot
+	// +optional
+	DataCopy *DataCopyStatus `json:"dataCopy,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Pending;Copying;Finished;Failed
+type DataCopyPhase string
+
+type DataCopyStatus struct {
+	Phase DataCopyPhase `json:"phase"`
+	// SourceVolume is namespace/name of the source volume
+	// +optional
+	SourceVolume string `json:"sourceVolume,omitempty"`
+	// SourceSnapshot is namespace/name of the snapshot, which data is copied from
+	// +optional
+	SourceSnapshot string `json:"sourceSnapshot,omitempty"`
+	// SourceBackup is namespace/name of the SnapshotBackup, which data is restored from
+	// +optional
+	SourceBackup string `json:"sourceBackup,omitempty"`
+	// +optional
+	TotalBytes int64 `json:"totalBytes,omitempty"`
+	// +optional
+	CopiedBytes int64 `json:"copiedBytes,omitempty"`
+	// Retries is the count of copies started again after failure
+	// +optional
+	Retries int32 `json:"retries,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+type VolumeCondition struct {
+	Type   VolumeConditionType `json:"type"`
+	Status ConditionStatus     `json:"status"`
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 /*
@@ -23,6 +23,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -132,13 +133,123 @@ func (in *AntstorDataControlStatus) DeepCopy() *AntstorDataControlStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AntstorQuota) DeepCopyInto(out *AntstorQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntstorQuota.
+func (in *AntstorQuota) DeepCopy() *AntstorQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(AntstorQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AntstorQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AntstorQuotaList) DeepCopyInto(out *AntstorQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AntstorQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntstorQuotaList.
+func (in *AntstorQuotaList) DeepCopy() *AntstorQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(AntstorQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AntstorQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AntstorQuotaSpec) DeepCopyInto(out *AntstorQuotaSpec) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntstorQuotaSpec.
+func (in *AntstorQuotaSpec) DeepCopy() *AntstorQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AntstorQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AntstorQuotaStatus) DeepCopyInto(out *AntstorQuotaStatus) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntstorQuotaStatus.
+func (in *AntstorQuotaStatus) DeepCopy() *AntstorQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AntstorQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AntstorSnapshot) DeepCopyInto(out *AntstorSnapshot) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntstorSnapshot.
@@ -211,6 +322,11 @@ func (in *AntstorSnapshotSpec) DeepCopy() *AntstorSnapshotSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AntstorSnapshotStatus) DeepCopyInto(out *AntstorSnapshotStatus) {
 	*out = *in
+	if in.ExportTarget != nil {
+		in, out := &in.ExportTarget, &out.ExportTarget
+		*out = new(SpdkTarget)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntstorSnapshotStatus.
@@ -443,6 +559,18 @@ func (in *AntstorVolumeStatus) DeepCopyInto(out *AntstorVolumeStatus) {
 		*out = new(HostAttachment)
 		**out = **in
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]VolumeCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DataCopy != nil {
+		in, out := &in.DataCopy, &out.DataCopy
+		*out = new(DataCopyStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntstorVolumeStatus.
@@ -470,6 +598,22 @@ func (in *AutoSwitch) DeepCopy() *AutoSwitch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupTarget) DeepCopyInto(out *BackupTarget) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupTarget.
+func (in *BackupTarget) DeepCopy() *BackupTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CSINodePubParams) DeepCopyInto(out *CSINodePubParams) {
 	*out = *in
@@ -492,6 +636,29 @@ func (in *CSINodePubParams) DeepCopy() *CSINodePubParams {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataCopyStatus) DeepCopyInto(out *DataCopyStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataCopyStatus.
+func (in *DataCopyStatus) DeepCopy() *DataCopyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DataCopyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DesiredVolumeSpec) DeepCopyInto(out *DesiredVolumeSpec) {
 	*out = *in
@@ -755,6 +922,22 @@ func (in *PoolCondition) DeepCopy() *PoolCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolIOStats) DeepCopyInto(out *PoolIOStats) {
+	*out = *in
+	in.UpdateTime.DeepCopyInto(&out.UpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolIOStats.
+func (in *PoolIOStats) DeepCopy() *PoolIOStats {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolIOStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QuantityRange) DeepCopyInto(out *QuantityRange) {
 	*out = *in
@@ -788,52 +971,105 @@ func (in *Raid) DeepCopy() *Raid {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SpdkLVStore) DeepCopyInto(out *SpdkLVStore) {
+func (in *SnapshotBackup) DeepCopyInto(out *SnapshotBackup) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpdkLVStore.
-func (in *SpdkLVStore) DeepCopy() *SpdkLVStore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotBackup.
+func (in *SnapshotBackup) DeepCopy() *SnapshotBackup {
 	if in == nil {
 		return nil
 	}
-	out := new(SpdkLVStore)
+	out := new(SnapshotBackup)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SnapshotBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SpdkLvol) DeepCopyInto(out *SpdkLvol) {
+func (in *SnapshotBackupList) DeepCopyInto(out *SnapshotBackupList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SnapshotBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpdkLvol.
-func (in *SpdkLvol) DeepCopy() *SpdkLvol {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotBackupList.
+func (in *SnapshotBackupList) DeepCopy() *SnapshotBackupList {
 	if in == nil {
 		return nil
 	}
-	out := new(SpdkLvol)
+	out := new(SnapshotBackupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SnapshotBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SpdkTarget) DeepCopyInto(out *SpdkTarget) {
+func (in *SnapshotBackupSpec) DeepCopyInto(out *SnapshotBackupSpec) {
 	*out = *in
+	out.Target = in.Target
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpdkTarget.
-func (in *SpdkTarget) DeepCopy() *SpdkTarget {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotBackupSpec.
+func (in *SnapshotBackupSpec) DeepCopy() *SnapshotBackupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(SpdkTarget)
+	out := new(SnapshotBackupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StoragePool) DeepCopyInto(out *StoragePool) {
+func (in *SnapshotBackupStatus) DeepCopyInto(out *SnapshotBackupStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotBackupStatus.
+func (in *SnapshotBackupStatus) DeepCopy() *SnapshotBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotGroup) DeepCopyInto(out *SnapshotGroup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -841,18 +1077,18 @@ func (in *StoragePool) DeepCopyInto(out *StoragePool) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePool.
-func (in *StoragePool) DeepCopy() *StoragePool {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotGroup.
+func (in *SnapshotGroup) DeepCopy() *SnapshotGroup {
 	if in == nil {
 		return nil
 	}
-	out := new(StoragePool)
+	out := new(SnapshotGroup)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *StoragePool) DeepCopyObject() runtime.Object {
+func (in *SnapshotGroup) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -860,31 +1096,31 @@ func (in *StoragePool) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StoragePoolList) DeepCopyInto(out *StoragePoolList) {
+func (in *SnapshotGroupList) DeepCopyInto(out *SnapshotGroupList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]StoragePool, len(*in))
+		*out = make([]SnapshotGroup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePoolList.
-func (in *StoragePoolList) DeepCopy() *StoragePoolList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotGroupList.
+func (in *SnapshotGroupList) DeepCopy() *SnapshotGroupList {
 	if in == nil {
 		return nil
 	}
-	out := new(StoragePoolList)
+	out := new(SnapshotGroupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *StoragePoolList) DeepCopyObject() runtime.Object {
+func (in *SnapshotGroupList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -892,53 +1128,623 @@ func (in *StoragePoolList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StoragePoolSpec) DeepCopyInto(out *StoragePoolSpec) {
+func (in *SnapshotGroupMember) DeepCopyInto(out *SnapshotGroupMember) {
 	*out = *in
-	in.KernelLVM.DeepCopyInto(&out.KernelLVM)
-	out.SpdkLVStore = in.SpdkLVStore
-	if in.Addresses != nil {
-		in, out := &in.Addresses, &out.Addresses
-		*out = make([]corev1.NodeAddress, len(*in))
+	if in.FrozenTime != nil {
+		in, out := &in.FrozenTime, &out.FrozenTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ThawedTime != nil {
+		in, out := &in.ThawedTime, &out.ThawedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotGroupMember.
+func (in *SnapshotGroupMember) DeepCopy() *SnapshotGroupMember {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotGroupMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotGroupSpec) DeepCopyInto(out *SnapshotGroupSpec) {
+	*out = *in
+	if in.VolumeNames != nil {
+		in, out := &in.VolumeNames, &out.VolumeNames
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	in.NodeInfo.DeepCopyInto(&out.NodeInfo)
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SnapshotSize != nil {
+		in, out := &in.SnapshotSize, &out.SnapshotSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePoolSpec.
-func (in *StoragePoolSpec) DeepCopy() *StoragePoolSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotGroupSpec.
+func (in *SnapshotGroupSpec) DeepCopy() *SnapshotGroupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(StoragePoolSpec)
+	out := new(SnapshotGroupSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StoragePoolStatus) DeepCopyInto(out *StoragePoolStatus) {
+func (in *SnapshotGroupStatus) DeepCopyInto(out *SnapshotGroupStatus) {
 	*out = *in
-	if in.Capacity != nil {
-		in, out := &in.Capacity, &out.Capacity
-		*out = make(corev1.ResourceList, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CutTime != nil {
+		in, out := &in.CutTime, &out.CutTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]SnapshotGroupMember, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.VGFreeSize = in.VGFreeSize.DeepCopy()
-	out.VGVirtualFreeSize = in.VGVirtualFreeSize.DeepCopy()
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]PoolCondition, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotGroupStatus.
+func (in *SnapshotGroupStatus) DeepCopy() *SnapshotGroupStatus {
+	if in == nil {
+		return nil
 	}
+	out := new(SnapshotGroupStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePoolStatus.
-func (in *StoragePoolStatus) DeepCopy() *StoragePoolStatus {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRetention) DeepCopyInto(out *SnapshotRetention) {
+	*out = *in
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRetention.
+func (in *SnapshotRetention) DeepCopy() *SnapshotRetention {
 	if in == nil {
 		return nil
 	}
-	out := new(StoragePoolStatus)
+	out := new(SnapshotRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRollback) DeepCopyInto(out *SnapshotRollback) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRollback.
+func (in *SnapshotRollback) DeepCopy() *SnapshotRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SnapshotRollback) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRollbackList) DeepCopyInto(out *SnapshotRollbackList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SnapshotRollback, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRollbackList.
+func (in *SnapshotRollbackList) DeepCopy() *SnapshotRollbackList {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRollbackList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SnapshotRollbackList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRollbackSpec) DeepCopyInto(out *SnapshotRollbackSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRollbackSpec.
+func (in *SnapshotRollbackSpec) DeepCopy() *SnapshotRollbackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRollbackSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRollbackStatus) DeepCopyInto(out *SnapshotRollbackStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRollbackStatus.
+func (in *SnapshotRollbackStatus) DeepCopy() *SnapshotRollbackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRollbackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotSchedule) DeepCopyInto(out *SnapshotSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotSchedule.
+func (in *SnapshotSchedule) DeepCopy() *SnapshotSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SnapshotSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotScheduleList) DeepCopyInto(out *SnapshotScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SnapshotSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotScheduleList.
+func (in *SnapshotScheduleList) DeepCopy() *SnapshotScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SnapshotScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotScheduleSpec) DeepCopyInto(out *SnapshotScheduleSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Retention.DeepCopyInto(&out.Retention)
+	if in.SnapshotSize != nil {
+		in, out := &in.SnapshotSize, &out.SnapshotSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotScheduleSpec.
+func (in *SnapshotScheduleSpec) DeepCopy() *SnapshotScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotScheduleStatus) DeepCopyInto(out *SnapshotScheduleStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotScheduleStatus.
+func (in *SnapshotScheduleStatus) DeepCopy() *SnapshotScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpdkLVStore) DeepCopyInto(out *SpdkLVStore) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpdkLVStore.
+func (in *SpdkLVStore) DeepCopy() *SpdkLVStore {
+	if in == nil {
+		return nil
+	}
+	out := new(SpdkLVStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpdkLvol) DeepCopyInto(out *SpdkLvol) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpdkLvol.
+func (in *SpdkLvol) DeepCopy() *SpdkLvol {
+	if in == nil {
+		return nil
+	}
+	out := new(SpdkLvol)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpdkTarget) DeepCopyInto(out *SpdkTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpdkTarget.
+func (in *SpdkTarget) DeepCopy() *SpdkTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(SpdkTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoragePool) DeepCopyInto(out *StoragePool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePool.
+func (in *StoragePool) DeepCopy() *StoragePool {
+	if in == nil {
+		return nil
+	}
+	out := new(StoragePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StoragePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoragePoolList) DeepCopyInto(out *StoragePoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StoragePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePoolList.
+func (in *StoragePoolList) DeepCopy() *StoragePoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(StoragePoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StoragePoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoragePoolSpec) DeepCopyInto(out *StoragePoolSpec) {
+	*out = *in
+	in.KernelLVM.DeepCopyInto(&out.KernelLVM)
+	out.SpdkLVStore = in.SpdkLVStore
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]corev1.NodeAddress, len(*in))
+		copy(*out, *in)
+	}
+	in.NodeInfo.DeepCopyInto(&out.NodeInfo)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePoolSpec.
+func (in *StoragePoolSpec) DeepCopy() *StoragePoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StoragePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoragePoolStatus) DeepCopyInto(out *StoragePoolStatus) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	out.VGFreeSize = in.VGFreeSize.DeepCopy()
+	out.VGVirtualFreeSize = in.VGVirtualFreeSize.DeepCopy()
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]PoolCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.IOStats != nil {
+		in, out := &in.IOStats, &out.IOStats
+		*out = new(PoolIOStats)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePoolStatus.
+func (in *StoragePoolStatus) DeepCopy() *StoragePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StoragePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageReservation) DeepCopyInto(out *StorageReservation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageReservation.
+func (in *StorageReservation) DeepCopy() *StorageReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StorageReservation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageReservationList) DeepCopyInto(out *StorageReservationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StorageReservation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageReservationList.
+func (in *StorageReservationList) DeepCopy() *StorageReservationList {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageReservationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StorageReservationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageReservationSpec) DeepCopyInto(out *StorageReservationSpec) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PoolSelector != nil {
+		in, out := &in.PoolSelector, &out.PoolSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageReservationSpec.
+func (in *StorageReservationSpec) DeepCopy() *StorageReservationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageReservationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageReservationStatus) DeepCopyInto(out *StorageReservationStatus) {
+	*out = *in
+	if in.ExpireTime != nil {
+		in, out := &in.ExpireTime, &out.ExpireTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ReservedNodes != nil {
+		in, out := &in.ReservedNodes, &out.ReservedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConsumedNodes != nil {
+		in, out := &in.ConsumedNodes, &out.ConsumedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailedNodes != nil {
+		in, out := &in.FailedNodes, &out.FailedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreemptedNodes != nil {
+		in, out := &in.PreemptedNodes, &out.PreemptedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageReservationStatus.
+func (in *StorageReservationStatus) DeepCopy() *StorageReservationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageReservationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeCondition) DeepCopyInto(out *VolumeCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeCondition.
+func (in *VolumeCondition) DeepCopy() *VolumeCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeCondition)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2,16 +2,47 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 
+	agentcfg "lite.io/liteio/pkg/agent/config"
 	"lite.io/liteio/pkg/util/misc"
 	corev1 "k8s.io/api/core/v1"
 )
 
 type Config struct {
-	Scheduler     SchedulerConfig `json:"scheduler" yaml:"scheduler"`
-	PluginConfigs json.RawMessage `json:"pluginConfigs" yaml:"pluginConfigs"`
+	Scheduler SchedulerConfig `json:"scheduler" yaml:"scheduler"`
+	Rebalance RebalanceConfig `json:"rebalance" yaml:"rebalance"`
+	// ConsistencyCheck audits the State of scheduler against APIServer
+	ConsistencyCheck ConsistencyCheckConfig `json:"consistencyCheck" yaml:"consistencyCheck"`
+	PluginConfigs    json.RawMessage        `json:"pluginConfigs" yaml:"pluginConfigs"`
+}
+
+// RebalanceConfig defines how the rebalancer moves remote volumes from full pools to idle pools
+type RebalanceConfig struct {
+	// Enabled turns on the rebalancer
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// DryRun only logs the proposed migrations, without creating VolumeMigrations
+	DryRun bool `json:"dryRun" yaml:"dryRun"`
+	// IntervalSeconds is the interval of analysis. Default value is 600.
+	IntervalSeconds int `json:"intervalSeconds" yaml:"intervalSeconds"`
+	// HighWatermarkPct is the usage percentage above which a pool is considered full. Default value is 85.
+	HighWatermarkPct int `json:"highWatermarkPct" yaml:"highWatermarkPct"`
+	// LowWatermarkPct is the max usage percentage of a destination pool after migration. Default value is 60.
+	LowWatermarkPct int `json:"lowWatermarkPct" yaml:"lowWatermarkPct"`
+	// MaxConcurrentMigrations is the max count of running VolumeMigrations in the cluster. Default value is 2.
+	MaxConcurrentMigrations int `json:"maxConcurrentMigrations" yaml:"maxConcurrentMigrations"`
+}
+
+// ConsistencyCheckConfig defines how the in-memory State is audited against StoragePools and AntstorVolumes in APIServer
+type ConsistencyCheckConfig struct {
+	// Enabled turns on the periodic check
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// IntervalSeconds is the interval of checking. Default value is 300.
+	IntervalSeconds int `json:"intervalSeconds" yaml:"intervalSeconds"`
+	// AutoRepair fixes State by binding or unbinding volumes, adding or removing pools and removing leaked reservations
+	AutoRepair bool `json:"autoRepair" yaml:"autoRepair"`
 }
 
 type SchedulerConfig struct {
@@ -31,7 +62,58 @@ type SchedulerConfig struct {
 	// MinLocalStoragePct defines the minimun percentage of local storage to be reserved on one node.
 	MinLocalStoragePct int `json:"minLocalStoragePct" yaml:"minLocalStoragePct"`
 	// NodeReservations defines the reservations on each node
+	// Deprecated: use StorageReservation, which can be changed without restarting controller
 	NodeReservations []NodeReservation `json:"nodeReservations" yaml:"nodeReservations"`
+	// DataHolderSpread defines the failure domain across which volumes of the same data-holder are spread
+	DataHolderSpread DataHolderSpreadConfig `json:"dataHolderSpread" yaml:"dataHolderSpread"`
+	// Profiles are named scheduler profiles. A volume selects its profile by annotation obnvmf/scheduler-profile.
+	// Volumes without the annotation are scheduled by Filters and Priorities.
+	Profiles []SchedulerProfile `json:"profiles" yaml:"profiles"`
+	// IOLoad defines the ceiling of IO load, used by IOLoad filter
+	IOLoad IOLoadConfig `json:"ioLoad" yaml:"ioLoad"`
+}
+
+type SchedulerProfile struct {
+	// Name of the profile
+	Name string `json:"name" yaml:"name"`
+	// filter names. If empty, SchedulerConfig.Filters is used.
+	Filters []string `json:"filters" yaml:"filters"`
+	// weighted priorities. If empty, SchedulerConfig.Priorities is used.
+	Priorities []WeightedPriority `json:"priorities" yaml:"priorities"`
+	// NormalizeScore scales the scores of each priority to [0, 100] among candidate nodes, before multiplying weight
+	NormalizeScore bool `json:"normalizeScore" yaml:"normalizeScore"`
+}
+
+type WeightedPriority struct {
+	// Name of the priority
+	Name string `json:"name" yaml:"name"`
+	// Weight of the priority. Default value is 1.
+	Weight int `json:"weight" yaml:"weight"`
+}
+
+type DataHolderSpreadConfig struct {
+	// Level is one of node, rack and room. Default value is node.
+	Level SpreadLevel `json:"level" yaml:"level"`
+	// NodeKeys defines the label keys of rack and room on Node
+	NodeKeys agentcfg.NodeInfoKeys `json:"nodeInfoKeys" yaml:"nodeInfoKeys"`
+}
+
+type SpreadLevel string
+
+const (
+	SpreadLevelNode SpreadLevel = "node"
+	SpreadLevelRack SpreadLevel = "rack"
+	SpreadLevelRoom SpreadLevel = "room"
+)
+
+// IOLoadConfig defines the ceiling of IO load of a Pool. Zero value means no limit.
+type IOLoadConfig struct {
+	// MaxIOPS is the max sum of read and write IOPS
+	MaxIOPS int64 `json:"maxIOPS" yaml:"maxIOPS"`
+	// MaxBps is the max sum of read and write bandwidth, unit: byte per second
+	MaxBps int64 `json:"maxBps" yaml:"maxBps"`
+	// MaxQueueTimeUs is the max average time of one IO in queue, unit: us
+	MaxQueueTimeUs int64 `json:"maxQueueTimeUs" yaml:"maxQueueTimeUs"`
 }
 
 type NodeReservation struct {
@@ -44,6 +126,35 @@ type NoScheduleConfig struct {
 	NodeTaints   []corev1.Toleration              `json:"nodeTaints" yaml:"nodeTaints"`
 }
 
+// GetProfile returns the profile by name. If name is empty, a default profile is built from Filters and Priorities.
+func (sc SchedulerConfig) GetProfile(name string) (p SchedulerProfile, err error) {
+	if name != "" {
+		var found bool
+		for _, item := range sc.Profiles {
+			if item.Name == name {
+				p = item
+				found = true
+				break
+			}
+		}
+		if !found {
+			err = fmt.Errorf("not found scheduler profile by name %s", name)
+			return
+		}
+	}
+
+	if len(p.Filters) == 0 {
+		p.Filters = sc.Filters
+	}
+	if len(p.Priorities) == 0 {
+		for _, item := range sc.Priorities {
+			p.Priorities = append(p.Priorities, WeightedPriority{Name: item, Weight: 1})
+		}
+	}
+
+	return
+}
+
 func Load(file string) (c Config, err error) {
 	var (
 		f *os.File
@@ -54,6 +165,7 @@ func Load(file string) (c Config, err error) {
 	if err != nil {
 		return
 	}
+	defer f.Close()
 	b, err = io.ReadAll(f)
 	if err != nil {
 		return
@@ -35,3 +35,42 @@ func TestConfig(t *testing.T) {
 	assert.Equal(t, "bbb", testCfg.Test["aaa"])
 	assert.Equal(t, "ddd", testCfg.Test2["ccc"])
 }
+
+func TestSchedulerProfile(t *testing.T) {
+	var profileCfg = `scheduler:
+  filters:
+  - Basic
+  priorities:
+  - LeastResource
+  - PositionAdvice
+  profiles:
+  - name: latency-critical
+    normalizeScore: true
+    filters:
+    - Basic
+    - Affinity
+    priorities:
+    - name: PositionAdvice
+      weight: 3
+    - name: LeastResource
+      weight: 1`
+
+	c, err := fromYamlBytes([]byte(profileCfg))
+	assert.NoError(t, err)
+
+	p, err := c.Scheduler.GetProfile("latency-critical")
+	assert.NoError(t, err)
+	assert.True(t, p.NormalizeScore)
+	assert.Equal(t, []string{"Basic", "Affinity"}, p.Filters)
+	assert.Equal(t, WeightedPriority{Name: "PositionAdvice", Weight: 3}, p.Priorities[0])
+
+	// default profile
+	p, err = c.Scheduler.GetProfile("")
+	assert.NoError(t, err)
+	assert.False(t, p.NormalizeScore)
+	assert.Equal(t, []string{"Basic"}, p.Filters)
+	assert.Equal(t, []WeightedPriority{{Name: "LeastResource", Weight: 1}, {Name: "PositionAdvice", Weight: 1}}, p.Priorities)
+
+	_, err = c.Scheduler.GetProfile("not-exist")
+	assert.Error(t, err)
+}
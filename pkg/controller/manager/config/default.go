@@ -1,5 +1,9 @@
 package config
 
+import (
+	agentcfg "lite.io/liteio/pkg/agent/config"
+)
+
 func SetDefaults(cfg *Config) {
 	// set max remote volume
 	if cfg.Scheduler.MaxRemoteVolumeCount <= 0 {
@@ -19,4 +23,26 @@ func SetDefaults(cfg *Config) {
 			"PositionAdvice",
 		}
 	}
+
+	if cfg.Scheduler.DataHolderSpread.Level == "" {
+		cfg.Scheduler.DataHolderSpread.Level = SpreadLevelNode
+	}
+	agentcfg.SetNodeInfoDefaults(&cfg.Scheduler.DataHolderSpread.NodeKeys)
+
+	if cfg.Rebalance.IntervalSeconds <= 0 {
+		cfg.Rebalance.IntervalSeconds = 600
+	}
+	if cfg.Rebalance.HighWatermarkPct <= 0 {
+		cfg.Rebalance.HighWatermarkPct = 85
+	}
+	if cfg.Rebalance.LowWatermarkPct <= 0 {
+		cfg.Rebalance.LowWatermarkPct = 60
+	}
+	if cfg.Rebalance.MaxConcurrentMigrations <= 0 {
+		cfg.Rebalance.MaxConcurrentMigrations = 2
+	}
+
+	if cfg.ConsistencyCheck.IntervalSeconds <= 0 {
+		cfg.ConsistencyCheck.IntervalSeconds = 300
+	}
 }
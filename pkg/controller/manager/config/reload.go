@@ -0,0 +1,145 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// EventReasonConfigReloaded is recorded when new config is applied
+	EventReasonConfigReloaded = "ConfigReloaded"
+	// EventReasonConfigInvalid is recorded when new config is rejected
+	EventReasonConfigInvalid = "ConfigInvalid"
+)
+
+// Reloadable is implemented by components which apply new config without restarting
+type Reloadable interface {
+	UpdateConfig(cfg Config)
+}
+
+// Reloader polls the config file, which is usually mounted from a ConfigMap, and applies changed config to targets.
+// Config failing validation is rejected, and the previous config is kept.
+type Reloader struct {
+	path     string
+	interval time.Duration
+	validate func(Config) error
+	targets  []Reloadable
+
+	recorder record.EventRecorder
+	// eventRef is the object which events are recorded on, e.g. the ConfigMap
+	eventRef *corev1.ObjectReference
+
+	lock        sync.Mutex
+	current     Config
+	lastContent []byte
+}
+
+// NewReloader creates a Reloader of the config file. cfg is the config in use. validate is called after defaults are set.
+func NewReloader(path string, interval time.Duration, cfg Config, validate func(Config) error) *Reloader {
+	return &Reloader{
+		path:     path,
+		interval: interval,
+		validate: validate,
+		current:  cfg,
+	}
+}
+
+// WithEventRecorder records an event on ref for every reloading
+func (r *Reloader) WithEventRecorder(recorder record.EventRecorder, ref *corev1.ObjectReference) *Reloader {
+	r.recorder = recorder
+	r.eventRef = ref
+	return r
+}
+
+// Register adds targets which receive the new config
+func (r *Reloader) Register(targets ...Reloadable) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.targets = append(r.targets, targets...)
+}
+
+// Start implements Runnable
+func (r *Reloader) Start(ctx context.Context) (err error) {
+	var tick = time.NewTicker(r.interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			if _, err = r.Reload(); err != nil {
+				klog.Error(err)
+			}
+		case <-ctx.Done():
+			klog.Info("config reloader quit")
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection implements LeaderElectionRunnable. Every replica keeps its config up to date.
+func (r *Reloader) NeedLeaderElection() bool {
+	return false
+}
+
+// Reload reads the config file. If the content is changed and valid, the new config is applied to targets.
+func (r *Reloader) Reload() (reloaded bool, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	bs, err := os.ReadFile(r.path)
+	if err != nil {
+		return
+	}
+	if r.lastContent != nil && bytes.Equal(bs, r.lastContent) {
+		return
+	}
+	r.lastContent = bs
+
+	cfg, err := fromYamlBytes(bs)
+	if err == nil {
+		SetDefaults(&cfg)
+		if r.validate != nil {
+			err = r.validate(cfg)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("config %s is invalid, keep the previous one: %w", r.path, err)
+		r.recordEvent(corev1.EventTypeWarning, EventReasonConfigInvalid, err.Error())
+		return
+	}
+
+	if reflect.DeepEqual(cfg, r.current) {
+		return
+	}
+
+	for _, item := range r.targets {
+		item.UpdateConfig(cfg)
+	}
+
+	var msg = fmt.Sprintf("config %s is reloaded", r.path)
+	var prev, next = r.current, cfg
+	prev.Scheduler, next.Scheduler = SchedulerConfig{}, SchedulerConfig{}
+	if !reflect.DeepEqual(prev, next) {
+		msg += ", changes out of scheduler section take effect after restart"
+	}
+	klog.Infof("%s, new config %+v", msg, cfg)
+	r.recordEvent(corev1.EventTypeNormal, EventReasonConfigReloaded, msg)
+	r.current = cfg
+	reloaded = true
+
+	return
+}
+
+func (r *Reloader) recordEvent(eventType, reason, msg string) {
+	if r.recorder != nil && r.eventRef != nil {
+		r.recorder.Event(r.eventRef, eventType, reason, msg)
+	}
+}
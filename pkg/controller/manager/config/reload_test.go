@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+type fakeReloadable struct {
+	cfgs []Config
+}
+
+func (f *fakeReloadable) UpdateConfig(cfg Config) {
+	f.cfgs = append(f.cfgs, cfg)
+}
+
+func TestReloader(t *testing.T) {
+	var (
+		file     = filepath.Join(t.TempDir(), "config.yaml")
+		target   = &fakeReloadable{}
+		recorder = record.NewFakeRecorder(10)
+		validate = func(c Config) error {
+			for _, name := range c.Scheduler.Filters {
+				if name != "Basic" {
+					return fmt.Errorf("not found filter by name %s", name)
+				}
+			}
+			return nil
+		}
+		write = func(content string) {
+			assert.NoError(t, os.WriteFile(file, []byte(content), 0644))
+		}
+	)
+
+	write("scheduler:\n  filters: [Basic]\n")
+	cfg, err := Load(file)
+	assert.NoError(t, err)
+	SetDefaults(&cfg)
+
+	reloader := NewReloader(file, time.Second, cfg, validate).
+		WithEventRecorder(recorder, &corev1.ObjectReference{Kind: "ConfigMap", Namespace: "obnvmf", Name: "storage-setting"})
+	reloader.Register(target)
+
+	// same config
+	reloaded, err := reloader.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	// valid config
+	write("scheduler:\n  filters: [Basic]\n  maxRemoteVolumeCount: 5\n")
+	reloaded, err = reloader.Reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	if assert.Len(t, target.cfgs, 1) {
+		assert.Equal(t, 5, target.cfgs[0].Scheduler.MaxRemoteVolumeCount)
+	}
+	assert.Contains(t, <-recorder.Events, EventReasonConfigReloaded)
+
+	// invalid config is rejected
+	write("scheduler:\n  filters: [Basic, Unknown]\n")
+	reloaded, err = reloader.Reload()
+	assert.Error(t, err)
+	assert.False(t, reloaded)
+	assert.Len(t, target.cfgs, 1)
+	assert.Contains(t, <-recorder.Events, EventReasonConfigInvalid)
+
+	// unchanged content is not validated again
+	reloaded, err = reloader.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+	assert.Empty(t, recorder.Events)
+}
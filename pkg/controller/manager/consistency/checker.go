@@ -0,0 +1,347 @@
+package consistency
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+// Kind is the type of inconsistency between State and APIServer
+type Kind string
+
+const (
+	// KindPoolMissing means the StoragePool is not in State
+	KindPoolMissing Kind = "PoolMissing"
+	// KindPoolStale means the node in State has no StoragePool in APIServer
+	KindPoolStale Kind = "PoolStale"
+	// KindVolumeMissing means the volume has a target node, but it is not in State
+	KindVolumeMissing Kind = "VolumeMissing"
+	// KindVolumeWrongNode means the volume is bound to another node in State, or the index of volume is broken
+	KindVolumeWrongNode Kind = "VolumeWrongNode"
+	// KindVolumeLeaked means the volume in State is not found in APIServer
+	KindVolumeLeaked Kind = "VolumeLeaked"
+	// KindReservationLeaked means the owner of reservation, a StorageReservation, a PVC or config, is not found
+	KindReservationLeaked Kind = "ReservationLeaked"
+)
+
+var AllKinds = []Kind{
+	KindPoolMissing,
+	KindPoolStale,
+	KindVolumeMissing,
+	KindVolumeWrongNode,
+	KindVolumeLeaked,
+	KindReservationLeaked,
+}
+
+// Mismatch is an inconsistency found by Checker
+type Mismatch struct {
+	Kind   Kind
+	NodeID string
+	// Key is the name of pool, UUID of volume or ID of reservation
+	Key     string
+	Message string
+
+	pool   *v1.StoragePool
+	volume *v1.AntstorVolume
+}
+
+func (m Mismatch) id() string {
+	return fmt.Sprintf("%s/%s/%s", m.Kind, m.NodeID, m.Key)
+}
+
+// Checker periodically diffs StoragePools and AntstorVolumes in the cache against State.
+// Mismatches are exported as metrics. If AutoRepair is enabled, a mismatch found in two consecutive checks is repaired,
+// because the cache or State may lag behind each other for a short time.
+type Checker struct {
+	client client.Client
+	state  state.StateIface
+	cfg    config.Config
+	// suspects are the mismatches found in the last check
+	suspects map[string]bool
+
+	cfgLock sync.RWMutex
+}
+
+// UpdateConfig implements config.Reloadable. Only the scheduler section is reloaded, for reservations in config.
+func (c *Checker) UpdateConfig(cfg config.Config) {
+	c.cfgLock.Lock()
+	defer c.cfgLock.Unlock()
+	c.cfg.Scheduler = cfg.Scheduler
+}
+
+func (c *Checker) nodeReservations() []config.NodeReservation {
+	c.cfgLock.RLock()
+	defer c.cfgLock.RUnlock()
+	return c.cfg.Scheduler.NodeReservations
+}
+
+func NewChecker(cli client.Client, s state.StateIface, cfg config.Config) *Checker {
+	return &Checker{
+		client:   cli,
+		state:    s,
+		cfg:      cfg,
+		suspects: make(map[string]bool),
+	}
+}
+
+// Start implements Runnable
+func (c *Checker) Start(ctx context.Context) (err error) {
+	var tick = time.NewTicker(time.Duration(c.cfg.ConsistencyCheck.IntervalSeconds) * time.Second)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			if _, err = c.Run(ctx); err != nil {
+				klog.Error(err)
+			}
+		case <-ctx.Done():
+			klog.Info("consistency checker quit")
+			return
+		}
+	}
+}
+
+// NeedLeaderElection implements LeaderElectionRunnable. State is only maintained by the leader.
+func (c *Checker) NeedLeaderElection() bool {
+	return true
+}
+
+// Run checks State once, updates metrics and repairs the confirmed mismatches if AutoRepair is enabled
+func (c *Checker) Run(ctx context.Context) (list []Mismatch, err error) {
+	list, err = c.Check(ctx)
+	if err != nil {
+		return
+	}
+
+	var counts = make(map[Kind]int)
+	for _, item := range list {
+		counts[item.Kind]++
+		klog.Warningf("state inconsistency %s on node %q, %s: %s", item.Kind, item.NodeID, item.Key, item.Message)
+	}
+	for _, kind := range AllKinds {
+		inconsistencyGauge.WithLabelValues(string(kind)).Set(float64(counts[kind]))
+	}
+	lastCheckGauge.SetToCurrentTime()
+
+	var suspects = make(map[string]bool, len(list))
+	for _, item := range list {
+		if !c.cfg.ConsistencyCheck.AutoRepair || !c.suspects[item.id()] {
+			suspects[item.id()] = true
+			continue
+		}
+		if errRepair := c.repair(item); errRepair != nil {
+			klog.Errorf("repair %s of %s failed: %+v", item.Kind, item.Key, errRepair)
+			suspects[item.id()] = true
+			continue
+		}
+		klog.Infof("repaired %s of %s on node %q", item.Kind, item.Key, item.NodeID)
+		repairCounter.WithLabelValues(string(item.Kind)).Inc()
+	}
+	c.suspects = suspects
+
+	return
+}
+
+// Check returns the mismatches between State and the cache
+func (c *Checker) Check(ctx context.Context) (list []Mismatch, err error) {
+	var (
+		poolList v1.StoragePoolList
+		volList  v1.AntstorVolumeList
+		resvList v1.StorageReservationList
+		pvcList  corev1.PersistentVolumeClaimList
+	)
+	if err = c.client.List(ctx, &poolList); err != nil {
+		return
+	}
+	if err = c.client.List(ctx, &volList); err != nil {
+		return
+	}
+	if err = c.client.List(ctx, &resvList); err != nil {
+		return
+	}
+	if err = c.client.List(ctx, &pvcList); err != nil {
+		return
+	}
+
+	var (
+		nodes     = c.state.GetAllNodes()
+		nodeMap   = make(map[string]*state.Node, len(nodes))
+		index     = c.state.GetVolumeIndex()
+		poolMap   = make(map[string]*v1.StoragePool, len(poolList.Items))
+		volMap    = make(map[string]*v1.AntstorVolume, len(volList.Items))
+		locations = make(map[string][]string)
+		resvOwner = make(map[string]bool)
+		resvsOf   = make(map[string][]state.ReservationIface, len(nodes))
+	)
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Info.ID < nodes[j].Info.ID
+	})
+
+	// pools
+	for i := range poolList.Items {
+		pool := &poolList.Items[i]
+		poolMap[pool.Spec.NodeInfo.ID] = pool
+	}
+	for _, node := range nodes {
+		var view = node.View()
+		nodeMap[node.Info.ID] = node
+		resvsOf[node.Info.ID] = view.Reservations
+		for _, vol := range view.Volumes {
+			locations[vol.Spec.Uuid] = append(locations[vol.Spec.Uuid], node.Info.ID)
+		}
+		if _, has := poolMap[node.Info.ID]; !has {
+			list = append(list, Mismatch{
+				Kind:    KindPoolStale,
+				NodeID:  node.Info.ID,
+				Key:     node.Info.ID,
+				Message: fmt.Sprintf("node has %d volumes, but StoragePool is not found", len(view.Volumes)),
+			})
+		}
+	}
+	for _, pool := range poolList.Items {
+		// pool being deleted is removed from State when it has no volume
+		if _, has := nodeMap[pool.Spec.NodeInfo.ID]; !has && pool.DeletionTimestamp == nil {
+			list = append(list, Mismatch{
+				Kind:    KindPoolMissing,
+				NodeID:  pool.Spec.NodeInfo.ID,
+				Key:     pool.Name,
+				Message: "StoragePool is not in State",
+				pool:    pool.DeepCopy(),
+			})
+		}
+	}
+
+	// volumes
+	for i := range volList.Items {
+		vol := &volList.Items[i]
+		if vol.Spec.Uuid == "" {
+			continue
+		}
+		volMap[vol.Spec.Uuid] = vol
+
+		var (
+			target    = vol.Spec.TargetNodeId
+			indexed   = index[vol.Spec.Uuid]
+			locatedAt = locations[vol.Spec.Uuid]
+		)
+		switch {
+		case target == "":
+			// not scheduled, or the volume is just bound in State and TargetNodeId is not patched yet
+		case target != "" && indexed == "" && len(locatedAt) == 0:
+			list = append(list, Mismatch{
+				Kind:    KindVolumeMissing,
+				NodeID:  target,
+				Key:     vol.Spec.Uuid,
+				Message: fmt.Sprintf("volume %s/%s is not in State", vol.Namespace, vol.Name),
+				volume:  vol.DeepCopy(),
+			})
+		case indexed != target || len(locatedAt) != 1 || locatedAt[0] != target:
+			list = append(list, Mismatch{
+				Kind:   KindVolumeWrongNode,
+				NodeID: target,
+				Key:    vol.Spec.Uuid,
+				Message: fmt.Sprintf("volume %s/%s targets node %q, but it is indexed to node %q and found on nodes %v",
+					vol.Namespace, vol.Name, target, indexed, locatedAt),
+				volume: vol.DeepCopy(),
+			})
+		}
+	}
+	var leaked = make(map[string]string)
+	for volID, nodeID := range index {
+		leaked[volID] = nodeID
+	}
+	for volID, nodeIDs := range locations {
+		leaked[volID] = nodeIDs[0]
+	}
+	for volID, nodeID := range leaked {
+		if _, has := volMap[volID]; !has {
+			list = append(list, Mismatch{
+				Kind:    KindVolumeLeaked,
+				NodeID:  nodeID,
+				Key:     volID,
+				Message: "volume in State is not found in APIServer",
+			})
+		}
+	}
+
+	// reservations
+	for _, item := range c.nodeReservations() {
+		resvOwner[item.ID] = true
+	}
+	for _, item := range resvList.Items {
+		resvOwner[item.ReservationID()] = true
+	}
+	for _, item := range pvcList.Items {
+		resvOwner[item.Namespace+"/"+item.Name] = true
+	}
+	for _, node := range nodes {
+		for _, resv := range resvsOf[node.Info.ID] {
+			if !resvOwner[resv.ID()] {
+				list = append(list, Mismatch{
+					Kind:    KindReservationLeaked,
+					NodeID:  node.Info.ID,
+					Key:     resv.ID(),
+					Message: fmt.Sprintf("owner of reservation (size %d) is not found", resv.Size()),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].id() < list[j].id()
+	})
+
+	return
+}
+
+func (c *Checker) repair(m Mismatch) (err error) {
+	switch m.Kind {
+	case KindPoolMissing:
+		c.state.SetStoragePool(m.pool)
+	case KindPoolStale:
+		var node *state.Node
+		if node, err = c.state.GetNodeByNodeID(m.NodeID); err != nil {
+			return
+		}
+		if view := node.View(); len(view.Volumes) > 0 || len(view.Reservations) > 0 {
+			return fmt.Errorf("node %s still has %d volumes and %d reservations", m.NodeID, len(view.Volumes), len(view.Reservations))
+		}
+		err = c.state.RemoveStoragePool(m.NodeID)
+	case KindVolumeMissing, KindVolumeWrongNode, KindVolumeLeaked:
+		c.unbindAll(m.Key)
+		if m.volume != nil && m.volume.Spec.TargetNodeId != "" {
+			err = c.state.BindAntstorVolume(m.volume.Spec.TargetNodeId, m.volume)
+		}
+	case KindReservationLeaked:
+		var node *state.Node
+		if node, err = c.state.GetNodeByNodeID(m.NodeID); err != nil {
+			return
+		}
+		node.Unreserve(m.Key)
+	}
+	return
+}
+
+// unbindAll removes the volume from the index and all nodes
+func (c *Checker) unbindAll(volID string) {
+	if _, has := c.state.GetVolumeIndex()[volID]; has {
+		if err := c.state.UnbindAntstorVolume(volID); err != nil {
+			klog.Error(err)
+		}
+	}
+	for _, node := range c.state.GetAllNodes() {
+		if _, err := node.GetVolumeByID(volID); err == nil {
+			node.RemoveVolumeByID(volID)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package consistency
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+const gib = 1 << 30
+
+func newPool(nodeID string) *v1.StoragePool {
+	return &v1.StoragePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: v1.DefaultNamespace,
+			Name:      nodeID,
+		},
+		Spec: v1.StoragePoolSpec{
+			NodeInfo:  v1.NodeInfo{ID: nodeID},
+			KernelLVM: v1.KernelLVM{Name: "vg", Bytes: 100 * gib},
+		},
+		Status: v1.StoragePoolStatus{
+			Status:     v1.PoolStatusReady,
+			VGFreeSize: *resource.NewQuantity(100*gib, resource.BinarySI),
+		},
+	}
+}
+
+func newVolume(name, nodeID string) *v1.AntstorVolume {
+	return &v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: v1.DefaultNamespace,
+			Name:      name,
+		},
+		Spec: v1.AntstorVolumeSpec{
+			Uuid:         name + "-uuid",
+			Type:         v1.VolumeTypeKernelLVol,
+			SizeByte:     10 * gib,
+			TargetNodeId: nodeID,
+		},
+	}
+}
+
+func TestChecker(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newPool("node-1"),
+		newPool("node-2"),
+		newVolume("vol-ok", "node-1"),
+		newVolume("vol-missing", "node-2"),
+		newVolume("vol-moved", "node-1"),
+		// just scheduled, TargetNodeId is not patched yet
+		newVolume("vol-scheduling", ""),
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pvc-1"}},
+	).Build()
+
+	// node-2 is missing, node-3 is stale
+	s := state.NewState()
+	s.SetStoragePool(newPool("node-1"))
+	s.SetStoragePool(newPool("node-3"))
+	assert.NoError(t, s.BindAntstorVolume("node-1", newVolume("vol-ok", "node-1")))
+	assert.NoError(t, s.BindAntstorVolume("node-3", newVolume("vol-moved", "node-3")))
+	assert.NoError(t, s.BindAntstorVolume("node-1", newVolume("vol-deleted", "node-1")))
+	assert.NoError(t, s.BindAntstorVolume("node-1", newVolume("vol-scheduling", "node-1")))
+	node1, _ := s.GetNodeByNodeID("node-1")
+	node1.Reserve(state.NewReservation("default/pvc-1", gib))
+	node1.Reserve(state.NewReservation("default/pvc-deleted", gib))
+	// StorageReservation default/pvc-1 is deleted, its reservation is not owned by the PVC with the same name
+	node1.Reserve(state.NewReservation(v1.StorageReservationID("default", "pvc-1"), gib))
+
+	checker := NewChecker(cli, s, config.Config{
+		ConsistencyCheck: config.ConsistencyCheckConfig{Enabled: true, AutoRepair: true},
+	})
+	list, err := checker.Run(context.Background())
+	assert.NoError(t, err)
+
+	var found = make(map[Kind][]string)
+	for _, item := range list {
+		found[item.Kind] = append(found[item.Kind], item.Key)
+	}
+	assert.Equal(t, map[Kind][]string{
+		KindPoolMissing:       {"node-2"},
+		KindPoolStale:         {"node-3"},
+		KindVolumeMissing:     {"vol-missing-uuid"},
+		KindVolumeWrongNode:   {"vol-moved-uuid"},
+		KindVolumeLeaked:      {"vol-deleted-uuid"},
+		KindReservationLeaked: {"default/pvc-deleted", "storagereservation/default/pvc-1"},
+	}, found)
+
+	// first found mismatches are not repaired
+	list, err = checker.Check(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, list, 7)
+
+	// stale node-3 is removed after vol-moved is bound to node-1
+	for i := 0; i < 2; i++ {
+		_, err = checker.Run(context.Background())
+		assert.NoError(t, err)
+	}
+	list, err = checker.Check(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+
+	vol, err := s.GetVolumeByID("vol-moved-uuid")
+	assert.NoError(t, err)
+	assert.Equal(t, "node-1", vol.Spec.TargetNodeId)
+	_, err = s.GetVolumeByID("vol-missing-uuid")
+	assert.NoError(t, err)
+	_, has := node1.GetReservation("default/pvc-1")
+	assert.True(t, has)
+}
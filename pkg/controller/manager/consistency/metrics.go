@@ -0,0 +1,27 @@
+package consistency
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	inconsistencyGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "antstor_state_inconsistencies",
+		Help: "Count of inconsistencies between scheduler state and APIServer found in the last check",
+	}, []string{"kind"})
+
+	repairCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "antstor_state_repairs_total",
+		Help: "Count of inconsistencies repaired by consistency checker",
+	}, []string{"kind"})
+
+	lastCheckGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "antstor_state_last_check_timestamp_seconds",
+		Help: "Unix time of the last consistency check",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(inconsistencyGauge, repairCounter, lastCheckGauge)
+}
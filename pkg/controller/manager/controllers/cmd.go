@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"lite.io/liteio/pkg/agent"
 	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
 	"lite.io/liteio/pkg/controller/manager/config"
+	sched "lite.io/liteio/pkg/controller/manager/scheduler"
 	"lite.io/liteio/pkg/controller/manager/state"
 	hostnvme "lite.io/liteio/pkg/host-nvme"
 	"lite.io/liteio/pkg/util"
@@ -78,6 +80,8 @@ func newRootCmd() *cobra.Command {
 	rootCmd.AddCommand(NewOperatorCommand())
 	rootCmd.AddCommand(agent.NewAgentCommand())
 	rootCmd.AddCommand(hostnvme.NewHostNvmeCommand())
+	rootCmd.AddCommand(NewExplainCommand())
+	rootCmd.AddCommand(NewSimulateCommand())
 	return rootCmd
 }
 
@@ -102,6 +106,8 @@ func NewOperatorCommand() *cobra.Command {
 	cmd.Flags().StringVar(&option.SyncDBConnInfo, "obConnInfo", "", "DB connection info for syncing meta data")
 	cmd.Flags().StringVar(&option.K8SCluster, "k8sCluster", "", "Name of k8s cluster")
 	cmd.Flags().StringVar(&option.ConfigPath, "config", "/controller-config.yaml", "config file path, default is /controller-config.yaml")
+	cmd.Flags().StringVar(&option.ConfigMap, "configMap", "obnvmf/storage-setting", "namespace/name of ConfigMap mounted as config file, events of reloading are recorded on it")
+	cmd.Flags().IntVar(&option.ConfigReloadSeconds, "configReloadSeconds", 30, "interval of checking config file for reloading, 0 disables reloading")
 	cmd.Flags().StringVar(&option.WebhookTLSDir, "tlsdir", "", "dir of tls.key and tls.crt")
 	cmd.Flags().BoolVar(&option.EnableWebhook, "enableWebhook", false, "enable webhook service")
 	// cmd.Flags().StringVar(&co.KubeAPIURL, "kubeApiUrl", "", "APIServer URL")
@@ -123,6 +129,10 @@ type OperatorOption struct {
 	K8SCluster     string
 
 	ConfigPath string
+	// ConfigMap is namespace/name of the ConfigMap which config file is mounted from
+	ConfigMap string
+	// ConfigReloadSeconds is the interval of reloading config file. Zero value disables reloading.
+	ConfigReloadSeconds int
 
 	// The address the probe endpoint binds to
 	EnableLeaderElection bool
@@ -148,6 +158,9 @@ func (o *OperatorOption) Run() {
 		klog.Fatalf("load config failed: %s", err.Error())
 	}
 	config.SetDefaults(&cfg)
+	if err = sched.ValidateConfig(cfg); err != nil {
+		klog.Fatalf("invalid config: %s", err.Error())
+	}
 
 	klog.Infof("use config: %+v", cfg)
 
@@ -164,7 +177,10 @@ func (o *OperatorOption) Run() {
 		Scheme:          scheme,
 		State:           state.NewState(),
 
-		ControllerConfig: cfg,
+		ControllerConfig:     cfg,
+		ConfigPath:           o.ConfigPath,
+		ConfigMap:            o.ConfigMap,
+		ConfigReloadInterval: time.Duration(o.ConfigReloadSeconds) * time.Second,
 	}
 	mgr := NewAndInitControllerManager(req)
 
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/scheduler"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+type ExplainOption struct {
+	// Addr is the address of controller metrics service
+	Addr string
+	// File is the path of request in JSON. Other flags are ignored if it is set.
+	File   string
+	Output string
+
+	Size         string
+	Type         string
+	IsThin       bool
+	Position     string
+	HostNode     string
+	NodeSelector string
+	PoolSelector string
+	Labels       []string
+	Annotations  []string
+}
+
+func NewExplainCommand() *cobra.Command {
+	var option ExplainOption
+	cmd := &cobra.Command{
+		Use:   "explain",
+		Short: "Explain where a hypothetical volume would be scheduled",
+		Long:  `Send a dry-run scheduling request to node-disk-controller operator, and print the result of each storage pool`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return option.Run(os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVar(&option.Addr, "addr", "http://127.0.0.1:9090", "address of operator metrics service")
+	cmd.Flags().StringVarP(&option.File, "file", "f", "", "file path of request in JSON")
+	cmd.Flags().StringVarP(&option.Output, "output", "o", "table", "output format, table or json")
+	cmd.Flags().StringVar(&option.Size, "size", "", "size of volume, e.g. 10Gi")
+	cmd.Flags().StringVar(&option.Type, "type", "", "type of volume, Flexible, KernelLVol or SpdkLVol")
+	cmd.Flags().BoolVar(&option.IsThin, "thin", false, "volume is thin provisioned")
+	cmd.Flags().StringVar(&option.Position, "position", "", "position advice, NoPreference, MustLocal, MustRemote, PreferLocal or PreferRemote")
+	cmd.Flags().StringVar(&option.HostNode, "hostNode", "", "node id where the consumer pod runs")
+	cmd.Flags().StringVar(&option.NodeSelector, "nodeSelector", "", "label selector of nodes, e.g. key1=value1,key2=value2")
+	cmd.Flags().StringVar(&option.PoolSelector, "poolSelector", "", "label selector of storage pools, e.g. key1=value1,key2=value2")
+	cmd.Flags().StringSliceVar(&option.Labels, "label", nil, "labels of volume, in format of key=value")
+	cmd.Flags().StringSliceVar(&option.Annotations, "anno", nil, "annotations of volume, in format of key=value")
+	return cmd
+}
+
+func (o *ExplainOption) Run(out io.Writer) (err error) {
+	req, err := o.request()
+	if err != nil {
+		return
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return
+	}
+
+	url := strings.TrimSuffix(o.Addr, "/") + scheduler.ExplainURI
+	cli := http.Client{Timeout: 30 * time.Second}
+	resp, err := cli.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp misc.ErrorResponse
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error != "" {
+			return fmt.Errorf("explain failed, code %d: %s", resp.StatusCode, errResp.Error)
+		}
+		return fmt.Errorf("explain failed, code %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result scheduler.ExplainResult
+	if err = json.Unmarshal(respBody, &result); err != nil {
+		return
+	}
+
+	switch o.Output {
+	case "json":
+		var buf bytes.Buffer
+		if err = json.Indent(&buf, respBody, "", "  "); err != nil {
+			return
+		}
+		fmt.Fprintln(out, buf.String())
+	default:
+		printExplainResult(out, result)
+	}
+
+	return
+}
+
+func (o *ExplainOption) request() (req scheduler.ExplainRequest, err error) {
+	if o.File != "" {
+		var bs []byte
+		bs, err = os.ReadFile(o.File)
+		if err != nil {
+			return
+		}
+		err = json.Unmarshal(bs, &req)
+		return
+	}
+
+	req = scheduler.ExplainRequest{
+		Size:           o.Size,
+		Type:           v1.VolumeType(o.Type),
+		IsThin:         o.IsThin,
+		PositionAdvice: v1.VolumePosition(o.Position),
+		HostNode:       o.HostNode,
+		Labels:         make(map[string]string),
+		Annotations:    make(map[string]string),
+	}
+	if req.Size == "" {
+		err = fmt.Errorf("--size is required")
+		return
+	}
+	if o.NodeSelector != "" {
+		req.Annotations[v1.NodeLabelSelectorKey] = o.NodeSelector
+	}
+	if o.PoolSelector != "" {
+		req.Annotations[v1.PoolLabelSelectorKey] = o.PoolSelector
+	}
+	if err = parseKeyValues(o.Labels, req.Labels); err != nil {
+		return
+	}
+	err = parseKeyValues(o.Annotations, req.Annotations)
+	return
+}
+
+func parseKeyValues(items []string, kv map[string]string) error {
+	for _, item := range items {
+		idx := strings.Index(item, "=")
+		if idx <= 0 {
+			return fmt.Errorf("invalid key=value %q", item)
+		}
+		kv[item[:idx]] = item[idx+1:]
+	}
+	return nil
+}
+
+func printExplainResult(out io.Writer, result scheduler.ExplainResult) {
+	profile := result.Profile
+	if profile == "" {
+		profile = "<default>"
+	}
+	fmt.Fprintf(out, "Profile: %s\n", profile)
+	if result.SelectedNode != "" {
+		fmt.Fprintf(out, "Selected: %s\n", result.SelectedNode)
+	} else {
+		fmt.Fprintf(out, "Selected: <none>, %s\n", result.Message)
+	}
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POOL\tPASSED\tSCORE\tREASONS")
+	for _, item := range result.Pools {
+		score := "-"
+		if item.Score != nil {
+			score = fmt.Sprint(*item.Score)
+		}
+		reasons := "-"
+		if len(item.Reasons) > 0 {
+			reasons = strings.Join(item.Reasons, ",")
+		}
+		fmt.Fprintf(w, "%s\t%t\t%s\t%s\n", item.Name, item.Passed, score, reasons)
+	}
+	w.Flush()
+}
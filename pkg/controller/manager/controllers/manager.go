@@ -2,6 +2,7 @@
 
 import (
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -10,6 +11,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	rt "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,6 +23,8 @@ import (
 	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
 	"lite.io/liteio/pkg/controller/kubeutil"
 	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/consistency"
+	"lite.io/liteio/pkg/controller/manager/rebalance"
 	"lite.io/liteio/pkg/controller/manager/reconciler"
 	"lite.io/liteio/pkg/controller/manager/reconciler/handler"
 	"lite.io/liteio/pkg/controller/manager/reconciler/plugin"
@@ -54,6 +58,11 @@ type NewManagerRequest struct {
 	Scheme     *runtime.Scheme
 
 	ControllerConfig config.Config
+	// ConfigPath is the file of ControllerConfig, reloaded every ConfigReloadInterval
+	ConfigPath           string
+	ConfigReloadInterval time.Duration
+	// ConfigMap is namespace/name of the ConfigMap which ConfigPath is mounted from
+	ConfigMap string
 }
 
 func NewAndInitControllerManager(req NewManagerRequest) manager.Manager {
@@ -89,6 +98,13 @@ func NewAndInitControllerManager(req NewManagerRequest) manager.Manager {
 		scheduler  = sched.NewScheduler(req.ControllerConfig)
 	)
 
+	poolHandler := &reconciler.StoragePoolReconcileHandler{
+		Client:   mgr.GetClient(),
+		Cfg:      req.ControllerConfig,
+		State:    stateObj,
+		PoolUtil: poolUtil,
+		KubeCli:  kubeClient,
+	}
 	poolReconciler := reconciler.PlugableReconciler{
 		Client:   mgr.GetClient(),
 		Plugable: plugin.NewPluginList(),
@@ -98,14 +114,8 @@ func NewAndInitControllerManager(req NewManagerRequest) manager.Manager {
 		State:   stateObj,
 
 		Concurrency: 4,
-		MainHandler: &reconciler.StoragePoolReconcileHandler{
-			Client:   mgr.GetClient(),
-			Cfg:      req.ControllerConfig,
-			State:    stateObj,
-			PoolUtil: poolUtil,
-			KubeCli:  kubeClient,
-		},
-		ForType: &v1.StoragePool{},
+		MainHandler: poolHandler,
+		ForType:     &v1.StoragePool{},
 		Watches: []reconciler.WatchObject{
 			{
 				Source: &source.Kind{Type: &corev1.Node{}},
@@ -135,6 +145,8 @@ func NewAndInitControllerManager(req NewManagerRequest) manager.Manager {
 			State:       stateObj,
 			AntstoreCli: antstorCli,
 			Scheduler:   scheduler,
+			// EventRecorder for AntstorVolume and PVC
+			EventRecorder: mgr.GetEventRecorderFor("AntstorVolume"),
 		},
 		ForType: &v1.AntstorVolume{},
 		Watches: []reconciler.WatchObject{
@@ -224,12 +236,18 @@ func NewAndInitControllerManager(req NewManagerRequest) manager.Manager {
 		AntstorClient: antstorCli,
 	}
 
+	// scheduler, StoragePool handler and plugins implementing config.Reloadable receive reloaded config
+	var reloadables = []config.Reloadable{scheduler, poolHandler}
+
 	for _, fn := range PoolReconcilerPluginCreaters {
 		p, err := fn(pluginHandle)
 		if err != nil {
 			klog.Fatal(err)
 		}
 		poolReconciler.RegisterPlugin(p)
+		if r, ok := p.(config.Reloadable); ok {
+			reloadables = append(reloadables, r)
+		}
 	}
 
 	for _, fn := range VolumeReconcilerPluginCreaters {
@@ -238,6 +256,9 @@ func NewAndInitControllerManager(req NewManagerRequest) manager.Manager {
 			klog.Fatal(err)
 		}
 		volReconciler.RegisterPlugin(p)
+		if r, ok := p.(config.Reloadable); ok {
+			reloadables = append(reloadables, r)
+		}
 	}
 
 	for _, fn := range VolumeGroupReconcilerPluginCreaters {
@@ -246,6 +267,9 @@ func NewAndInitControllerManager(req NewManagerRequest) manager.Manager {
 			klog.Fatal(err)
 		}
 		volGroupReconciler.RegisterPlugin(p)
+		if r, ok := p.(config.Reloadable); ok {
+			reloadables = append(reloadables, r)
+		}
 	}
 
 	for _, fn := range DataControlReconcilerPluginCreaters {
@@ -254,6 +278,9 @@ func NewAndInitControllerManager(req NewManagerRequest) manager.Manager {
 			klog.Fatal(err)
 		}
 		dataControlReconciler.RegisterPlugin(p)
+		if r, ok := p.(config.Reloadable); ok {
+			reloadables = append(reloadables, r)
+		}
 	}
 
 	// setup SnapshotReconsiler
@@ -272,7 +299,45 @@ func NewAndInitControllerManager(req NewManagerRequest) manager.Manager {
 		os.Exit(1)
 	}
 
-	migrationReconcile := &reconciler.VolumeMigrationReconciler{
+	rollbackReconciler := &reconciler.SnapshotRollbackReconciler{
+		Client:  mgr.GetClient(),
+		Log:     rt.Log.WithName("controllers").WithName("SnapshotRollback"),
+		KubeCli: kubeClient,
+	}
+	if err = rollbackReconciler.SetupWithManager(mgr); err != nil {
+		klog.Error(err, "unable to create SnapshotRollback controller")
+		os.Exit(1)
+	}
+
+	scheduleReconciler := &reconciler.SnapshotScheduleReconciler{
+		Client: mgr.GetClient(),
+		Log:    rt.Log.WithName("controllers").WithName("SnapshotSchedule"),
+	}
+	if err = scheduleReconciler.SetupWithManager(mgr); err != nil {
+		klog.Error(err, "unable to create SnapshotSchedule controller")
+		os.Exit(1)
+	}
+
+	groupReconciler := &reconciler.SnapshotGroupReconciler{
+		Client: mgr.GetClient(),
+		Log:    rt.Log.WithName("controllers").WithName("SnapshotGroup"),
+	}
+	if err = groupReconciler.SetupWithManager(mgr); err != nil {
+		klog.Error(err, "unable to create SnapshotGroup controller")
+		os.Exit(1)
+	}
+
+	backupReconciler := &reconciler.SnapshotBackupReconciler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Log:       rt.Log.WithName("controllers").WithName("SnapshotBackup"),
+	}
+	if err = backupReconciler.SetupWithManager(mgr); err != nil {
+		klog.Error(err, "unable to create SnapshotBackup controller")
+		os.Exit(1)
+	}
+
+	migrationReconcile :=&reconciler.VolumeMigrationReconciler{
 		Client: mgr.GetClient(),
 		Log:    rt.Log.WithName("controllers").WithName("Migration"),
 	}
@@ -281,9 +346,71 @@ func NewAndInitControllerManager(req NewManagerRequest) manager.Manager {
 		os.Exit(1)
 	}
 
+	quotaReconciler := &reconciler.QuotaReconciler{
+		Client: mgr.GetClient(),
+		Log:    rt.Log.WithName("controllers").WithName("Quota"),
+	}
+	if err = quotaReconciler.SetupWithManager(mgr); err != nil {
+		klog.Error(err, "unable to create Quota controller")
+		os.Exit(1)
+	}
+
+	reservationReconciler := &reconciler.StorageReservationReconciler{
+		Client: mgr.GetClient(),
+		Log:    rt.Log.WithName("controllers").WithName("StorageReservation"),
+		State:  stateObj,
+	}
+	if err = reservationReconciler.SetupWithManager(mgr); err != nil {
+		klog.Error(err, "unable to create StorageReservation controller")
+		os.Exit(1)
+	}
+
+	// setup capacity rebalancer
+	if req.ControllerConfig.Rebalance.Enabled {
+		klog.Infof("setup rebalancer, config %+v", req.ControllerConfig.Rebalance)
+		if err = mgr.Add(rebalance.NewRebalancer(mgr.GetClient(), stateObj, req.ControllerConfig)); err != nil {
+			klog.Error(err, "unable to add rebalancer")
+			os.Exit(1)
+		}
+	}
+
+	// setup consistency checker of State
+	if req.ControllerConfig.ConsistencyCheck.Enabled {
+		klog.Infof("setup consistency checker, config %+v", req.ControllerConfig.ConsistencyCheck)
+		checker := consistency.NewChecker(mgr.GetClient(), stateObj, req.ControllerConfig)
+		if err = mgr.Add(checker); err != nil {
+			klog.Error(err, "unable to add consistency checker")
+			os.Exit(1)
+		}
+		reloadables = append(reloadables, checker)
+	}
+
+	// setup config reloader
+	if req.ConfigPath != "" && req.ConfigReloadInterval > 0 {
+		klog.Infof("setup config reloader of %s, interval %s", req.ConfigPath, req.ConfigReloadInterval)
+		reloader := config.NewReloader(req.ConfigPath, req.ConfigReloadInterval, req.ControllerConfig, sched.ValidateConfig)
+		if ns, name, errSplit := cache.SplitMetaNamespaceKey(req.ConfigMap); errSplit == nil && name != "" {
+			reloader.WithEventRecorder(mgr.GetEventRecorderFor("node-disk-controller"), &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+				Namespace:  ns,
+				Name:       name,
+			})
+		}
+		reloader.Register(reloadables...)
+		if err = mgr.Add(reloader); err != nil {
+			klog.Error(err, "unable to add config reloader")
+			os.Exit(1)
+		}
+	}
+
 	// setup state API service
 	klog.Infof("setup state API service on %s, URI /state/storagepool", req.MetricsAddr)
 	mgr.AddMetricsExtraHandler("/state/storagepool", state.NewStateHandler(stateObj))
+	klog.Infof("setup state API service on %s, URI %s", req.MetricsAddr, state.StateAPIPrefix)
+	mgr.AddMetricsExtraHandler(state.StateAPIPrefix, state.NewStateAPIHandler(stateObj))
+	klog.Infof("setup scheduler explain API on %s, URI %s", req.MetricsAddr, sched.ExplainURI)
+	mgr.AddMetricsExtraHandler(sched.ExplainURI, sched.NewExplainHandler(stateObj, scheduler))
 
 	if req.EnableWebhook {
 		klog.Info("setup webhook service for AntstorVolume")
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/simulate"
+)
+
+type SimulateOption struct {
+	// StateFiles are dumps of StoragePools and AntstorVolumes
+	StateFiles []string
+	// ConfigPath is the path of controller config
+	ConfigPath string
+	// WorkloadFile is the path of workload in YAML or JSON. Flags of request are ignored if it is set.
+	WorkloadFile string
+	Output       string
+	Seed         int64
+	Verbose      bool
+
+	Count       int
+	Size        string
+	Type        string
+	IsThin      bool
+	Position    string
+	Priority    int32
+	Labels      []string
+	Annotations []string
+}
+
+func NewSimulateCommand() *cobra.Command {
+	var option SimulateOption
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Replay synthetic volume requests against recorded cluster state",
+		Long: `Load StoragePools and AntstorVolumes from dump files into memory, schedule synthetic volume requests with the given config,
+and print failure reasons, fragmentation and utilization of each storage pool. Nothing is changed in the cluster.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return option.Run(os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&option.StateFiles, "state", nil, "file path of StoragePools and AntstorVolumes in YAML, or JSON from state API. Can be repeated")
+	cmd.Flags().StringVar(&option.ConfigPath, "config", "", "file path of controller config. Default config is used if it is empty")
+	cmd.Flags().StringVarP(&option.WorkloadFile, "workload", "w", "", "file path of workload in YAML or JSON")
+	cmd.Flags().StringVarP(&option.Output, "output", "o", "table", "output format, table or json")
+	cmd.Flags().Int64Var(&option.Seed, "seed", time.Now().UnixNano(), "seed of picking random host nodes")
+	cmd.Flags().BoolVar(&option.Verbose, "verbose", false, "print logs of scheduler")
+	cmd.Flags().IntVar(&option.Count, "count", 1, "count of volumes")
+	cmd.Flags().StringVar(&option.Size, "size", "", "size of volume, e.g. 10Gi")
+	cmd.Flags().StringVar(&option.Type, "type", "", "type of volume, Flexible, KernelLVol or SpdkLVol")
+	cmd.Flags().BoolVar(&option.IsThin, "thin", false, "volume is thin provisioned")
+	cmd.Flags().StringVar(&option.Position, "position", "", "position advice, NoPreference, MustLocal, MustRemote, PreferLocal or PreferRemote")
+	cmd.Flags().Int32Var(&option.Priority, "priority", 0, "priority of volume")
+	cmd.Flags().StringSliceVar(&option.Labels, "label", nil, "labels of volume, in format of key=value")
+	cmd.Flags().StringSliceVar(&option.Annotations, "anno", nil, "annotations of volume, in format of key=value")
+	return cmd
+}
+
+func (o *SimulateOption) Run(out io.Writer) (err error) {
+	if len(o.StateFiles) == 0 {
+		return fmt.Errorf("--state is required")
+	}
+
+	if !o.Verbose {
+		// scheduler logs every filter failure
+		flag.Set("logtostderr", "false")
+		flag.Set("alsologtostderr", "false")
+		flag.Set("stderrthreshold", "FATAL")
+		klog.SetOutput(io.Discard)
+	}
+
+	var cfg config.Config
+	if o.ConfigPath != "" {
+		if cfg, err = config.Load(o.ConfigPath); err != nil {
+			return
+		}
+	}
+	config.SetDefaults(&cfg)
+
+	var dump simulate.Dump
+	for _, file := range o.StateFiles {
+		if err = loadDumpFile(file, &dump); err != nil {
+			return fmt.Errorf("load state %s failed: %w", file, err)
+		}
+	}
+	memState, err := dump.NewState()
+	if err != nil {
+		return
+	}
+
+	workload, err := o.workload()
+	if err != nil {
+		return
+	}
+
+	report, err := simulate.NewSimulator(cfg, memState, o.Seed).Run(workload)
+	if err != nil {
+		return
+	}
+
+	switch o.Output {
+	case "json":
+		var bs []byte
+		if bs, err = json.MarshalIndent(report, "", "  "); err != nil {
+			return
+		}
+		fmt.Fprintln(out, string(bs))
+	default:
+		printSimulateReport(out, report)
+	}
+
+	return
+}
+
+func (o *SimulateOption) workload() (w simulate.Workload, err error) {
+	if o.WorkloadFile != "" {
+		var f *os.File
+		if f, err = os.Open(o.WorkloadFile); err != nil {
+			return
+		}
+		defer f.Close()
+		return simulate.LoadWorkload(f)
+	}
+
+	req := simulate.Request{
+		Count:          o.Count,
+		Size:           o.Size,
+		Type:           v1.VolumeType(o.Type),
+		IsThin:         o.IsThin,
+		PositionAdvice: v1.VolumePosition(o.Position),
+		Priority:       o.Priority,
+		Labels:         make(map[string]string),
+		Annotations:    make(map[string]string),
+	}
+	if req.Size == "" {
+		err = fmt.Errorf("--size or --workload is required")
+		return
+	}
+	if err = parseKeyValues(o.Labels, req.Labels); err != nil {
+		return
+	}
+	if err = parseKeyValues(o.Annotations, req.Annotations); err != nil {
+		return
+	}
+	w.Requests = append(w.Requests, req)
+	return
+}
+
+func loadDumpFile(file string, dump *simulate.Dump) (err error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	return simulate.LoadDump(f, dump)
+}
+
+func printSimulateReport(out io.Writer, report simulate.Report) {
+	fmt.Fprintf(out, "Requests: %d, Scheduled: %d, Failed: %d\n", report.Requests, report.Scheduled, report.Failed)
+	if len(report.FailureReasons) > 0 {
+		var reasons []string
+		for reason, cnt := range report.FailureReasons {
+			reasons = append(reasons, fmt.Sprintf("%s=%d", reason, cnt))
+		}
+		sort.Strings(reasons)
+		fmt.Fprintf(out, "Failure Reasons: %s\n", strings.Join(reasons, ","))
+	}
+	frag := report.Fragmentation
+	fmt.Fprintf(out, "Fragmentation: %.2f, TotalFree: %s, MaxPoolFree: %s, StrandedFree: %s\n", frag.Ratio,
+		readableBytes(frag.TotalFree), readableBytes(frag.MaxPoolFree), readableBytes(frag.StrandedFree))
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POOL\tCAPACITY\tALLOCATED\tRESERVED\tFREE\tUTIL\tVOLUMES\tREMOTE")
+	for _, item := range report.Pools {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%.1f%%\t%d\t%d\n", item.Name, readableBytes(item.Capacity),
+			readableBytes(item.Allocated), readableBytes(item.Reserved), readableBytes(item.Free),
+			item.Utilization, item.Volumes, item.RemoteVolumes)
+	}
+	w.Flush()
+}
+
+func readableBytes(size int64) string {
+	return resource.NewQuantity(size, resource.BinarySI).String()
+}
@@ -0,0 +1,269 @@
+package rebalance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/scheduler/filter"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+const (
+	// CreatedByRebalancer is the value of label MigrationLabelKeyCreatedBy
+	CreatedByRebalancer = "rebalancer"
+
+	migrationNamePrefix = "rebalance-"
+)
+
+// Proposal is a planned migration of a volume
+type Proposal struct {
+	Volume     *v1.AntstorVolume
+	SourceNode string
+	DestNode   string
+}
+
+// Rebalancer periodically moves remote volumes from pools above high watermark to pools under low watermark,
+// by creating VolumeMigrations.
+type Rebalancer struct {
+	client client.Client
+	state  state.StateIface
+	cfg    config.Config
+}
+
+func NewRebalancer(cli client.Client, state state.StateIface, cfg config.Config) *Rebalancer {
+	return &Rebalancer{
+		client: cli,
+		state:  state,
+		cfg:    cfg,
+	}
+}
+
+// Start implements Runnable
+func (r *Rebalancer) Start(ctx context.Context) (err error) {
+	var tick = time.NewTicker(time.Duration(r.cfg.Rebalance.IntervalSeconds) * time.Second)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+			_, err = r.Rebalance(ctx)
+			if err != nil {
+				klog.Error(err)
+			}
+		case <-ctx.Done():
+			klog.Info("quit rebalance loop")
+			return nil
+		}
+	}
+}
+
+// NeedLeaderElection implements LeaderElectionRunnable. Only the leader creates migrations.
+func (r *Rebalancer) NeedLeaderElection() bool {
+	return true
+}
+
+// Rebalance runs one round of analysis, and creates VolumeMigrations for the proposals unless DryRun is set.
+func (r *Rebalancer) Rebalance(ctx context.Context) (proposals []Proposal, err error) {
+	var migList v1.VolumeMigrationList
+	err = r.client.List(ctx, &migList)
+	if err != nil {
+		err = fmt.Errorf("list VolumeMigrations failed: %w", err)
+		return
+	}
+
+	var (
+		inflight int
+		// namespace/name of volumes in running migrations
+		migrating = make(map[string]bool)
+	)
+	for _, item := range migList.Items {
+		if item.Status.Phase == v1.MigrationPhaseFinished || item.Status.Status == v1.MigrationStatusError {
+			continue
+		}
+		inflight++
+		migrating[item.Spec.SourceVolume.Namespace+"/"+item.Spec.SourceVolume.Name] = true
+		if item.Spec.DestVolume.Name != "" {
+			migrating[item.Spec.DestVolume.Namespace+"/"+item.Spec.DestVolume.Name] = true
+		}
+	}
+
+	budget := r.cfg.Rebalance.MaxConcurrentMigrations - inflight
+	if budget <= 0 {
+		klog.Infof("rebalance: %d migrations are running, reach max concurrency %d", inflight, r.cfg.Rebalance.MaxConcurrentMigrations)
+		return
+	}
+
+	proposals = r.Propose(budget, migrating)
+	for _, item := range proposals {
+		klog.Infof("rebalance: propose to migrate volume %s from %s to %s, dryRun=%t",
+			item.Volume.Name, item.SourceNode, item.DestNode, r.cfg.Rebalance.DryRun)
+		if r.cfg.Rebalance.DryRun {
+			continue
+		}
+		err = r.client.Create(ctx, newMigration(item))
+		if err != nil {
+			err = fmt.Errorf("create VolumeMigration for volume %s failed: %w", item.Volume.Name, err)
+			return
+		}
+	}
+
+	return
+}
+
+// Propose returns at most budget proposals. Volumes in migrating, which is keyed by namespace/name, are skipped.
+func (r *Rebalancer) Propose(budget int, migrating map[string]bool) (proposals []Proposal) {
+	var (
+		rcfg     = r.cfg.Rebalance
+		allNodes = r.state.GetAllNodes()
+		// planned usage of each node in bytes
+		used  = make(map[string]int64, len(allNodes))
+		total = make(map[string]int64, len(allNodes))
+		hot   []*state.Node
+	)
+
+	for _, node := range allNodes {
+		total[node.Info.ID] = node.Pool.GetAvailableBytes()
+		for _, vol := range node.View().Volumes {
+			used[node.Info.ID] += int64(vol.GetTotalSize())
+		}
+	}
+	usagePct := func(nodeID string) int64 {
+		if total[nodeID] <= 0 {
+			return 100
+		}
+		return used[nodeID] * 100 / total[nodeID]
+	}
+
+	for _, node := range allNodes {
+		if node.Pool.Status.Status != v1.PoolStatusReady {
+			continue
+		}
+		if usagePct(node.Info.ID) >= int64(rcfg.HighWatermarkPct) {
+			hot = append(hot, node)
+		}
+	}
+	sort.Slice(hot, func(i, j int) bool {
+		return usagePct(hot[i].Info.ID) > usagePct(hot[j].Info.ID)
+	})
+
+	for _, src := range hot {
+		for _, vol := range candidateVolumes(src, migrating) {
+			if len(proposals) >= budget || usagePct(src.Info.ID) < int64(rcfg.HighWatermarkPct) {
+				break
+			}
+
+			var (
+				size     = int64(vol.GetTotalSize())
+				destNode string
+				destPct  int64
+			)
+			for _, dest := range r.filterNodes(allNodes, src, vol) {
+				id := dest.Info.ID
+				if total[id] <= 0 {
+					continue
+				}
+				pct := (used[id] + size) * 100 / total[id]
+				if pct >= int64(rcfg.LowWatermarkPct) {
+					continue
+				}
+				if destNode == "" || pct < destPct {
+					destNode, destPct = id, pct
+				}
+			}
+			if destNode == "" {
+				klog.Infof("rebalance: no pool under low watermark for volume %s", vol.Name)
+				continue
+			}
+
+			used[src.Info.ID] -= size
+			used[destNode] += size
+			migrating[vol.Namespace+"/"+vol.Name] = true
+			proposals = append(proposals, Proposal{
+				Volume:     vol,
+				SourceNode: src.Info.ID,
+				DestNode:   destNode,
+			})
+		}
+	}
+
+	return
+}
+
+// filterNodes returns the nodes except src, which pass the filters of the volume's scheduler profile
+func (r *Rebalancer) filterNodes(allNodes []*state.Node, src *state.Node, vol *v1.AntstorVolume) (nodes []*state.Node) {
+	var cfg = r.cfg.Scheduler
+	profile, err := cfg.GetProfile(vol.Annotations[v1.SchedulerProfileAnnoKey])
+	if err != nil {
+		klog.Errorf("%s: %+v, use default profile", vol.Name, err)
+		profile, _ = cfg.GetProfile("")
+	}
+	cfg.Filters = profile.Filters
+
+	var others = make([]*state.Node, 0, len(allNodes))
+	for _, item := range allNodes {
+		if item.Info.ID != src.Info.ID {
+			others = append(others, item)
+		}
+	}
+
+	// filters may modify the volume, e.g. setting annotations
+	nodes, err = filter.NewFilterChain(cfg).
+		Input(others, vol.DeepCopy()).
+		LoadFilterFromConfig().
+		MatchAll()
+	if err != nil {
+		klog.Infof("rebalance: volume %s, %s", vol.Name, err)
+	}
+	return
+}
+
+// candidateVolumes returns volumes which can be migrated, sorted by size in descending order
+func candidateVolumes(n *state.Node, migrating map[string]bool) (vols []*v1.AntstorVolume) {
+	for _, vol := range n.View().Volumes {
+		if vol.Spec.Type != v1.VolumeTypeSpdkLVol ||
+			vol.Spec.PositionAdvice == v1.MustLocal ||
+			vol.Spec.HostNode == nil || vol.IsLocal() ||
+			vol.Status.Status != v1.VolumeStatusReady ||
+			vol.DeletionTimestamp != nil ||
+			vol.Annotations[v1.RebalanceDisabledAnnoKey] == "true" ||
+			migrating[vol.Namespace+"/"+vol.Name] {
+			continue
+		}
+		vols = append(vols, vol)
+	}
+
+	sort.Slice(vols, func(i, j int) bool {
+		return vols[i].GetTotalSize() > vols[j].GetTotalSize()
+	})
+	return
+}
+
+func newMigration(p Proposal) *v1.VolumeMigration {
+	return &v1.VolumeMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: p.Volume.Namespace,
+			Name:      migrationNamePrefix + uuid.NewV4().String(),
+			Labels: map[string]string{
+				v1.MigrationLabelKeySourceVolumeName: p.Volume.Name,
+				v1.MigrationLabelKeyCreatedBy:        CreatedByRebalancer,
+			},
+		},
+		Spec: v1.VolumeMigrationSpec{
+			SourceVolume: v1.VolumeInfo{
+				Namespace: p.Volume.Namespace,
+				Name:      p.Volume.Name,
+			},
+			DestVolume: v1.VolumeInfo{
+				TargetNodeId: p.DestNode,
+			},
+		},
+	}
+}
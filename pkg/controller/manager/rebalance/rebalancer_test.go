@@ -0,0 +1,181 @@
+package rebalance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+const gib = 1 << 30
+
+func newPool(nodeID string, usedGi int64) *v1.StoragePool {
+	return &v1.StoragePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodeID,
+			Namespace: v1.DefaultNamespace,
+		},
+		Spec: v1.StoragePoolSpec{
+			SpdkLVStore: v1.SpdkLVStore{
+				Name:  "lvs-" + nodeID,
+				UUID:  "lvs-uuid-" + nodeID,
+				Bytes: 100 * gib,
+			},
+			NodeInfo: v1.NodeInfo{
+				ID: nodeID,
+			},
+		},
+		Status: v1.StoragePoolStatus{
+			Status:     v1.PoolStatusReady,
+			VGFreeSize: *resource.NewQuantity((100-usedGi)*gib, resource.BinarySI),
+			Conditions: []v1.PoolCondition{
+				{
+					Type:   v1.PoolConditionSpkdHealth,
+					Status: v1.StatusOK,
+				},
+			},
+		},
+	}
+}
+
+func newVolume(name, nodeID string, sizeGi uint64) *v1.AntstorVolume {
+	return &v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   v1.DefaultNamespace,
+			Name:        name,
+			Annotations: map[string]string{},
+		},
+		Spec: v1.AntstorVolumeSpec{
+			Uuid:         name + "-uuid",
+			Type:         v1.VolumeTypeSpdkLVol,
+			SizeByte:     sizeGi * gib,
+			TargetNodeId: nodeID,
+			HostNode: &v1.NodeInfo{
+				ID: "host-node",
+			},
+		},
+		Status: v1.AntstorVolumeStatus{
+			Status: v1.VolumeStatusReady,
+		},
+	}
+}
+
+func newTestState(t *testing.T) state.StateIface {
+	s := state.NewState()
+	s.SetStoragePool(newPool("node-1", 90))
+	s.SetStoragePool(newPool("node-2", 0))
+	s.SetStoragePool(newPool("node-3", 50))
+
+	vols := map[string]*v1.AntstorVolume{
+		"vol-1": newVolume("vol-1", "node-1", 40),
+		"vol-2": newVolume("vol-2", "node-1", 30),
+		"vol-3": newVolume("vol-3", "node-1", 20),
+		"vol-4": newVolume("vol-4", "node-3", 50),
+	}
+	// vol-1 opts out of rebalancing
+	vols["vol-1"].Annotations[v1.RebalanceDisabledAnnoKey] = "true"
+	for _, vol := range vols {
+		assert.NoError(t, s.BindAntstorVolume(vol.Spec.TargetNodeId, vol))
+	}
+
+	return s
+}
+
+func newTestConfig(dryRun bool, maxMigrations int) config.Config {
+	return config.Config{
+		Scheduler: config.SchedulerConfig{
+			Filters:              []string{"Basic"},
+			MaxRemoteVolumeCount: 3,
+		},
+		Rebalance: config.RebalanceConfig{
+			Enabled:                 true,
+			DryRun:                  dryRun,
+			HighWatermarkPct:        85,
+			LowWatermarkPct:         60,
+			MaxConcurrentMigrations: maxMigrations,
+		},
+	}
+}
+
+func TestRebalance(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	r := NewRebalancer(cli, newTestState(t), newTestConfig(false, 2))
+	proposals, err := r.Rebalance(context.Background())
+	assert.NoError(t, err)
+	// moving vol-2 makes node-1 60% used, which is under high watermark.
+	// node-3 will be 80% used, so node-2 is the only destination.
+	if assert.Len(t, proposals, 1) {
+		assert.Equal(t, "vol-2", proposals[0].Volume.Name)
+		assert.Equal(t, "node-1", proposals[0].SourceNode)
+		assert.Equal(t, "node-2", proposals[0].DestNode)
+	}
+
+	var migList v1.VolumeMigrationList
+	assert.NoError(t, cli.List(context.Background(), &migList, client.MatchingLabels{
+		v1.MigrationLabelKeyCreatedBy: CreatedByRebalancer,
+	}))
+	if assert.Len(t, migList.Items, 1) {
+		mig := migList.Items[0]
+		assert.Equal(t, "vol-2", mig.Spec.SourceVolume.Name)
+		assert.Equal(t, "node-2", mig.Spec.DestVolume.TargetNodeId)
+	}
+
+	// node-1 is still above high watermark before vol-2 is moved. vol-2 is in migration, so vol-3 is proposed.
+	proposals, err = r.Rebalance(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, proposals, 1) {
+		assert.Equal(t, "vol-3", proposals[0].Volume.Name)
+	}
+
+	// reach max concurrency
+	proposals, err = r.Rebalance(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, proposals, 0)
+}
+
+func TestRebalanceDryRun(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	r := NewRebalancer(cli, newTestState(t), newTestConfig(true, 2))
+	proposals, err := r.Rebalance(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, proposals, 1)
+
+	var migList v1.VolumeMigrationList
+	assert.NoError(t, cli.List(context.Background(), &migList))
+	assert.Len(t, migList.Items, 0)
+}
+
+func TestRebalanceMigratingInOtherNamespace(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+	// a running migration of a volume with the same name in another namespace
+	mig := &v1.VolumeMigration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "mig-other"},
+		Spec: v1.VolumeMigrationSpec{
+			SourceVolume: v1.VolumeInfo{Namespace: "other", Name: "vol-2"},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mig).Build()
+
+	r := NewRebalancer(cli, newTestState(t), newTestConfig(true, 2))
+	proposals, err := r.Rebalance(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, proposals, 1) {
+		assert.Equal(t, "vol-2", proposals[0].Volume.Name)
+	}
+}
@@ -189,6 +189,10 @@ func (r *VolumeMigrationReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		destVolume.Labels[v1.MigrationLabelKeyMigrationName] = migration.Name
 		// dest volume cannot reside on the same node of src volume, b/c dest subsystem should have same NQN, NSUUID of src subsystem.
 		destVolume.Annotations[v1.PoolLabelSelectorKey] = fmt.Sprintf("%s!=%s", v1.PoolLabelsNodeSnKey, srcVol.Spec.TargetNodeId)
+		// dest node is specified, e.g. by the rebalancer
+		if destNode := migration.Spec.DestVolume.TargetNodeId; destNode != "" && destNode != srcVol.Spec.TargetNodeId {
+			destVolume.Annotations[v1.PoolLabelSelectorKey] = fmt.Sprintf("%s=%s", v1.PoolLabelsNodeSnKey, destNode)
+		}
 
 		log.Info("creating dest volume", "name", destVolume.Name)
 		err = r.Create(ctx, &destVolume)
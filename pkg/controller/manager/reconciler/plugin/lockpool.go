@@ -3,6 +3,7 @@ package plugin
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
 	"lite.io/liteio/pkg/controller/manager/config"
@@ -18,18 +19,34 @@ type LockPoolPlugin struct {
 	State  state.StateIface
 	Client client.Client
 	Cfg    config.Config
+	// cfgLock protects Cfg, which is replaced by UpdateConfig
+	cfgLock sync.RWMutex
 }
 
 func (p *LockPoolPlugin) Name() string {
 	return "LockPool"
 }
 
+// UpdateConfig implements config.Reloadable
+func (p *LockPoolPlugin) UpdateConfig(cfg config.Config) {
+	p.cfgLock.Lock()
+	defer p.cfgLock.Unlock()
+	p.Cfg = cfg
+}
+
+func (p *LockPoolPlugin) lockSchedConfig() config.NoScheduleConfig {
+	p.cfgLock.RLock()
+	defer p.cfgLock.RUnlock()
+	return p.Cfg.Scheduler.LockSchedCfg
+}
+
 func (p *LockPoolPlugin) Reconcile(ctx *Context) (result Result) {
 	var (
-		log = ctx.Log
-		obj = ctx.ReqCtx.Object
-		ok  bool
-		err error
+		log     = ctx.Log
+		lockCfg = p.lockSchedConfig()
+		obj     = ctx.ReqCtx.Object
+		ok      bool
+		err     error
 
 		sp   *v1.StoragePool
 		node corev1.Node
@@ -50,7 +67,7 @@ func (p *LockPoolPlugin) Reconcile(ctx *Context) (result Result) {
 
 	var matched bool
 	// check selector
-	for _, item := range p.Cfg.Scheduler.LockSchedCfg.NodeSelector {
+	for _, item := range lockCfg.NodeSelector {
 		s := labels.NewSelector()
 		op, err := convertSelectionOp(item.Operator)
 		if err != nil {
@@ -72,7 +89,7 @@ func (p *LockPoolPlugin) Reconcile(ctx *Context) (result Result) {
 
 	// check taints
 	if !matched {
-		for _, toler := range p.Cfg.Scheduler.LockSchedCfg.NodeTaints {
+		for _, toler := range lockCfg.NodeTaints {
 			matched = matchAnyTaint(toler, node.Spec.Taints)
 			if matched {
 				log.Info("matched nodeSelector", "toleration", toler, "name", sp.Name)
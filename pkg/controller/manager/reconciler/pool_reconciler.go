@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -44,6 +45,45 @@ type StoragePoolReconcileHandler struct {
 	State    state.StateIface
 	PoolUtil kubeutil.StoragePoolUpdater
 	KubeCli  kubernetes.Interface
+
+	cfgLock sync.RWMutex
+}
+
+// UpdateConfig implements config.Reloadable. Reservations removed or resized in config are unreserved from all nodes,
+// and the new ones are reserved.
+func (r *StoragePoolReconcileHandler) UpdateConfig(cfg config.Config) {
+	r.cfgLock.Lock()
+	prev := r.Cfg.Scheduler.NodeReservations
+	r.Cfg.Scheduler = cfg.Scheduler
+	r.cfgLock.Unlock()
+
+	var sizes = make(map[string]int64, len(cfg.Scheduler.NodeReservations))
+	for _, item := range cfg.Scheduler.NodeReservations {
+		sizes[item.ID] = item.Size
+	}
+	for _, node := range r.State.GetAllNodes() {
+		for _, item := range prev {
+			if size, has := sizes[item.ID]; !has || size != item.Size {
+				node.Unreserve(item.ID)
+			}
+		}
+		reserveByConfig(node, cfg.Scheduler.NodeReservations)
+	}
+}
+
+func (r *StoragePoolReconcileHandler) nodeReservations() []config.NodeReservation {
+	r.cfgLock.RLock()
+	defer r.cfgLock.RUnlock()
+	return r.Cfg.Scheduler.NodeReservations
+}
+
+// reserveByConfig adds the reservations in config to node, if they are not reserved
+func reserveByConfig(node *state.Node, resvs []config.NodeReservation) {
+	for _, item := range resvs {
+		if _, has := node.GetReservation(item.ID); !has {
+			node.Reserve(state.NewReservation(item.ID, item.Size))
+		}
+	}
 }
 
 func (r *StoragePoolReconcileHandler) ResourceName() string {
@@ -252,12 +292,8 @@ func (r *StoragePoolReconcileHandler) saveToState(sp *v1.StoragePool, log logr.L
 	if err != nil {
 		log.Error(err, "GetNodeByNodeID error")
 	}
-	for _, item := range r.Cfg.Scheduler.NodeReservations {
-		if node != nil {
-			if _, has := node.GetReservation(item.ID); !has {
-				node.Reserve(state.NewReservation(item.ID, item.Size))
-			}
-		}
+	if node != nil {
+		reserveByConfig(node, r.nodeReservations())
 	}
 
 	return plugin.Result{}
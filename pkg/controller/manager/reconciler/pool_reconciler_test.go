@@ -0,0 +1,55 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+func TestStoragePoolHandlerUpdateConfig(t *testing.T) {
+	s := state.NewState()
+	s.SetStoragePool(&v1.StoragePool{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "node-1"},
+		Spec: v1.StoragePoolSpec{
+			NodeInfo:    v1.NodeInfo{ID: "node-1"},
+			SpdkLVStore: v1.SpdkLVStore{Name: "lvs", Bytes: 100 << 30},
+		},
+		Status: v1.StoragePoolStatus{
+			Status:     v1.PoolStatusReady,
+			VGFreeSize: *resource.NewQuantity(100<<30, resource.BinarySI),
+		},
+	})
+	node, err := s.GetNodeByNodeID("node-1")
+	assert.NoError(t, err)
+
+	var cfg config.Config
+	cfg.Scheduler.NodeReservations = []config.NodeReservation{
+		{ID: "resv-a", Size: 1 << 30},
+		{ID: "resv-b", Size: 1 << 30},
+	}
+	r := &StoragePoolReconcileHandler{Cfg: cfg, State: s}
+	reserveByConfig(node, r.nodeReservations())
+
+	// resv-a is resized, resv-b is removed and resv-c is added
+	cfg.Scheduler.NodeReservations = []config.NodeReservation{
+		{ID: "resv-a", Size: 2 << 30},
+		{ID: "resv-c", Size: 1 << 30},
+	}
+	r.UpdateConfig(cfg)
+
+	resv, has := node.GetReservation("resv-a")
+	if assert.True(t, has) {
+		assert.Equal(t, int64(2<<30), resv.Size())
+	}
+	_, has = node.GetReservation("resv-b")
+	assert.False(t, has)
+	_, has = node.GetReservation("resv-c")
+	assert.True(t, has)
+	assert.Equal(t, cfg.Scheduler.NodeReservations, r.nodeReservations())
+}
@@ -0,0 +1,119 @@
+package reconciler
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	crhandler "sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+)
+
+const (
+	// quotaResyncInterval is the interval of recomputing usage, in case of missing events
+	quotaResyncInterval = 5 * time.Minute
+)
+
+// QuotaReconciler computes the usage of AntstorQuota from AntstorVolumes and AntstorSnapshots
+type QuotaReconciler struct {
+	client.Client
+	Log logr.Logger
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *QuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: 1,
+		}).
+		For(&v1.AntstorQuota{}).
+		Watches(&source.Kind{Type: &v1.AntstorVolume{}}, crhandler.EnqueueRequestsFromMapFunc(r.quotasOfVolume)).
+		Watches(&source.Kind{Type: &v1.AntstorSnapshot{}}, crhandler.EnqueueRequestsFromMapFunc(r.quotasOfSnapshot)).
+		Complete(r)
+}
+
+func (r *QuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var (
+		log   = r.Log.WithValues("Quota", req.NamespacedName)
+		quota v1.AntstorQuota
+		vols  v1.AntstorVolumeList
+		snaps v1.AntstorSnapshotList
+	)
+
+	if err := r.Get(ctx, req.NamespacedName, &quota); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if err := r.List(ctx, &vols, client.MatchingLabels{v1.VolumeContextKeyPvcNS: quota.Namespace}); err != nil {
+		log.Error(err, "list AntstorVolumes failed")
+		return ctrl.Result{}, err
+	}
+	// snapshots without namespace label are matched by origin volume
+	if err := r.List(ctx, &snaps); err != nil {
+		log.Error(err, "list AntstorSnapshots failed")
+		return ctrl.Result{}, err
+	}
+
+	used := v1.QuotaUsageOfNamespace(quota.Namespace, vols.Items, snaps.Items)
+	if equality.Semantic.DeepEqual(quota.Status.Used, used) && equality.Semantic.DeepEqual(quota.Status.Hard, quota.Spec.Hard) {
+		return ctrl.Result{RequeueAfter: quotaResyncInterval}, nil
+	}
+
+	quota.Status.Hard = quota.Spec.Hard
+	quota.Status.Used = used
+	log.Info("update quota status", "used", used)
+	if err := r.Status().Update(ctx, &quota); err != nil {
+		log.Error(err, "update quota status failed")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: quotaResyncInterval}, nil
+}
+
+func (r *QuotaReconciler) quotasOfVolume(obj client.Object) []reconcile.Request {
+	return r.quotasInNamespace(obj.GetLabels()[v1.VolumeContextKeyPvcNS])
+}
+
+func (r *QuotaReconciler) quotasOfSnapshot(obj client.Object) []reconcile.Request {
+	ns, has := obj.GetLabels()[v1.VolumeContextKeyPvcNS]
+	if !has {
+		if snap, ok := obj.(*v1.AntstorSnapshot); ok {
+			var vol v1.AntstorVolume
+			err := r.Get(context.Background(), types.NamespacedName{
+				Namespace: snap.Spec.OriginVolNamespace,
+				Name:      snap.Spec.OriginVolName,
+			}, &vol)
+			if err == nil {
+				ns = vol.QuotaNamespace()
+			}
+		}
+	}
+	return r.quotasInNamespace(ns)
+}
+
+func (r *QuotaReconciler) quotasInNamespace(ns string) (reqs []reconcile.Request) {
+	if ns == "" {
+		return
+	}
+
+	var list v1.AntstorQuotaList
+	if err := r.List(context.Background(), &list, client.InNamespace(ns)); err != nil {
+		r.Log.Error(err, "list AntstorQuotas failed", "namespace", ns)
+		return
+	}
+	for _, item := range list.Items {
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: item.Namespace,
+			Name:      item.Name,
+		}})
+	}
+	return
+}
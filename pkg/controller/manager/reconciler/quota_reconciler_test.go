@@ -0,0 +1,117 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+)
+
+func TestQuotaReconcile(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+
+	quota := &v1.AntstorQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "app",
+			Name:      "quota",
+		},
+		Spec: v1.AntstorQuotaSpec{
+			Hard: corev1.ResourceList{
+				v1.QuotaResourceStorage:       resource.MustParse("100Gi"),
+				v1.QuotaResourceRemoteStorage: resource.MustParse("30Gi"),
+			},
+		},
+	}
+	newVol := func(name, ns, target string, typ v1.VolumeType) *v1.AntstorVolume {
+		return &v1.AntstorVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: v1.DefaultNamespace,
+				Name:      name,
+				Labels: map[string]string{
+					v1.VolumeContextKeyPvcNS: ns,
+				},
+			},
+			Spec: v1.AntstorVolumeSpec{
+				Type:         typ,
+				SizeByte:     10 << 30,
+				TargetNodeId: target,
+				HostNode:     &v1.NodeInfo{ID: "node-1"},
+			},
+		}
+	}
+	vol1 := newVol("vol-1", "app", "node-1", v1.VolumeTypeKernelLVol)
+	vol2 := newVol("vol-2", "app", "node-2", v1.VolumeTypeSpdkLVol)
+	vol3 := newVol("vol-3", "other", "node-2", v1.VolumeTypeSpdkLVol)
+	// snapshot without namespace label belongs to namespace of origin volume
+	snap := &v1.AntstorSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: v1.DefaultNamespace,
+			Name:      "snap-1",
+		},
+		Spec: v1.AntstorSnapshotSpec{
+			Size:               1 << 30,
+			OriginVolName:      vol2.Name,
+			OriginVolNamespace: vol2.Namespace,
+		},
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(quota, vol1, vol2, vol3, snap).Build()
+	r := &QuotaReconciler{
+		Client: cli,
+		Log:    zap.New(),
+	}
+	assert.Len(t, r.quotasOfSnapshot(snap), 1)
+	assert.Len(t, r.quotasOfVolume(vol3), 0)
+
+	key := types.NamespacedName{Namespace: "app", Name: "quota"}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+
+	var got v1.AntstorQuota
+	assert.NoError(t, cli.Get(context.Background(), key, &got))
+	used := got.Status.Used
+	for name, expect := range map[corev1.ResourceName]string{
+		v1.QuotaResourceVolumes:         "2",
+		v1.QuotaResourceStorage:         "20Gi",
+		v1.QuotaResourceLocalStorage:    "10Gi",
+		v1.QuotaResourceRemoteStorage:   "10Gi",
+		v1.QuotaResourceLvmStorage:      "10Gi",
+		v1.QuotaResourceSpdkStorage:     "10Gi",
+		v1.QuotaResourceThickStorage:    "20Gi",
+		v1.QuotaResourceSnapshots:       "1",
+		v1.QuotaResourceSnapshotStorage: "1Gi",
+	} {
+		quan := used[name]
+		assert.Zero(t, quan.Cmp(resource.MustParse(expect)), "%s: %s != %s", name, quan.String(), expect)
+	}
+
+	// a Flexible volume without placement may be remote, so 10Gi + 25Gi remote storage exceeds the quota
+	req := (&v1.AntstorVolume{
+		Spec: v1.AntstorVolumeSpec{
+			Type:     v1.VolumeTypeFlexible,
+			SizeByte: 25 << 30,
+		},
+	}).QuotaUsage(true)
+	assert.Equal(t, []corev1.ResourceName{v1.QuotaResourceRemoteStorage}, got.ExceededResources(used, req))
+
+	// MustLocal volume is not charged to remote storage
+	req = (&v1.AntstorVolume{
+		Spec: v1.AntstorVolumeSpec{
+			Type:           v1.VolumeTypeFlexible,
+			SizeByte:       25 << 30,
+			PositionAdvice: v1.MustLocal,
+		},
+	}).QuotaUsage(true)
+	assert.Empty(t, got.ExceededResources(used, req))
+}
@@ -0,0 +1,191 @@
+package reconciler
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	crhandler "sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/state"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+const (
+	// reservationResyncInterval is the interval of reserving again, in case of new pools or released volumes
+	reservationResyncInterval = 5 * time.Minute
+)
+
+// StorageReservationReconciler loads StorageReservations into the reservations of Nodes in State.
+// Expired StorageReservations are deleted. Deleted StorageReservations are removed from State.
+type StorageReservationReconciler struct {
+	client.Client
+	Log   logr.Logger
+	State state.StateIface
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StorageReservationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: 1,
+		}).
+		For(&v1.StorageReservation{}).
+		Watches(&source.Kind{Type: &v1.StoragePool{}}, crhandler.EnqueueRequestsFromMapFunc(r.allReservations)).
+		Complete(r)
+}
+
+func (r *StorageReservationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var (
+		log  = r.Log.WithValues("Reservation", req.NamespacedName)
+		resv v1.StorageReservation
+		id   = v1.StorageReservationID(req.Namespace, req.Name)
+	)
+
+	if err := r.Get(ctx, req.NamespacedName, &resv); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("StorageReservation is deleted, unreserve it")
+			r.unreserveAll(id)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if resv.DeletionTimestamp != nil {
+		log.Info("StorageReservation is being deleted, unreserve it")
+		r.unreserveAll(id)
+		return ctrl.Result{}, nil
+	}
+
+	expire := resv.ExpireTime()
+	if expire != nil && !time.Now().Before(expire.Time) {
+		log.Info("StorageReservation is expired, delete it", "expireTime", expire)
+		r.unreserveAll(id)
+		if err := r.Delete(ctx, &resv); err != nil {
+			log.Error(err, "delete StorageReservation failed")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	nodeSel, err := selectorOf(resv.Spec.NodeSelector)
+	if err != nil {
+		log.Error(err, "invalid nodeSelector")
+		return ctrl.Result{}, nil
+	}
+	poolSel, err := selectorOf(resv.Spec.PoolSelector)
+	if err != nil {
+		log.Error(err, "invalid poolSelector")
+		return ctrl.Result{}, nil
+	}
+
+	var (
+		size      = resv.Spec.Size.Value()
+		status    = v1.StorageReservationStatus{ExpireTime: expire}
+		preempted = misc.FromSlice(resv.Status.PreemptedNodes)
+	)
+	for _, node := range r.State.GetAllNodes() {
+		nodeID := node.Info.ID
+		_, reserved := node.GetReservation(id)
+		if !nodeSel.Matches(labels.Set(node.Info.Labels)) || !poolSel.Matches(labels.Set(node.Pool.Labels)) {
+			// selector may be changed
+			if reserved {
+				log.Info("node is not selected, unreserve it", "node", nodeID)
+				node.Unreserve(id)
+			}
+			continue
+		}
+
+		// preempted reservation is not reserved again
+		if preempted.Contains(nodeID) {
+			if reserved {
+				log.Info("reservation is preempted on node, unreserve it", "node", nodeID)
+				node.Unreserve(id)
+			}
+			status.PreemptedNodes = append(status.PreemptedNodes, nodeID)
+			continue
+		}
+
+		if node.IsReservationConsumed(id) {
+			status.ConsumedNodes = append(status.ConsumedNodes, nodeID)
+			continue
+		}
+
+		if cur, has := node.GetReservation(id); !has || cur.Size() != size || cur.Priority() != resv.Spec.Priority {
+			if has {
+				node.Unreserve(id)
+			}
+			log.Info("reserve storage on node", "node", nodeID, "size", size, "priority", resv.Spec.Priority)
+			node.Reserve(state.NewReservationWithPriority(id, size, resv.Spec.Priority))
+		}
+		if _, has := node.GetReservation(id); has {
+			status.ReservedNodes = append(status.ReservedNodes, nodeID)
+		} else {
+			status.FailedNodes = append(status.FailedNodes, nodeID)
+		}
+	}
+	sort.Strings(status.ReservedNodes)
+	sort.Strings(status.ConsumedNodes)
+	sort.Strings(status.FailedNodes)
+	sort.Strings(status.PreemptedNodes)
+
+	var requeue = reservationResyncInterval
+	if expire != nil {
+		if untilExpire := time.Until(expire.Time); untilExpire < requeue {
+			requeue = untilExpire
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(resv.Status, status) {
+		resv.Status = status
+		log.Info("update reservation status", "status", status)
+		if err = r.Status().Update(ctx, &resv); err != nil {
+			log.Error(err, "update reservation status failed")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+func (r *StorageReservationReconciler) unreserveAll(id string) {
+	for _, node := range r.State.GetAllNodes() {
+		if _, has := node.GetReservation(id); has {
+			node.Unreserve(id)
+		}
+	}
+}
+
+func (r *StorageReservationReconciler) allReservations(obj client.Object) (reqs []reconcile.Request) {
+	var list v1.StorageReservationList
+	if err := r.List(context.Background(), &list); err != nil {
+		r.Log.Error(err, "list StorageReservations failed")
+		return
+	}
+	for _, item := range list.Items {
+		reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: item.Namespace,
+			Name:      item.Name,
+		}})
+	}
+	return
+}
+
+// selectorOf converts LabelSelector to Selector. Empty selector matches everything.
+func selectorOf(sel *metav1.LabelSelector) (labels.Selector, error) {
+	if sel == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(sel)
+}
@@ -0,0 +1,154 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+func TestStorageReservationReconcile(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+
+	newPool := func(nodeID, rack string) *v1.StoragePool {
+		return &v1.StoragePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: v1.DefaultNamespace,
+				Name:      nodeID,
+				Labels:    map[string]string{"rack": rack},
+			},
+			Spec: v1.StoragePoolSpec{
+				SpdkLVStore: v1.SpdkLVStore{
+					Name:  "lvs-" + nodeID,
+					UUID:  "lvs-uuid-" + nodeID,
+					Bytes: 100 << 30,
+				},
+				NodeInfo: v1.NodeInfo{ID: nodeID},
+			},
+			Status: v1.StoragePoolStatus{
+				VGFreeSize: *resource.NewQuantity(100<<30, resource.BinarySI),
+			},
+		}
+	}
+	stateObj := state.NewState()
+	stateObj.SetStoragePool(newPool("node-1", "rack-1"))
+	stateObj.SetStoragePool(newPool("node-2", "rack-1"))
+	stateObj.SetStoragePool(newPool("node-3", "rack-2"))
+
+	resv := &v1.StorageReservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "app",
+			Name:              "upcoming",
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: v1.StorageReservationSpec{
+			Size: resource.MustParse("10Gi"),
+			PoolSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"rack": "rack-1"},
+			},
+			TTL: &metav1.Duration{Duration: time.Hour},
+		},
+	}
+	expired := &v1.StorageReservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "app",
+			Name:              "expired",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+		},
+		Spec: v1.StorageReservationSpec{
+			Size: resource.MustParse("10Gi"),
+			TTL:  &metav1.Duration{Duration: time.Hour},
+		},
+	}
+	// a volume on node-2 has consumed the reservation
+	vol := &v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   v1.DefaultNamespace,
+			Name:        "vol-1",
+			Annotations: map[string]string{v1.ReservationIDKey: resv.ReservationID()},
+		},
+		Spec: v1.AntstorVolumeSpec{
+			Uuid:     "vol-1-uuid",
+			Type:     v1.VolumeTypeSpdkLVol,
+			SizeByte: 10 << 30,
+		},
+	}
+	assert.NoError(t, stateObj.BindAntstorVolume("node-2", vol))
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(resv, expired).Build()
+	r := &StorageReservationReconciler{
+		Client: cli,
+		Log:    zap.New(),
+		State:  stateObj,
+	}
+	assert.Len(t, r.allReservations(newPool("node-4", "rack-1")), 2)
+
+	key := types.NamespacedName{Namespace: "app", Name: "upcoming"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= time.Hour)
+
+	var got v1.StorageReservation
+	assert.NoError(t, cli.Get(context.Background(), key, &got))
+	assert.Equal(t, []string{"node-1"}, got.Status.ReservedNodes)
+	assert.Equal(t, []string{"node-2"}, got.Status.ConsumedNodes)
+	assert.Empty(t, got.Status.FailedNodes)
+	assert.NotNil(t, got.Status.ExpireTime)
+
+	node1, _ := stateObj.GetNodeByNodeID("node-1")
+	r1, has := node1.GetReservation(resv.ReservationID())
+	assert.True(t, has)
+	assert.Equal(t, int64(10<<30), r1.Size())
+	node3, _ := stateObj.GetNodeByNodeID("node-3")
+	_, has = node3.GetReservation(resv.ReservationID())
+	assert.False(t, has)
+
+	// larger than free space
+	got.Spec.Size = resource.MustParse("200Gi")
+	assert.NoError(t, cli.Update(context.Background(), &got))
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.NoError(t, cli.Get(context.Background(), key, &got))
+	assert.Equal(t, []string{"node-1"}, got.Status.FailedNodes)
+	assert.Empty(t, got.Status.ReservedNodes)
+
+	// deleted reservation is removed from state, but the reservation of PVC with the same namespace/name is kept
+	got.Spec.Size = resource.MustParse("10Gi")
+	assert.NoError(t, cli.Update(context.Background(), &got))
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	_, has = node1.GetReservation(resv.ReservationID())
+	assert.True(t, has)
+	node1.Reserve(state.NewReservation("app/upcoming", 1<<30))
+	assert.NoError(t, cli.Delete(context.Background(), &got))
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	_, has = node1.GetReservation(resv.ReservationID())
+	assert.False(t, has)
+	_, has = node1.GetReservation("app/upcoming")
+	assert.True(t, has)
+
+	// expired reservation is deleted
+	key = types.NamespacedName{Namespace: "app", Name: "expired"}
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	err = cli.Get(context.Background(), key, &got)
+	assert.True(t, errors.IsNotFound(err))
+	for _, node := range stateObj.GetAllNodes() {
+		_, has = node.GetReservation(expired.ReservationID())
+		assert.False(t, has)
+	}
+}
@@ -0,0 +1,321 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/util/s3"
+)
+
+// backupCheckInterval is the interval of checking the snapshot of backup to be ready
+const backupCheckInterval = 5 * time.Second
+
+// SnapshotBackupReconciler schedules SnapshotBackups to the agent of the snapshot's node, by label obnvmf/target-node-id.
+// It resolves the base backup, and protects the snapshot by SnapshotBackupFinalizer until all backups of the snapshot are finished.
+// The agent uploads data and sets the backup to Completed or Failed.
+type SnapshotBackupReconciler struct {
+	client.Client
+	// APIReader reads from APIServer without cache, so that a backup just scheduled is not missed when releasing the snapshot.
+	// If it is nil, Client is used.
+	APIReader client.Reader
+	Log       logr.Logger
+	// Now returns current time. It is replaced in tests.
+	Now func() time.Time
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SnapshotBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: 1,
+		}).
+		For(&v1.SnapshotBackup{}).
+		Complete(r)
+}
+
+func (r *SnapshotBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var (
+		log    = r.Log.WithValues("SnapshotBackup", req.NamespacedName)
+		backup v1.SnapshotBackup
+	)
+
+	if err := r.Get(ctx, req.NamespacedName, &backup); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// the agent stops uploading when the backup is being deleted. The manifest is deleted, and chunks are kept in the bucket.
+	if backup.DeletionTimestamp != nil {
+		if !controllerutil.ContainsFinalizer(&backup, v1.SnapshotBackupFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.releaseSnapshot(ctx, &backup, log); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.deleteManifest(ctx, &backup, log); err != nil {
+			log.Error(err, "delete manifest of backup failed", "manifest", backup.Status.ManifestKey)
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(&backup, v1.SnapshotBackupFinalizer)
+		return ctrl.Result{}, r.Update(ctx, &backup)
+	}
+
+	if !controllerutil.ContainsFinalizer(&backup, v1.SnapshotBackupFinalizer) {
+		controllerutil.AddFinalizer(&backup, v1.SnapshotBackupFinalizer)
+		return ctrl.Result{}, r.Update(ctx, &backup)
+	}
+
+	if backup.IsFinished() {
+		return ctrl.Result{}, r.releaseSnapshot(ctx, &backup, log)
+	}
+
+	if backup.Status.Phase == "" {
+		return r.schedule(ctx, &backup, log)
+	}
+
+	// Pending or Uploading, the agent is uploading data
+	return ctrl.Result{}, nil
+}
+
+// schedule waits for the snapshot to be ready, and assigns the backup to the node of snapshot
+func (r *SnapshotBackupReconciler) schedule(ctx context.Context, backup *v1.SnapshotBackup, log logr.Logger) (ctrl.Result, error) {
+	var snap v1.AntstorSnapshot
+	err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.SnapshotName}, &snap)
+	if errors.IsNotFound(err) {
+		return r.fail(ctx, backup, fmt.Sprintf("snapshot %s is not found", backup.Spec.SnapshotName))
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var nodeID = snap.Labels[v1.TargetNodeIdLabelKey]
+	switch {
+	case snap.DeletionTimestamp != nil:
+		return r.fail(ctx, backup, fmt.Sprintf("snapshot %s is being deleted", snap.Name))
+	case snap.Status.Status == v1.SnapshotStatusError:
+		return r.fail(ctx, backup, fmt.Sprintf("snapshot %s is in error: %s", snap.Name, snap.Status.Message))
+	case snap.Status.Status == v1.SnapshotStatusMerging || snap.Status.Status == v1.SnapshotStatusMerged:
+		return r.fail(ctx, backup, fmt.Sprintf("snapshot %s is %s", snap.Name, snap.Status.Status))
+	case snap.Status.Status != v1.SnapshotStatusReady || nodeID == "":
+		msg := fmt.Sprintf("waiting for snapshot %s to be ready", snap.Name)
+		if backup.Status.Message != msg {
+			backup.Status.Message = msg
+			if err = r.Status().Update(ctx, backup); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: backupCheckInterval}, nil
+	}
+
+	base, msg, err := r.resolveBase(ctx, backup, &snap)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if msg != "" {
+		return r.fail(ctx, backup, msg)
+	}
+
+	// volumes are restored from the backup only in the namespace of PVC of the origin volume
+	pvcNs, err := r.pvcNamespaceOf(ctx, &snap)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the finalizer is added before the agent starts, so the snapshot is not deleted or merged during upload
+	if !controllerutil.ContainsFinalizer(&snap, v1.SnapshotBackupFinalizer) {
+		controllerutil.AddFinalizer(&snap, v1.SnapshotBackupFinalizer)
+		if err = r.Update(ctx, &snap); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if backup.Labels == nil {
+		backup.Labels = make(map[string]string)
+	}
+	backup.Labels[v1.TargetNodeIdLabelKey] = nodeID
+	backup.Labels[v1.SnapshotBackupSnapshotLabelKey] = snap.Name
+	backup.Labels[v1.OriginVolumeNameLabelKey] = snap.Spec.OriginVolName
+	if err = r.Update(ctx, backup); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.Info("schedule backup", "node", nodeID, "base", base)
+	backup.Status.Phase = v1.SnapshotBackupPhasePending
+	backup.Status.Message = ""
+	backup.Status.NodeID = nodeID
+	backup.Status.VolumeName = snap.Spec.OriginVolName
+	backup.Status.PvcNamespace = pvcNs
+	backup.Status.BaseBackup = base
+	return ctrl.Result{}, r.Status().Update(ctx, backup)
+}
+
+// pvcNamespaceOf returns the namespace of PVC of the origin volume of snapshot
+func (r *SnapshotBackupReconciler) pvcNamespaceOf(ctx context.Context, snap *v1.AntstorSnapshot) (ns string, err error) {
+	if ns, has := snap.Labels[v1.VolumeContextKeyPvcNS]; has {
+		return ns, nil
+	}
+	var vol v1.AntstorVolume
+	err = r.Get(ctx, client.ObjectKey{Namespace: snap.Spec.OriginVolNamespace, Name: snap.Spec.OriginVolName}, &vol)
+	if err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	return vol.Labels[v1.VolumeContextKeyPvcNS], nil
+}
+
+// resolveBase returns the name of base backup. If the base backup is invalid, msg is the reason.
+// Backup of thin snapshot uses the latest Completed backup of the same volume and target by default.
+func (r *SnapshotBackupReconciler) resolveBase(ctx context.Context, backup *v1.SnapshotBackup, snap *v1.AntstorSnapshot) (base, msg string, err error) {
+	if name := backup.Spec.BaseBackupName; name != "" {
+		var baseBackup v1.SnapshotBackup
+		err = r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: name}, &baseBackup)
+		if errors.IsNotFound(err) {
+			return "", fmt.Sprintf("base backup %s is not found", name), nil
+		}
+		if err != nil {
+			return
+		}
+		if msg = incompatibleBase(backup, &baseBackup); msg != "" {
+			return "", fmt.Sprintf("base backup %s %s", name, msg), nil
+		}
+		return name, "", nil
+	}
+
+	if !snap.Spec.IsThin {
+		return
+	}
+	var list v1.SnapshotBackupList
+	err = r.List(ctx, &list, client.InNamespace(backup.Namespace), client.MatchingLabels{
+		v1.OriginVolumeNameLabelKey: snap.Spec.OriginVolName,
+	})
+	if err != nil {
+		return
+	}
+	var latest *v1.SnapshotBackup
+	for i := range list.Items {
+		item := &list.Items[i]
+		if item.Name == backup.Name || item.DeletionTimestamp != nil || incompatibleBase(backup, item) != "" {
+			continue
+		}
+		if latest == nil || latest.Status.CompletionTime.Before(item.Status.CompletionTime) {
+			latest = item
+		}
+	}
+	if latest != nil {
+		base = latest.Name
+	}
+	return
+}
+
+// incompatibleBase returns the reason why base cannot be the base backup of backup
+func incompatibleBase(backup, base *v1.SnapshotBackup) string {
+	switch {
+	case base.Status.Phase != v1.SnapshotBackupPhaseCompleted:
+		return "is not completed"
+	case !backup.Spec.Target.SameTarget(base.Spec.Target):
+		return "is in another bucket or prefix"
+	case backup.ChunkSize() != base.ChunkSize():
+		return fmt.Sprintf("has chunk size %d", base.ChunkSize())
+	}
+	return ""
+}
+
+// releaseSnapshot removes SnapshotBackupFinalizer from the snapshot, if no other backup of the snapshot is running
+func (r *SnapshotBackupReconciler) releaseSnapshot(ctx context.Context, backup *v1.SnapshotBackup, log logr.Logger) error {
+	var snap v1.AntstorSnapshot
+	err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: backup.Spec.SnapshotName}, &snap)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !controllerutil.ContainsFinalizer(&snap, v1.SnapshotBackupFinalizer) {
+		return nil
+	}
+
+	// backups are labeled with the snapshot before they are set to Pending, so a labeled backup of empty phase is about to run
+	var list v1.SnapshotBackupList
+	err = r.reader().List(ctx, &list, client.InNamespace(backup.Namespace), client.MatchingLabels{
+		v1.SnapshotBackupSnapshotLabelKey: snap.Name,
+	})
+	if err != nil {
+		return err
+	}
+	for _, item := range list.Items {
+		if item.Name != backup.Name && item.DeletionTimestamp == nil && !item.IsFinished() {
+			log.Info("snapshot is still being backed up", "snapshot", snap.Name, "backup", item.Name)
+			return nil
+		}
+	}
+
+	log.Info("release snapshot of backup", "snapshot", snap.Name)
+	controllerutil.RemoveFinalizer(&snap, v1.SnapshotBackupFinalizer)
+	return r.Update(ctx, &snap)
+}
+
+// deleteManifest deletes the manifest of backup from the bucket, so the backup cannot be restored any more.
+// Chunks are shared by backups of the same prefix, so they are not deleted.
+func (r *SnapshotBackupReconciler) deleteManifest(ctx context.Context, backup *v1.SnapshotBackup, log logr.Logger) error {
+	if backup.Status.ManifestKey == "" {
+		return nil
+	}
+
+	var (
+		target   = backup.Spec.Target
+		ns, name = backup.CredentialsSecret()
+		secret   corev1.Secret
+	)
+	// Secrets are not cached by the manager
+	err := r.reader().Get(ctx, client.ObjectKey{Namespace: ns, Name: name}, &secret)
+	if errors.IsNotFound(err) {
+		log.Info("secret of backup target is not found, manifest is left in the bucket", "secret", ns+"/"+name, "manifest", backup.Status.ManifestKey)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	store, err := s3.NewClient(s3.Config{
+		Endpoint:  target.Endpoint,
+		Region:    target.Region,
+		AccessKey: string(secret.Data[v1.BackupSecretAccessKeyIDKey]),
+		SecretKey: string(secret.Data[v1.BackupSecretSecretAccessKeyKey]),
+		Insecure:  target.Insecure,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info("delete manifest of backup", "bucket", target.Bucket, "manifest", backup.Status.ManifestKey)
+	return store.DeleteObject(ctx, target.Bucket, backup.Status.ManifestKey)
+}
+
+// reader returns APIReader, or Client if APIReader is not set
+func (r *SnapshotBackupReconciler) reader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+func (r *SnapshotBackupReconciler) fail(ctx context.Context, backup *v1.SnapshotBackup, msg string) (ctrl.Result, error) {
+	r.Log.Info("snapshot backup failed", "backup", backup.Namespace+"/"+backup.Name, "reason", msg)
+	var now = metav1.NewTime(r.now())
+	backup.Status.Phase = v1.SnapshotBackupPhaseFailed
+	backup.Status.Message = msg
+	backup.Status.CompletionTime = &now
+	return ctrl.Result{}, r.Status().Update(ctx, backup)
+}
+
+func (r *SnapshotBackupReconciler) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,215 @@
+package reconciler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+)
+
+func TestSnapshotBackupReconcile(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+
+	var now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var target = v1.BackupTarget{
+		Endpoint:             "http://minio:9000",
+		Bucket:               "backup",
+		CredentialsSecretRef: corev1.SecretReference{Name: "minio"},
+	}
+	newSnap := func(name string) *v1.AntstorSnapshot {
+		return &v1.AntstorSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: v1.DefaultNamespace,
+				Name:      name,
+				Labels: map[string]string{
+					v1.TargetNodeIdLabelKey:  "node-1",
+					v1.VolumeContextKeyPvcNS: "app",
+				},
+			},
+			Spec: v1.AntstorSnapshotSpec{
+				VolType:            v1.VolumeTypeKernelLVol,
+				OriginVolName:      "vol-1",
+				OriginVolNamespace: v1.DefaultNamespace,
+				IsThin:             true,
+			},
+			Status: v1.AntstorSnapshotStatus{Status: v1.SnapshotStatusReady},
+		}
+	}
+	newBackup := func(name, snap string) *v1.SnapshotBackup {
+		return &v1.SnapshotBackup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: name},
+			Spec:       v1.SnapshotBackupSpec{SnapshotName: snap, Target: target},
+		}
+	}
+	completed := func(name string, completedAt time.Time, chunkSize int64) *v1.SnapshotBackup {
+		b := newBackup(name, "snap-old")
+		b.Labels = map[string]string{v1.OriginVolumeNameLabelKey: "vol-1"}
+		b.Spec.ChunkSizeBytes = chunkSize
+		b.Status.Phase = v1.SnapshotBackupPhaseCompleted
+		b.Status.CompletionTime = &metav1.Time{Time: completedAt}
+		return b
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newSnap("snap-1"),
+		completed("full", now.Add(-2*time.Hour), 0),
+		completed("incr", now.Add(-time.Hour), 0),
+		// latest, but chunks of different size cannot be compared
+		completed("other-size", now.Add(-time.Minute), 8<<20),
+		newBackup("bak-1", "snap-1"),
+		newBackup("missing", "snap-x"),
+	).Build()
+	r := &SnapshotBackupReconciler{
+		Client: cli,
+		Log:    zap.New(),
+		Now:    func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	reconcile := func(name string) *v1.SnapshotBackup {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: v1.DefaultNamespace, Name: name}})
+		assert.NoError(t, err)
+		var backup v1.SnapshotBackup
+		assert.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: v1.DefaultNamespace, Name: name}, &backup))
+		return &backup
+	}
+	getSnap := func(name string) *v1.AntstorSnapshot {
+		var snap v1.AntstorSnapshot
+		assert.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: v1.DefaultNamespace, Name: name}, &snap))
+		return &snap
+	}
+
+	// backup is scheduled to the node of snapshot, incremental to the latest compatible backup
+	backup := reconcile("bak-1")
+	assert.True(t, controllerutil.ContainsFinalizer(backup, v1.SnapshotBackupFinalizer))
+	assert.Equal(t, v1.SnapshotBackupPhase(""), backup.Status.Phase)
+	backup = reconcile("bak-1")
+	assert.Equal(t, v1.SnapshotBackupPhasePending, backup.Status.Phase)
+	assert.Equal(t, "node-1", backup.Status.NodeID)
+	assert.Equal(t, "vol-1", backup.Status.VolumeName)
+	assert.Equal(t, "app", backup.Status.PvcNamespace)
+	assert.Equal(t, "incr", backup.Status.BaseBackup)
+	assert.Equal(t, "node-1", backup.Labels[v1.TargetNodeIdLabelKey])
+	assert.Equal(t, "snap-1", backup.Labels[v1.SnapshotBackupSnapshotLabelKey])
+	assert.True(t, controllerutil.ContainsFinalizer(getSnap("snap-1"), v1.SnapshotBackupFinalizer))
+
+	// running backup keeps the snapshot
+	backup = reconcile("bak-1")
+	assert.Equal(t, v1.SnapshotBackupPhasePending, backup.Status.Phase)
+	assert.True(t, controllerutil.ContainsFinalizer(getSnap("snap-1"), v1.SnapshotBackupFinalizer))
+
+	// backup labeled by scheduling, whose phase is not updated yet, keeps the snapshot
+	bak3 := newBackup("bak-3", "snap-1")
+	bak3.Labels = map[string]string{
+		v1.TargetNodeIdLabelKey:           "node-1",
+		v1.SnapshotBackupSnapshotLabelKey: "snap-1",
+	}
+	assert.NoError(t, cli.Create(ctx, bak3))
+	backup.Status.Phase = v1.SnapshotBackupPhaseCompleted
+	assert.NoError(t, cli.Status().Update(ctx, backup))
+	reconcile("bak-1")
+	assert.True(t, controllerutil.ContainsFinalizer(getSnap("snap-1"), v1.SnapshotBackupFinalizer))
+
+	// finished backup releases the snapshot
+	bak3 = reconcile("bak-3")
+	bak3.Status.Phase = v1.SnapshotBackupPhaseFailed
+	assert.NoError(t, cli.Status().Update(ctx, bak3))
+	reconcile("bak-3")
+	assert.False(t, controllerutil.ContainsFinalizer(getSnap("snap-1"), v1.SnapshotBackupFinalizer))
+
+	// explicit base backup must be completed in the same target
+	bak2 := newBackup("bak-2", "snap-1")
+	bak2.Spec.BaseBackupName = "other-size"
+	assert.NoError(t, cli.Create(ctx, bak2))
+	reconcile("bak-2")
+	backup = reconcile("bak-2")
+	assert.Equal(t, v1.SnapshotBackupPhaseFailed, backup.Status.Phase)
+	assert.Contains(t, backup.Status.Message, "chunk size")
+
+	// snapshot is not found
+	reconcile("missing")
+	backup = reconcile("missing")
+	assert.Equal(t, v1.SnapshotBackupPhaseFailed, backup.Status.Phase)
+	assert.Equal(t, now.Unix(), backup.Status.CompletionTime.Unix())
+
+	// deleting backup removes its finalizer
+	assert.NoError(t, cli.Delete(ctx, backup))
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "missing"}})
+	assert.NoError(t, err)
+	err = cli.Get(ctx, client.ObjectKey{Namespace: v1.DefaultNamespace, Name: "missing"}, backup)
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestSnapshotBackupDeleteManifest(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodDelete {
+			deleted = append(deleted, req.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "minio"},
+		Data: map[string][]byte{
+			v1.BackupSecretAccessKeyIDKey:     []byte("ak"),
+			v1.BackupSecretSecretAccessKeyKey: []byte("sk"),
+		},
+	}
+	backup := &v1.SnapshotBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  v1.DefaultNamespace,
+			Name:       "bak-1",
+			Finalizers: []string{v1.SnapshotBackupFinalizer},
+		},
+		Spec: v1.SnapshotBackupSpec{
+			SnapshotName: "snap-1",
+			Target: v1.BackupTarget{
+				Endpoint:             server.URL,
+				Bucket:               "backup",
+				CredentialsSecretRef: corev1.SecretReference{Name: "minio"},
+			},
+		},
+		Status: v1.SnapshotBackupStatus{
+			Phase:       v1.SnapshotBackupPhaseCompleted,
+			ManifestKey: "backups/obnvmf/bak-1/manifest.json",
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, backup).Build()
+	r := &SnapshotBackupReconciler{
+		Client: cli,
+		Log:    zap.New(),
+	}
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "bak-1"}
+
+	// manifest is deleted before the finalizer is removed
+	assert.NoError(t, cli.Delete(ctx, backup))
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/backup/backups/obnvmf/bak-1/manifest.json"}, deleted)
+	err = cli.Get(ctx, key, backup)
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,413 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	uuid "github.com/satori/go.uuid"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/util"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+const (
+	// groupPrepareTimeout is the max time for member snapshots to be bound to nodes and frozen
+	groupPrepareTimeout = 5 * time.Minute
+	// groupCheckInterval is the interval of checking member snapshots
+	groupCheckInterval = 2 * time.Second
+)
+
+// SnapshotGroupReconciler takes crash-consistent snapshots of several volumes.
+// It creates a member AntstorSnapshot for each volume. After all members are bound to nodes, it sets annotation obnvmf/freeze-timeout-seconds
+// to ask the agents to freeze the volumes. After all volumes are frozen, it sets annotation obnvmf/cut-at,
+// and the agents create the snapshots and thaw the volumes. Agents thaw the volumes by themselves if the cut does not come in time.
+type SnapshotGroupReconciler struct {
+	client.Client
+	Log logr.Logger
+	// Now returns current time. It is replaced in tests.
+	Now func() time.Time
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SnapshotGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: 1,
+		}).
+		For(&v1.SnapshotGroup{}).
+		Owns(&v1.AntstorSnapshot{}).
+		Complete(r)
+}
+
+func (r *SnapshotGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var (
+		log   = r.Log.WithValues("SnapshotGroup", req.NamespacedName)
+		group v1.SnapshotGroup
+		snaps = make(map[string]*v1.AntstorSnapshot)
+	)
+
+	if err := r.Get(ctx, req.NamespacedName, &group); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// member snapshots are deleted by garbage collector
+	if group.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	if len(group.Status.Members) == 0 {
+		if group.Status.Phase == v1.SnapshotGroupPhaseFailed {
+			return ctrl.Result{}, nil
+		}
+		return r.resolveMembers(ctx, &group, log)
+	}
+
+	for _, member := range group.Status.Members {
+		var snap v1.AntstorSnapshot
+		err := r.Get(ctx, client.ObjectKey{Namespace: group.Namespace, Name: member.SnapshotName}, &snap)
+		if err == nil {
+			snaps[member.SnapshotName] = &snap
+		} else if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+
+	var origStatus = group.Status.DeepCopy()
+	syncMemberStatus(&group, snaps)
+
+	switch group.Status.Phase {
+	case v1.SnapshotGroupPhaseFailed:
+		// agents thaw the frozen volumes when the member snapshots are deleted
+		for _, snap := range snaps {
+			if snap.DeletionTimestamp == nil {
+				log.Info("delete member snapshot of failed group", "snapshot", snap.Name)
+				if err := r.Delete(ctx, snap); client.IgnoreNotFound(err) != nil {
+					return ctrl.Result{}, err
+				}
+			}
+		}
+		return ctrl.Result{}, r.updateStatus(ctx, &group, origStatus)
+
+	case v1.SnapshotGroupPhaseReady:
+		return ctrl.Result{}, r.updateStatus(ctx, &group, origStatus)
+
+	case v1.SnapshotGroupPhasePending:
+		var pending []string
+		for _, member := range group.Status.Members {
+			snap := snaps[member.SnapshotName]
+			if snap == nil {
+				if err := r.createMember(ctx, &group, member, log); err != nil {
+					return r.fail(ctx, &group, fmt.Sprintf("volume %s: %s", member.VolumeName, err.Error()))
+				}
+				pending = append(pending, member.VolumeName)
+			} else if snap.Status.Status != v1.SnapshotStatusCreating {
+				pending = append(pending, member.VolumeName)
+			}
+		}
+		if len(pending) > 0 {
+			if r.now().Sub(group.Status.StartTime.Time) > groupPrepareTimeout {
+				return r.fail(ctx, &group, fmt.Sprintf("snapshots of volumes %v are not scheduled in %s", pending, groupPrepareTimeout))
+			}
+			group.Status.Message = fmt.Sprintf("waiting for snapshots of volumes %v to be scheduled", pending)
+			return ctrl.Result{RequeueAfter: groupCheckInterval}, r.updateStatus(ctx, &group, origStatus)
+		}
+
+		// all members are bound to nodes, ask the agents to freeze the volumes
+		var timeout = strconv.Itoa(int(freezeTimeoutOf(&group).Seconds()))
+		for _, snap := range snaps {
+			if err := r.setAnnotation(ctx, snap, v1.FreezeTimeoutAnnoKey, timeout); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		log.Info("freezing volumes of group")
+		group.Status.Phase = v1.SnapshotGroupPhaseFreezing
+		group.Status.Message = ""
+		return ctrl.Result{RequeueAfter: groupCheckInterval}, r.updateStatus(ctx, &group, origStatus)
+
+	case v1.SnapshotGroupPhaseFreezing:
+		var (
+			notFrozen []string
+			earliest  *metav1.Time
+		)
+		for _, member := range group.Status.Members {
+			if snaps[member.SnapshotName] == nil {
+				return r.fail(ctx, &group, fmt.Sprintf("snapshot %s is deleted", member.SnapshotName))
+			}
+			if member.ThawedTime != nil {
+				return r.fail(ctx, &group, fmt.Sprintf("volume %s is thawed before the cut", member.VolumeName))
+			}
+			if member.FrozenTime == nil {
+				notFrozen = append(notFrozen, member.VolumeName)
+			} else if earliest == nil || member.FrozenTime.Before(earliest) {
+				earliest = member.FrozenTime
+			}
+		}
+		if len(notFrozen) > 0 {
+			var now = r.now()
+			if (earliest != nil && now.Sub(earliest.Time) > freezeTimeoutOf(&group)) || now.Sub(group.Status.StartTime.Time) > groupPrepareTimeout {
+				return r.fail(ctx, &group, fmt.Sprintf("volumes %v are not frozen in time", notFrozen))
+			}
+			group.Status.Message = fmt.Sprintf("waiting for volumes %v to be frozen", notFrozen)
+			return ctrl.Result{RequeueAfter: groupCheckInterval}, r.updateStatus(ctx, &group, origStatus)
+		}
+
+		// all volumes are frozen, now is the point in time of the snapshots
+		var cutTime = metav1.NewTime(r.now())
+		if err := r.cutMembers(ctx, snaps, cutTime); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("all volumes are frozen, cut snapshots", "cutTime", cutTime)
+		group.Status.Phase = v1.SnapshotGroupPhaseSnapshotting
+		group.Status.Message = ""
+		group.Status.CutTime = &cutTime
+		return ctrl.Result{RequeueAfter: groupCheckInterval}, r.updateStatus(ctx, &group, origStatus)
+
+	case v1.SnapshotGroupPhaseSnapshotting:
+		// annotation may be not set on all members, if the controller restarted during cutting
+		if err := r.cutMembers(ctx, snaps, *group.Status.CutTime); err != nil {
+			return ctrl.Result{}, err
+		}
+		var notReady []string
+		for _, member := range group.Status.Members {
+			snap := snaps[member.SnapshotName]
+			if snap == nil {
+				return r.fail(ctx, &group, fmt.Sprintf("snapshot %s is deleted", member.SnapshotName))
+			}
+			// agent thaws the volume and adds SnapshotFinalizer together after the snapshot is created
+			if member.ThawedTime != nil && !misc.InSliceString(v1.SnapshotFinalizer, snap.Finalizers) {
+				return r.fail(ctx, &group, fmt.Sprintf("volume %s is thawed before the snapshot is created", member.VolumeName))
+			}
+			if member.Status != v1.SnapshotStatusReady {
+				notReady = append(notReady, member.VolumeName)
+			}
+		}
+		if len(notReady) > 0 {
+			group.Status.Message = fmt.Sprintf("waiting for snapshots of volumes %v to be ready", notReady)
+			return ctrl.Result{RequeueAfter: groupCheckInterval}, r.updateStatus(ctx, &group, origStatus)
+		}
+
+		var now = metav1.NewTime(r.now())
+		log.Info("all snapshots of group are ready")
+		group.Status.Phase = v1.SnapshotGroupPhaseReady
+		group.Status.Message = ""
+		group.Status.CompletionTime = &now
+		return ctrl.Result{}, r.updateStatus(ctx, &group, origStatus)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// resolveMembers decides the volumes of group from VolumeNames and Selector
+func (r *SnapshotGroupReconciler) resolveMembers(ctx context.Context, group *v1.SnapshotGroup, log logr.Logger) (ctrl.Result, error) {
+	var nameSet = make(map[string]bool)
+	for _, name := range group.Spec.VolumeNames {
+		nameSet[name] = true
+	}
+	if group.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(group.Spec.Selector)
+		if err != nil {
+			return r.fail(ctx, group, fmt.Sprintf("invalid selector: %s", err.Error()))
+		}
+		var vols v1.AntstorVolumeList
+		if err = r.List(ctx, &vols, client.InNamespace(group.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return ctrl.Result{}, err
+		}
+		for _, vol := range vols.Items {
+			nameSet[vol.Name] = true
+		}
+	}
+	if len(nameSet) == 0 {
+		return r.fail(ctx, group, "no volume is selected")
+	}
+
+	var names = make([]string, 0, len(nameSet))
+	for name := range nameSet {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var members = make([]v1.SnapshotGroupMember, 0, len(names))
+	for _, name := range names {
+		var vol v1.AntstorVolume
+		if err := r.Get(ctx, client.ObjectKey{Namespace: group.Namespace, Name: name}, &vol); err != nil {
+			if errors.IsNotFound(err) {
+				return r.fail(ctx, group, fmt.Sprintf("volume %s not found", name))
+			}
+			return ctrl.Result{}, err
+		}
+		if vol.Status.Status != v1.VolumeStatusReady || vol.DeletionTimestamp != nil {
+			return r.fail(ctx, group, fmt.Sprintf("volume %s is not ready", name))
+		}
+		members = append(members, v1.SnapshotGroupMember{
+			VolumeName:   vol.Name,
+			SnapshotName: fmt.Sprintf("%s-%s", group.Name, vol.Name),
+			TargetNodeID: vol.Spec.TargetNodeId,
+		})
+	}
+
+	var now = metav1.NewTime(r.now())
+	log.Info("resolved members of group", "volumes", names)
+	group.Status.Phase = v1.SnapshotGroupPhasePending
+	group.Status.StartTime = &now
+	group.Status.Members = members
+	return ctrl.Result{Requeue: true}, r.Status().Update(ctx, group)
+}
+
+// createMember creates the member snapshot of volume. It is owned by the group.
+func (r *SnapshotGroupReconciler) createMember(ctx context.Context, group *v1.SnapshotGroup, member v1.SnapshotGroupMember, log logr.Logger) (err error) {
+	var vol v1.AntstorVolume
+	if err = r.Get(ctx, client.ObjectKey{Namespace: group.Namespace, Name: member.VolumeName}, &vol); err != nil {
+		return
+	}
+
+	// thin snapshots share blocks with the thin volume, they need no reserved space
+	var size = int64(vol.Spec.SizeByte)
+	if !vol.IsThinLVol() {
+		reserved, err := strconv.ParseInt(vol.Annotations[v1.SnapshotReservedSpaceAnnotationKey], 10, 64)
+		if err != nil || reserved <= 0 {
+			return fmt.Errorf("volume has no snapshot reserved space")
+		}
+		size = reserved
+		if group.Spec.SnapshotSize != nil {
+			size = group.Spec.SnapshotSize.Value()
+		}
+		size = size / util.FourMiB * util.FourMiB
+		if size < util.FourMiB {
+			return fmt.Errorf("snapshot size %d is too small, at least 4MiB", size)
+		}
+	}
+
+	snap := &v1.AntstorSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: group.Namespace,
+			Name:      member.SnapshotName,
+			Labels: map[string]string{
+				v1.OriginVolumeNameLabelKey:      vol.Name,
+				v1.OriginVolumeNamespaceLabelKey: vol.Namespace,
+				v1.SnapshotGroupLabelKey:         group.Name,
+			},
+			Annotations: make(map[string]string),
+		},
+		Spec: v1.AntstorSnapshotSpec{
+			Uuid:               uuid.NewV4().String(),
+			VolType:            vol.Spec.Type,
+			Size:               size,
+			OriginVolName:      vol.Name,
+			OriginVolNamespace: vol.Namespace,
+			IsThin:             vol.IsThinLVol(),
+		},
+	}
+	snap.Labels[v1.SnapUuidLabelKey] = snap.Spec.Uuid
+	if pvcNS := vol.QuotaNamespace(); pvcNS != "" {
+		snap.Labels[v1.VolumeContextKeyPvcNS] = pvcNS
+		if err = checkSnapshotQuota(ctx, r.Client, pvcNS, snap); err != nil {
+			return
+		}
+	}
+	if err = controllerutil.SetControllerReference(group, snap, r.Scheme()); err != nil {
+		return
+	}
+
+	log.Info("create member snapshot", "snapshot", snap.Name, "size", size)
+	return r.Create(ctx, snap)
+}
+
+// cutMembers sets annotation obnvmf/cut-at on member snapshots
+func (r *SnapshotGroupReconciler) cutMembers(ctx context.Context, snaps map[string]*v1.AntstorSnapshot, cutTime metav1.Time) (err error) {
+	var value = strconv.FormatInt(cutTime.Unix(), 10)
+	for _, snap := range snaps {
+		if err = r.setAnnotation(ctx, snap, v1.CutAtAnnoKey, value); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// setAnnotation updates the annotation of snapshot, if it is not set
+func (r *SnapshotGroupReconciler) setAnnotation(ctx context.Context, snap *v1.AntstorSnapshot, key, value string) error {
+	if _, has := snap.Annotations[key]; has {
+		return nil
+	}
+	if snap.Annotations == nil {
+		snap.Annotations = make(map[string]string)
+	}
+	snap.Annotations[key] = value
+	return r.Update(ctx, snap)
+}
+
+func (r *SnapshotGroupReconciler) fail(ctx context.Context, group *v1.SnapshotGroup, msg string) (ctrl.Result, error) {
+	r.Log.Info("snapshot group failed", "group", group.Namespace+"/"+group.Name, "reason", msg)
+	var now = metav1.NewTime(r.now())
+	group.Status.Phase = v1.SnapshotGroupPhaseFailed
+	group.Status.Message = msg
+	group.Status.CompletionTime = &now
+	if err := r.Status().Update(ctx, group); err != nil {
+		return ctrl.Result{}, err
+	}
+	// delete member snapshots in the next round
+	return ctrl.Result{Requeue: len(group.Status.Members) > 0}, nil
+}
+
+func (r *SnapshotGroupReconciler) updateStatus(ctx context.Context, group *v1.SnapshotGroup, origStatus *v1.SnapshotGroupStatus) error {
+	if equality.Semantic.DeepEqual(origStatus, &group.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, group)
+}
+
+func (r *SnapshotGroupReconciler) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
+
+// syncMemberStatus copies status and freeze annotations of member snapshots to status of group
+func syncMemberStatus(group *v1.SnapshotGroup, snaps map[string]*v1.AntstorSnapshot) {
+	for i := range group.Status.Members {
+		member := &group.Status.Members[i]
+		snap := snaps[member.SnapshotName]
+		if snap == nil {
+			continue
+		}
+		member.Status = snap.Status.Status
+		member.FrozenTime = annotationTime(snap, v1.FrozenAtAnnoKey)
+		member.ThawedTime = annotationTime(snap, v1.ThawedAtAnnoKey)
+		if nodeID := snap.Spec.OriginVolTargetNodeID; nodeID != "" {
+			member.TargetNodeID = nodeID
+		}
+	}
+}
+
+// annotationTime parses the unix timestamp in annotation of snapshot. It returns nil if the annotation is not set.
+func annotationTime(snap *v1.AntstorSnapshot, key string) *metav1.Time {
+	val, has := snap.Annotations[key]
+	if !has {
+		return nil
+	}
+	sec, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return nil
+	}
+	t := metav1.NewTime(time.Unix(sec, 0))
+	return &t
+}
+
+func freezeTimeoutOf(group *v1.SnapshotGroup) time.Duration {
+	var sec = int(group.Spec.FreezeTimeoutSeconds)
+	if sec <= 0 {
+		sec = v1.DefaultFreezeTimeoutSeconds
+	}
+	return time.Duration(sec) * time.Second
+}
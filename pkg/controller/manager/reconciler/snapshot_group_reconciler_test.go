@@ -0,0 +1,184 @@
+package reconciler
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+)
+
+func TestSnapshotGroupReconcile(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+
+	newVol := func(name, app string) *v1.AntstorVolume {
+		return &v1.AntstorVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: v1.DefaultNamespace,
+				Name:      name,
+				Labels:    map[string]string{"app": app},
+				Annotations: map[string]string{
+					v1.SnapshotReservedSpaceAnnotationKey: "8589934592",
+				},
+			},
+			Spec: v1.AntstorVolumeSpec{
+				Type:         v1.VolumeTypeKernelLVol,
+				SizeByte:     10 << 30,
+				TargetNodeId: "node-" + name,
+			},
+			Status: v1.AntstorVolumeStatus{Status: v1.VolumeStatusReady},
+		}
+	}
+	newGroup := func(name string) *v1.SnapshotGroup {
+		return &v1.SnapshotGroup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: name},
+			Spec: v1.SnapshotGroupSpec{
+				VolumeNames:          []string{"vol-1"},
+				Selector:             &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+				FreezeTimeoutSeconds: 5,
+			},
+		}
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newVol("vol-1", "web"),
+		newVol("vol-2", "db"),
+		newGroup("grp"),
+		newGroup("timeout"),
+	).Build()
+	var now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := &SnapshotGroupReconciler{
+		Client: cli,
+		Log:    zap.New(),
+		Now:    func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	reconcile := func(name string) *v1.SnapshotGroup {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: v1.DefaultNamespace, Name: name}})
+		assert.NoError(t, err)
+		var group v1.SnapshotGroup
+		assert.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: v1.DefaultNamespace, Name: name}, &group))
+		return &group
+	}
+	getSnap := func(name string) *v1.AntstorSnapshot {
+		var snap v1.AntstorSnapshot
+		assert.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: v1.DefaultNamespace, Name: name}, &snap))
+		return &snap
+	}
+	// updateSnap mocks the SnapshotReconciler and the agent
+	updateSnap := func(name string, fn func(snap *v1.AntstorSnapshot)) {
+		snap := getSnap(name)
+		fn(snap)
+		assert.NoError(t, cli.Update(ctx, snap))
+	}
+	unix := func(t time.Time) string {
+		return strconv.FormatInt(t.Unix(), 10)
+	}
+
+	// resolve members
+	group := reconcile("grp")
+	assert.Equal(t, v1.SnapshotGroupPhasePending, group.Status.Phase)
+	assert.Len(t, group.Status.Members, 2)
+	assert.Equal(t, "grp-vol-1", group.Status.Members[0].SnapshotName)
+	assert.Equal(t, "grp-vol-2", group.Status.Members[1].SnapshotName)
+	assert.Equal(t, "node-vol-2", group.Status.Members[1].TargetNodeID)
+
+	// create member snapshots
+	group = reconcile("grp")
+	assert.Equal(t, v1.SnapshotGroupPhasePending, group.Status.Phase)
+	snap := getSnap("grp-vol-1")
+	assert.Equal(t, int64(8<<30), snap.Spec.Size)
+	assert.Equal(t, "grp", snap.Labels[v1.SnapshotGroupLabelKey])
+	assert.Equal(t, "vol-1", snap.Spec.OriginVolName)
+	assert.Len(t, snap.OwnerReferences, 1)
+	assert.Empty(t, snap.Annotations[v1.FreezeTimeoutAnnoKey])
+
+	// members are bound to nodes, ask for freezing
+	for _, name := range []string{"grp-vol-1", "grp-vol-2"} {
+		updateSnap(name, func(snap *v1.AntstorSnapshot) {
+			snap.Status.Status = v1.SnapshotStatusCreating
+		})
+	}
+	group = reconcile("grp")
+	assert.Equal(t, v1.SnapshotGroupPhaseFreezing, group.Status.Phase)
+	assert.Equal(t, "5", getSnap("grp-vol-2").Annotations[v1.FreezeTimeoutAnnoKey])
+
+	// only one volume is frozen, not cut
+	now = now.Add(time.Second)
+	updateSnap("grp-vol-1", func(snap *v1.AntstorSnapshot) {
+		snap.Annotations[v1.FrozenAtAnnoKey] = unix(now)
+	})
+	group = reconcile("grp")
+	assert.Equal(t, v1.SnapshotGroupPhaseFreezing, group.Status.Phase)
+	assert.NotNil(t, group.Status.Members[0].FrozenTime)
+	assert.Empty(t, getSnap("grp-vol-1").Annotations[v1.CutAtAnnoKey])
+
+	// all volumes are frozen, cut
+	now = now.Add(time.Second)
+	updateSnap("grp-vol-2", func(snap *v1.AntstorSnapshot) {
+		snap.Annotations[v1.FrozenAtAnnoKey] = unix(now)
+	})
+	group = reconcile("grp")
+	assert.Equal(t, v1.SnapshotGroupPhaseSnapshotting, group.Status.Phase)
+	assert.Equal(t, now.Unix(), group.Status.CutTime.Unix())
+	assert.Equal(t, unix(now), getSnap("grp-vol-1").Annotations[v1.CutAtAnnoKey])
+	assert.Equal(t, unix(now), getSnap("grp-vol-2").Annotations[v1.CutAtAnnoKey])
+
+	// snapshots are created and volumes are thawed
+	for _, name := range []string{"grp-vol-1", "grp-vol-2"} {
+		updateSnap(name, func(snap *v1.AntstorSnapshot) {
+			snap.Annotations[v1.ThawedAtAnnoKey] = unix(now)
+			snap.Finalizers = append(snap.Finalizers, v1.SnapshotFinalizer)
+			snap.Status.Status = v1.SnapshotStatusReady
+		})
+	}
+	group = reconcile("grp")
+	assert.Equal(t, v1.SnapshotGroupPhaseReady, group.Status.Phase)
+	assert.NotNil(t, group.Status.CompletionTime)
+	assert.Equal(t, v1.SnapshotStatusReady, group.Status.Members[1].Status)
+	assert.NotNil(t, group.Status.Members[1].ThawedTime)
+
+	// one volume is never frozen, the group fails and member snapshots are deleted
+	reconcile("timeout")
+	reconcile("timeout")
+	for _, name := range []string{"timeout-vol-1", "timeout-vol-2"} {
+		updateSnap(name, func(snap *v1.AntstorSnapshot) {
+			snap.Status.Status = v1.SnapshotStatusCreating
+		})
+	}
+	group = reconcile("timeout")
+	assert.Equal(t, v1.SnapshotGroupPhaseFreezing, group.Status.Phase)
+	updateSnap("timeout-vol-1", func(snap *v1.AntstorSnapshot) {
+		snap.Annotations[v1.FrozenAtAnnoKey] = unix(now)
+	})
+	now = now.Add(6 * time.Second)
+	group = reconcile("timeout")
+	assert.Equal(t, v1.SnapshotGroupPhaseFailed, group.Status.Phase)
+	assert.Contains(t, group.Status.Message, "vol-2")
+	group = reconcile("timeout")
+	assert.Equal(t, v1.SnapshotGroupPhaseFailed, group.Status.Phase)
+	err := cli.Get(ctx, client.ObjectKey{Namespace: v1.DefaultNamespace, Name: "timeout-vol-1"}, &v1.AntstorSnapshot{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// missing volume
+	assert.NoError(t, cli.Create(ctx, &v1.SnapshotGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "missing"},
+		Spec:       v1.SnapshotGroupSpec{VolumeNames: []string{"vol-x"}},
+	}))
+	group = reconcile("missing")
+	assert.Equal(t, v1.SnapshotGroupPhaseFailed, group.Status.Phase)
+	assert.Equal(t, "volume vol-x not found", group.Status.Message)
+}
@@ -132,6 +132,32 @@ func (r *SnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, err
 	}
 
+	// get origin volume
+	volFullName := types.NamespacedName{
+		Namespace: obj.Spec.OriginVolNamespace,
+		Name:      obj.Spec.OriginVolName,
+	}
+	log.Info("get origin volume", "volFullName", volFullName)
+	err = r.Get(ctx, volFullName, &originVol)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// TODO: submit Event, update Status to error
+			log.Info("not found StoragePool", "name", volFullName)
+			r.EventRecorder.Event(&obj, corev1.EventTypeWarning, SnapshotCreateFailure, fmt.Sprintf("cannot find origin volume %s", volFullName))
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// temporary snapshot for volume cloning is deleted after data is copied, so it is not limited by the rules of user snapshot
+	_, isCopySource := obj.Labels[v1.SnapshotCopyForLabelKey]
+	// scheduled snapshots and member snapshots of SnapshotGroups of a volume co-exist, limited by the snapshot reserved space
+	var isMultiple = isMultipleSnapshot(&obj)
+	// thin snapshots of thin LV are chained in the thin pool and need no reserved space, so they are not limited either
+	var isThin = originVol.IsThinLVol()
+	// reservedUsed is the sum of sizes of older snapshots in the snapshot reserved space of origin volume.
+	// A new snapshot waits until there is enough space, and never blocks the older ones.
+	var reservedUsed int64
+
 	// 2. The origin volume should only have one snapshot
 	snapList, err := r.AntstorClientset.VolumeV1().AntstorSnapshots(obj.Namespace).List(context.Background(), metav1.ListOptions{
 		LabelSelector: fmt.Sprintf("%s=%s", v1.OriginVolumeNameLabelKey, obj.Spec.OriginVolName),
@@ -141,10 +167,19 @@ func (r *SnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, err
 	}
 	for _, item := range snapList.Items {
-		// skip itself
+		// skip itself and temporary snapshots
 		if item.Name == obj.Name && item.Namespace == obj.Namespace {
 			continue
 		}
+		if _, has := item.Labels[v1.SnapshotCopyForLabelKey]; has || isCopySource || isThin {
+			continue
+		}
+		if item.Status.Status != v1.SnapshotStatusMerged && isOlderSnapshot(&item, &obj) {
+			reservedUsed += item.Spec.Size
+		}
+		if isMultiple && isMultipleSnapshot(&item) {
+			continue
+		}
 		if item.Status.Status != v1.SnapshotStatusMerged {
 			log.Info("origin volume can only have one snapshot", "originVolName", obj.Spec.OriginVolName)
 			r.EventRecorder.Event(&obj, corev1.EventTypeWarning, SnapshotCreateFailure, "origin volume can only have one snapshot")
@@ -152,22 +187,6 @@ func (r *SnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		}
 	}
 
-	// get origin volume
-	volFullName := types.NamespacedName{
-		Namespace: obj.Spec.OriginVolNamespace,
-		Name:      obj.Spec.OriginVolName,
-	}
-	log.Info("get origin volume", "volFullName", volFullName)
-	err = r.Get(ctx, volFullName, &originVol)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			// TODO: submit Event, update Status to error
-			log.Info("not found StoragePool", "name", volFullName)
-			r.EventRecorder.Event(&obj, corev1.EventTypeWarning, SnapshotCreateFailure, fmt.Sprintf("cannot find origin volume %s", volFullName))
-		}
-		return ctrl.Result{}, client.IgnoreNotFound(err)
-	}
-
 	// TODO: validate origin volume
 	if originVol.Spec.TargetNodeId == "" {
 		log.Info("origin vol is not scheduled. Snapshot creation will be retried in 1 min", "volName", volFullName)
@@ -175,7 +194,12 @@ func (r *SnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{Requeue: true, RequeueAfter: time.Minute}, nil
 	}
 
-	if originVol.Annotations == nil {
+	// size of temporary snapshot is decided by the agent which copies data
+	if isCopySource {
+		log.Info("snapshot is the source of volume cloning, skip checking reserved space")
+	} else if isThin {
+		log.Info("snapshot is a thin snapshot, skip checking reserved space")
+	} else if originVol.Annotations == nil {
 		r.EventRecorder.Event(&obj, corev1.EventTypeWarning, SnapshotCreateFailure, "origin volume has no obnvmf/snapshot-reserved-bytes in annotations")
 		return ctrl.Result{Requeue: true, RequeueAfter: 3 * time.Minute}, nil
 	} else {
@@ -186,16 +210,17 @@ func (r *SnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			return ctrl.Result{Requeue: true, RequeueAfter: 3 * time.Minute}, nil
 		}
 
-		if obj.Spec.Size > int64(snapReservedBytes) {
-			r.EventRecorder.Event(&obj, corev1.EventTypeWarning, SnapshotCreateFailure, fmt.Sprintf("snap size too large: %d, reserved size %d", obj.Spec.Size, snapReservedBytes))
+		if obj.Spec.Size+reservedUsed > int64(snapReservedBytes) {
+			r.EventRecorder.Event(&obj, corev1.EventTypeWarning, SnapshotCreateFailure, fmt.Sprintf("snap size too large: %d, used by other snapshots %d, reserved size %d", obj.Spec.Size, reservedUsed, snapReservedBytes))
 			return ctrl.Result{Requeue: true, RequeueAfter: 3 * time.Minute}, nil
 		}
 	}
 
 	// bind snapshot to Node. update snapshot target id
 	if obj.Spec.OriginVolTargetNodeID == "" {
-		log.Info("update origin vol node id", "nodeID", originVol.Spec.TargetNodeId)
+		log.Info("update origin vol node id", "nodeID", originVol.Spec.TargetNodeId, "isThin", isThin)
 		obj.Spec.OriginVolTargetNodeID = originVol.Spec.TargetNodeId
+		obj.Spec.IsThin = isThin
 		obj.Labels[v1.TargetNodeIdLabelKey] = originVol.Spec.TargetNodeId
 		err = r.Update(context.Background(), &obj)
 		return ctrl.Result{}, err
@@ -219,3 +244,18 @@ func (r *SnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	return ctrl.Result{}, nil
 }
+
+// isOlderSnapshot returns true if a is created before b. Name decides the order of snapshots created in the same second.
+func isOlderSnapshot(a, b *v1.AntstorSnapshot) bool {
+	if a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.Name < b.Name
+	}
+	return a.CreationTimestamp.Before(&b.CreationTimestamp)
+}
+
+// isMultipleSnapshot returns true if the snapshot is created by SnapshotSchedule or SnapshotGroup, which can co-exist with each other
+func isMultipleSnapshot(snap *v1.AntstorSnapshot) bool {
+	_, isScheduled := snap.Labels[v1.SnapshotScheduleLabelKey]
+	_, isGroupMember := snap.Labels[v1.SnapshotGroupLabelKey]
+	return isScheduled || isGroupMember
+}
@@ -0,0 +1,299 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+)
+
+const (
+	// rollbackUnpublishWaitInterval is the interval of checking if the volume is unpublished
+	rollbackUnpublishWaitInterval = 30 * time.Second
+	// rollbackMergeCheckInterval is the interval of checking if the agent finishes merging
+	rollbackMergeCheckInterval = 10 * time.Second
+)
+
+// SnapshotRollbackReconciler rolls back a volume to its snapshot, or all volumes of a SnapshotGroup to their snapshots.
+// It waits until the volumes are not attached to any node, and then sets label obnmvf/merge-start-timestamp on the snapshots.
+// The agent merges the snapshot into the volume, rebuilds the target of the volume and sets label obnmvf/merge-finish-timestamp.
+type SnapshotRollbackReconciler struct {
+	client.Client
+	Log logr.Logger
+	// KubeCli lists VolumeAttachments. VolumeAttachments are not cached by the manager.
+	KubeCli kubernetes.Interface
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SnapshotRollbackReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: 1,
+		}).
+		For(&v1.SnapshotRollback{}).
+		Complete(r)
+}
+
+func (r *SnapshotRollbackReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var (
+		log      = r.Log.WithValues("SnapshotRollback", req.NamespacedName)
+		rollback v1.SnapshotRollback
+		toMerge  []*v1.AntstorSnapshot
+	)
+
+	if err := r.Get(ctx, req.NamespacedName, &rollback); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if rollback.DeletionTimestamp != nil || rollback.IsFinished() {
+		return ctrl.Result{}, nil
+	}
+
+	targets, msg, err := r.getTargets(ctx, &rollback)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if msg != "" {
+		return r.fail(ctx, &rollback, msg)
+	}
+
+	if rollback.Status.Phase == v1.RollbackPhaseMerging {
+		return r.checkMerging(ctx, &rollback, targets, log)
+	}
+
+	// validate rollback
+	for _, target := range targets {
+		snapshot, volume := target.snapshot, target.volume
+		switch snapshot.Status.Status {
+		case v1.SnapshotStatusReady:
+			toMerge = append(toMerge, snapshot)
+		case v1.SnapshotStatusMerged:
+			return r.fail(ctx, &rollback, fmt.Sprintf("snapshot %s is already merged", snapshot.Name))
+		case v1.SnapshotStatusMerging:
+			owned, err := r.isMergingByOthers(ctx, &rollback, snapshot)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if owned {
+				return r.pending(ctx, &rollback, fmt.Sprintf("snapshot %s is being merged by another rollback", snapshot.Name), rollbackMergeCheckInterval)
+			}
+			// merging is triggered by this rollback, but status is not updated
+			log.Info("snapshot is already merging", "snapshot", snapshot.Name)
+		default:
+			return r.pending(ctx, &rollback, fmt.Sprintf("snapshot %s is not ready", snapshot.Name), rollbackMergeCheckInterval)
+		}
+		if volume.Status.Status != v1.VolumeStatusReady {
+			return r.pending(ctx, &rollback, fmt.Sprintf("volume %s is not ready", volume.Name), rollbackMergeCheckInterval)
+		}
+	}
+
+	// the volumes must not be written during merging. Check it right before triggering merging.
+	if !rollback.Spec.Force && len(toMerge) > 0 {
+		nodes, err := r.publishedNodes(ctx, targets)
+		if err != nil {
+			log.Error(err, "list VolumeAttachments failed")
+			return ctrl.Result{}, err
+		}
+		if len(nodes) > 0 {
+			log.Info("volume is in use, wait for it to be unpublished", "nodes", nodes)
+			return r.pending(ctx, &rollback, fmt.Sprintf("volume is attached to nodes %v", nodes), rollbackUnpublishWaitInterval)
+		}
+	}
+
+	// trigger merging. The label is removed by the agent after SPDK snapshot is restored.
+	for _, snapshot := range toMerge {
+		if snapshot.Labels == nil {
+			snapshot.Labels = make(map[string]string)
+		}
+		snapshot.Labels[v1.MergeStartTimestampLabelKey] = strconv.FormatInt(time.Now().Unix(), 10)
+		delete(snapshot.Labels, v1.MergeFinishTimestampLabelKey)
+		log.Info("start merging snapshot", "snapshot", snapshot.Name, "volume", snapshot.Spec.OriginVolName)
+		if err = r.Update(ctx, snapshot); err != nil {
+			log.Error(err, "set merge-start-timestamp of snapshot failed")
+			return ctrl.Result{}, err
+		}
+	}
+
+	return r.setMerging(ctx, &rollback)
+}
+
+// rollbackTarget is a volume and the snapshot to roll back to
+type rollbackTarget struct {
+	volume   *v1.AntstorVolume
+	snapshot *v1.AntstorSnapshot
+}
+
+// getTargets returns the volume and snapshot of rollback, or all members of the SnapshotGroup.
+// If the rollback is invalid, msg is the reason.
+func (r *SnapshotRollbackReconciler) getTargets(ctx context.Context, rollback *v1.SnapshotRollback) (targets []rollbackTarget, msg string, err error) {
+	var pairs [][2]string
+	if groupName := rollback.Spec.SnapshotGroupName; groupName != "" {
+		var group v1.SnapshotGroup
+		if err = r.Get(ctx, client.ObjectKey{Namespace: rollback.Namespace, Name: groupName}, &group); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, fmt.Sprintf("snapshot group %s not found", groupName), nil
+			}
+			return
+		}
+		if group.Status.Phase != v1.SnapshotGroupPhaseReady {
+			return nil, fmt.Sprintf("snapshot group %s is not ready", groupName), nil
+		}
+		for _, member := range group.Status.Members {
+			pairs = append(pairs, [2]string{member.VolumeName, member.SnapshotName})
+		}
+	} else {
+		if rollback.Spec.VolumeName == "" || rollback.Spec.SnapshotName == "" {
+			return nil, "volumeName and snapshotName are required if snapshotGroupName is empty", nil
+		}
+		pairs = append(pairs, [2]string{rollback.Spec.VolumeName, rollback.Spec.SnapshotName})
+	}
+
+	for _, pair := range pairs {
+		var target = rollbackTarget{volume: &v1.AntstorVolume{}, snapshot: &v1.AntstorSnapshot{}}
+		if err = r.Get(ctx, client.ObjectKey{Namespace: rollback.Namespace, Name: pair[1]}, target.snapshot); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, fmt.Sprintf("snapshot %s not found", pair[1]), nil
+			}
+			return
+		}
+		if err = r.Get(ctx, client.ObjectKey{Namespace: rollback.Namespace, Name: pair[0]}, target.volume); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, fmt.Sprintf("volume %s not found", pair[0]), nil
+			}
+			return
+		}
+		if target.snapshot.Spec.OriginVolName != target.volume.Name || target.snapshot.Spec.OriginVolNamespace != target.volume.Namespace {
+			return nil, fmt.Sprintf("snapshot %s is not a snapshot of volume %s", pair[1], pair[0]), nil
+		}
+		targets = append(targets, target)
+	}
+	return
+}
+
+func (r *SnapshotRollbackReconciler) setMerging(ctx context.Context, rollback *v1.SnapshotRollback) (ctrl.Result, error) {
+	var now = metav1.Now()
+	rollback.Status.Phase = v1.RollbackPhaseMerging
+	rollback.Status.Message = ""
+	rollback.Status.StartTime = &now
+	if err := r.Status().Update(ctx, rollback); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: rollbackMergeCheckInterval}, nil
+}
+
+// isMergingByOthers returns true if another rollback of the snapshot is Merging
+func (r *SnapshotRollbackReconciler) isMergingByOthers(ctx context.Context, rollback *v1.SnapshotRollback, snapshot *v1.AntstorSnapshot) (bool, error) {
+	var list v1.SnapshotRollbackList
+	if err := r.List(ctx, &list, client.InNamespace(rollback.Namespace)); err != nil {
+		return false, err
+	}
+	for _, item := range list.Items {
+		if item.Name == rollback.Name || item.Status.Phase != v1.RollbackPhaseMerging {
+			continue
+		}
+		if groupName := item.Spec.SnapshotGroupName; groupName != "" {
+			if snapshot.Labels[v1.SnapshotGroupLabelKey] == groupName {
+				return true, nil
+			}
+		} else if item.Spec.SnapshotName == snapshot.Name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkMerging waits for label obnmvf/merge-finish-timestamp of all snapshots
+func (r *SnapshotRollbackReconciler) checkMerging(ctx context.Context, rollback *v1.SnapshotRollback, targets []rollbackTarget, log logr.Logger) (ctrl.Result, error) {
+	var completion metav1.Time
+	for _, target := range targets {
+		snapshot := target.snapshot
+		val, has := snapshot.Labels[v1.MergeFinishTimestampLabelKey]
+		if !has {
+			log.Info("snapshot is merging", "snapshot", snapshot.Name)
+			return ctrl.Result{RequeueAfter: rollbackMergeCheckInterval}, nil
+		}
+		var finish = metav1.Now()
+		if sec, err := strconv.ParseInt(val, 10, 64); err == nil {
+			finish = metav1.NewTime(time.Unix(sec, 0))
+		}
+		if completion.Before(&finish) {
+			completion = finish
+		}
+	}
+
+	log.Info("snapshots are merged, rollback succeeded")
+	rollback.Status.Phase = v1.RollbackPhaseSucceeded
+	rollback.Status.Message = ""
+	rollback.Status.CompletionTime = &completion
+	return ctrl.Result{}, r.Status().Update(ctx, rollback)
+}
+
+// publishedNodes returns the nodes which the PVs of targets are attached to, by VolumeAttachments.
+// A VolumeAttachment which is not attached yet is counted too, because the volume is being attached.
+func (r *SnapshotRollbackReconciler) publishedNodes(ctx context.Context, targets []rollbackTarget) (nodes []string, err error) {
+	var (
+		pvNames = make(map[string]bool, len(targets))
+		nodeSet = make(map[string]bool)
+		list    *storagev1.VolumeAttachmentList
+	)
+	for _, target := range targets {
+		pvName := target.volume.Labels[v1.VolumePVNameLabelKey]
+		if pvName == "" {
+			pvName = target.volume.Name
+		}
+		pvNames[pvName] = true
+	}
+
+	list, err = r.KubeCli.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return
+	}
+	for _, va := range list.Items {
+		pvName := va.Spec.Source.PersistentVolumeName
+		if pvName == nil || !pvNames[*pvName] {
+			continue
+		}
+		// detached and being deleted
+		if !va.Status.Attached && va.DeletionTimestamp != nil {
+			continue
+		}
+		nodeSet[va.Spec.NodeName] = true
+	}
+	for node := range nodeSet {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return
+}
+
+func (r *SnapshotRollbackReconciler) pending(ctx context.Context, rollback *v1.SnapshotRollback, msg string, requeue time.Duration) (ctrl.Result, error) {
+	if rollback.Status.Phase != v1.RollbackPhasePending || rollback.Status.Message != msg {
+		rollback.Status.Phase = v1.RollbackPhasePending
+		rollback.Status.Message = msg
+		if err := r.Status().Update(ctx, rollback); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: requeue}, nil
+}
+
+func (r *SnapshotRollbackReconciler) fail(ctx context.Context, rollback *v1.SnapshotRollback, msg string) (ctrl.Result, error) {
+	r.Log.Info("rollback failed", "rollback", rollback.Namespace+"/"+rollback.Name, "reason", msg)
+	var now = metav1.Now()
+	rollback.Status.Phase = v1.RollbackPhaseFailed
+	rollback.Status.Message = msg
+	rollback.Status.CompletionTime = &now
+	return ctrl.Result{}, r.Status().Update(ctx, rollback)
+}
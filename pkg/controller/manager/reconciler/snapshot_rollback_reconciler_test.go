@@ -0,0 +1,223 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+)
+
+func TestSnapshotRollbackReconcile(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+
+	vol := &v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: v1.DefaultNamespace,
+			Name:      "vol-1",
+			Labels: map[string]string{
+				v1.VolumeContextKeyPvcName: "data",
+				v1.VolumeContextKeyPvcNS:   "app",
+				v1.VolumePVNameLabelKey:    "pv-1",
+			},
+		},
+		Spec: v1.AntstorVolumeSpec{
+			Type:         v1.VolumeTypeKernelLVol,
+			TargetNodeId: "node-1",
+		},
+		Status: v1.AntstorVolumeStatus{Status: v1.VolumeStatusReady},
+	}
+	snap := &v1.AntstorSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: v1.DefaultNamespace,
+			Name:      "snap-1",
+			Labels:    map[string]string{v1.OriginVolumeNameLabelKey: "vol-1"},
+		},
+		Spec: v1.AntstorSnapshotSpec{
+			OriginVolName:      "vol-1",
+			OriginVolNamespace: v1.DefaultNamespace,
+		},
+		Status: v1.AntstorSnapshotStatus{Status: v1.SnapshotStatusReady},
+	}
+	other := &v1.AntstorSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "snap-2"},
+		Spec: v1.AntstorSnapshotSpec{
+			OriginVolName:      "vol-2",
+			OriginVolNamespace: v1.DefaultNamespace,
+		},
+		Status: v1.AntstorSnapshotStatus{Status: v1.SnapshotStatusReady},
+	}
+	newRollback := func(name, snapName string) *v1.SnapshotRollback {
+		return &v1.SnapshotRollback{
+			ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: name},
+			Spec: v1.SnapshotRollbackSpec{
+				VolumeName:   "vol-1",
+				SnapshotName: snapName,
+			},
+		}
+	}
+	newAttachment := func(name, pvName, nodeName string) *storagev1.VolumeAttachment {
+		return &storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: "antstor.csi.alipay.com",
+				NodeName: nodeName,
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName},
+			},
+			Status: storagev1.VolumeAttachmentStatus{Attached: true},
+		}
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(vol, snap, other, newRollback("rb-1", "snap-1"), newRollback("rb-2", "snap-2")).Build()
+	kubeCli := kubefake.NewSimpleClientset(newAttachment("csi-1", "pv-1", "node-2"), newAttachment("csi-2", "pv-2", "node-3"))
+	r := &SnapshotRollbackReconciler{
+		Client:  cli,
+		Log:     zap.New(),
+		KubeCli: kubeCli,
+	}
+	ctx := context.Background()
+
+	// snapshot of another volume
+	key := types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "rb-2"}
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	var got v1.SnapshotRollback
+	assert.NoError(t, cli.Get(ctx, key, &got))
+	assert.Equal(t, v1.RollbackPhaseFailed, got.Status.Phase)
+
+	// PV of volume is attached to node-2
+	key = types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "rb-1"}
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.Equal(t, rollbackUnpublishWaitInterval, result.RequeueAfter)
+	assert.NoError(t, cli.Get(ctx, key, &got))
+	assert.Equal(t, v1.RollbackPhasePending, got.Status.Phase)
+	assert.Equal(t, "volume is attached to nodes [node-2]", got.Status.Message)
+
+	// VolumeAttachment is deleted, start merging
+	assert.NoError(t, kubeCli.StorageV1().VolumeAttachments().Delete(ctx, "csi-1", metav1.DeleteOptions{}))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.NoError(t, cli.Get(ctx, key, &got))
+	assert.Equal(t, v1.RollbackPhaseMerging, got.Status.Phase)
+	assert.NotNil(t, got.Status.StartTime)
+	var gotSnap v1.AntstorSnapshot
+	assert.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "snap-1"}, &gotSnap))
+	assert.NotEmpty(t, gotSnap.Labels[v1.MergeStartTimestampLabelKey])
+
+	// agent has not finished merging
+	result, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.Equal(t, rollbackMergeCheckInterval, result.RequeueAfter)
+
+	// agent finished merging
+	gotSnap.Labels[v1.MergeFinishTimestampLabelKey] = "1700000000"
+	assert.NoError(t, cli.Update(ctx, &gotSnap))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.NoError(t, cli.Get(ctx, key, &got))
+	assert.Equal(t, v1.RollbackPhaseSucceeded, got.Status.Phase)
+	assert.Equal(t, int64(1700000000), got.Status.CompletionTime.Unix())
+}
+
+func TestSnapshotGroupRollbackReconcile(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+
+	var objs []client.Object
+	for _, name := range []string{"vol-1", "vol-2"} {
+		objs = append(objs, &v1.AntstorVolume{
+			ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: name},
+			Spec:       v1.AntstorVolumeSpec{Type: v1.VolumeTypeKernelLVol},
+			Status:     v1.AntstorVolumeStatus{Status: v1.VolumeStatusReady},
+		}, &v1.AntstorSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: v1.DefaultNamespace,
+				Name:      "grp-" + name,
+				Labels:    map[string]string{v1.SnapshotGroupLabelKey: "grp"},
+			},
+			Spec: v1.AntstorSnapshotSpec{
+				OriginVolName:      name,
+				OriginVolNamespace: v1.DefaultNamespace,
+			},
+			Status: v1.AntstorSnapshotStatus{Status: v1.SnapshotStatusReady},
+		})
+	}
+	group := &v1.SnapshotGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "grp"},
+		Status: v1.SnapshotGroupStatus{
+			Phase: v1.SnapshotGroupPhaseReady,
+			Members: []v1.SnapshotGroupMember{
+				{VolumeName: "vol-1", SnapshotName: "grp-vol-1"},
+				{VolumeName: "vol-2", SnapshotName: "grp-vol-2"},
+			},
+		},
+	}
+	newRollback := func(name, groupName string) *v1.SnapshotRollback {
+		return &v1.SnapshotRollback{
+			ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: name},
+			Spec:       v1.SnapshotRollbackSpec{SnapshotGroupName: groupName},
+		}
+	}
+	objs = append(objs, group, newRollback("rb-grp", "grp"), newRollback("rb-missing", "none"), newRollback("rb-single", ""))
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	r := &SnapshotRollbackReconciler{
+		Client:  cli,
+		Log:     zap.New(),
+		KubeCli: kubefake.NewSimpleClientset(),
+	}
+	ctx := context.Background()
+	reconcile := func(name string) (ctrl.Result, *v1.SnapshotRollback) {
+		key := types.NamespacedName{Namespace: v1.DefaultNamespace, Name: name}
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		assert.NoError(t, err)
+		var got v1.SnapshotRollback
+		assert.NoError(t, cli.Get(ctx, key, &got))
+		return result, &got
+	}
+	getSnap := func(name string) *v1.AntstorSnapshot {
+		var snap v1.AntstorSnapshot
+		assert.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: v1.DefaultNamespace, Name: name}, &snap))
+		return &snap
+	}
+
+	// group not found, or neither group nor snapshot is specified
+	_, got := reconcile("rb-missing")
+	assert.Equal(t, v1.RollbackPhaseFailed, got.Status.Phase)
+	assert.Equal(t, "snapshot group none not found", got.Status.Message)
+	_, got = reconcile("rb-single")
+	assert.Equal(t, v1.RollbackPhaseFailed, got.Status.Phase)
+
+	// start merging all members
+	_, got = reconcile("rb-grp")
+	assert.Equal(t, v1.RollbackPhaseMerging, got.Status.Phase)
+	assert.NotEmpty(t, getSnap("grp-vol-1").Labels[v1.MergeStartTimestampLabelKey])
+	assert.NotEmpty(t, getSnap("grp-vol-2").Labels[v1.MergeStartTimestampLabelKey])
+
+	// wait for all members
+	for i, finish := range []string{"1700000000", "1700000100"} {
+		snap := getSnap(group.Status.Members[i].SnapshotName)
+		snap.Labels[v1.MergeFinishTimestampLabelKey] = finish
+		assert.NoError(t, cli.Update(ctx, snap))
+		if i == 0 {
+			result, _ := reconcile("rb-grp")
+			assert.Equal(t, rollbackMergeCheckInterval, result.RequeueAfter)
+		}
+	}
+	_, got = reconcile("rb-grp")
+	assert.Equal(t, v1.RollbackPhaseSucceeded, got.Status.Phase)
+	assert.Equal(t, int64(1700000100), got.Status.CompletionTime.Unix())
+}
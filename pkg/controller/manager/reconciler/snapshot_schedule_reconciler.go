@@ -0,0 +1,342 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	uuid "github.com/satori/go.uuid"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/util"
+	"lite.io/liteio/pkg/util/cron"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+const (
+	// scheduleResyncInterval is the max interval of reconciling a SnapshotSchedule, so that expired snapshots are deleted in time
+	scheduleResyncInterval = 10 * time.Minute
+	// maxMissedSchedules is the max number of missed schedules to look back. Only the latest missed schedule is run.
+	maxMissedSchedules = 1000
+)
+
+// SnapshotScheduleReconciler creates AntstorSnapshots of the selected volumes by cron, and deletes old snapshots by retention.
+// The snapshots of each volume must fit in the snapshot reserved space of the volume, so LVM COW snapshots never exhaust the VG.
+type SnapshotScheduleReconciler struct {
+	client.Client
+	Log logr.Logger
+	// Now returns current time. It is replaced in tests.
+	Now func() time.Time
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SnapshotScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: 1,
+		}).
+		For(&v1.SnapshotSchedule{}).
+		Complete(r)
+}
+
+func (r *SnapshotScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var (
+		log   = r.Log.WithValues("SnapshotSchedule", req.NamespacedName)
+		sched v1.SnapshotSchedule
+		vols  v1.AntstorVolumeList
+		snaps v1.AntstorSnapshotList
+		now   = r.now()
+	)
+
+	if err := r.Get(ctx, req.NamespacedName, &sched); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// snapshots are kept after SnapshotSchedule is deleted
+	if sched.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	cronSched, err := cron.Parse(sched.Spec.Schedule)
+	if err != nil {
+		return ctrl.Result{}, r.recordError(ctx, &sched, err.Error())
+	}
+	if sched.Spec.Selector == nil {
+		return ctrl.Result{}, r.recordError(ctx, &sched, "selector is empty")
+	}
+	selector, err := metav1.LabelSelectorAsSelector(sched.Spec.Selector)
+	if err != nil {
+		return ctrl.Result{}, r.recordError(ctx, &sched, fmt.Sprintf("invalid selector: %s", err))
+	}
+
+	if err = r.List(ctx, &vols, client.InNamespace(sched.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err = r.List(ctx, &snaps, client.InNamespace(sched.Namespace), client.MatchingLabels{v1.SnapshotScheduleLabelKey: sched.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// delete snapshots by retention. Deleted snapshots may still be in the cache, so they are recorded in pruned.
+	var (
+		snapsOfVol = make(map[string][]*v1.AntstorSnapshot)
+		pruned     = misc.NewEmptySet()
+	)
+	for i := range snaps.Items {
+		snap := &snaps.Items[i]
+		snapsOfVol[snap.Spec.OriginVolName] = append(snapsOfVol[snap.Spec.OriginVolName], snap)
+	}
+	for volName, items := range snapsOfVol {
+		kept, err := r.prune(ctx, &sched, items, sched.Spec.Retention.KeepLast, now, pruned, log)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		snapsOfVol[volName] = kept
+	}
+
+	// decide the time to run
+	var last = sched.CreationTimestamp.Time
+	if sched.Status.LastScheduleTime != nil {
+		last = sched.Status.LastScheduleTime.Time
+	}
+	next := cronSched.Next(last.UTC())
+	if next.IsZero() {
+		return ctrl.Result{}, r.recordError(ctx, &sched, fmt.Sprintf("no time matches schedule %q", sched.Spec.Schedule))
+	}
+	if now.Before(next) || sched.Spec.Suspend {
+		return ctrl.Result{RequeueAfter: requeueBefore(next.Sub(now))}, nil
+	}
+	// if schedules are missed, e.g. controller is down, only run the latest one
+	runTime := next
+	for i := 0; i < maxMissedSchedules; i++ {
+		n := cronSched.Next(runTime)
+		if n.IsZero() || n.After(now) {
+			break
+		}
+		runTime = n
+	}
+	log.Info("run schedule", "time", runTime)
+
+	var failures []string
+	for i := range vols.Items {
+		vol := &vols.Items[i]
+		if vol.DeletionTimestamp != nil {
+			continue
+		}
+		if vol.Status.Status != v1.VolumeStatusReady {
+			failures = append(failures, fmt.Sprintf("%s: volume is not ready", vol.Name))
+			continue
+		}
+		// make room for the new snapshot
+		if keep := sched.Spec.Retention.KeepLast; keep > 0 {
+			if _, err = r.prune(ctx, &sched, snapsOfVol[vol.Name], keep-1, now, pruned, log); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if err = r.createSnapshot(ctx, &sched, vol, runTime, pruned, log); err != nil {
+			log.Error(err, "create scheduled snapshot failed", "volume", vol.Name)
+			failures = append(failures, fmt.Sprintf("%s: %s", vol.Name, err))
+		}
+	}
+
+	var runAt = metav1.NewTime(runTime)
+	if err = r.recordResult(ctx, &sched, &runAt, failures); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeueBefore(cronSched.Next(runTime).Sub(now))}, nil
+}
+
+// prune deletes snapshots older than MaxAge, and the oldest snapshots to keep at most keep snapshots. keep <= 0 means no limit.
+// The remaining snapshots are returned, and names of the deleted snapshots are added to pruned.
+func (r *SnapshotScheduleReconciler) prune(ctx context.Context, sched *v1.SnapshotSchedule, snaps []*v1.AntstorSnapshot, keep int,
+	now time.Time, pruned misc.Set, log logr.Logger) (kept []*v1.AntstorSnapshot, err error) {
+	// newest first
+	sort.Slice(snaps, func(i, j int) bool {
+		return snaps[j].CreationTimestamp.Before(&snaps[i].CreationTimestamp)
+	})
+
+	var maxAge = sched.Spec.Retention.MaxAge
+	for _, snap := range snaps {
+		if snap.DeletionTimestamp != nil {
+			continue
+		}
+		var expired = maxAge != nil && now.Sub(snap.CreationTimestamp.Time) > maxAge.Duration
+		var exceeded = keep > 0 && len(kept) >= keep
+		// snapshot being merged is deleted after rollback
+		if (!expired && !exceeded) || snap.Status.Status == v1.SnapshotStatusMerging {
+			kept = append(kept, snap)
+			continue
+		}
+
+		log.Info("delete snapshot by retention", "snapshot", snap.Name, "expired", expired)
+		if err = r.Delete(ctx, snap); err != nil && !errors.IsNotFound(err) {
+			return
+		}
+		err = nil
+		pruned.Add(snap.Name)
+	}
+	return
+}
+
+// createSnapshot creates the snapshot of volume for runTime. The size is validated against snapshot reserved space of volume and AntstorQuota.
+// Snapshots in pruned are deleted, they do not use the snapshot reserved space.
+func (r *SnapshotScheduleReconciler) createSnapshot(ctx context.Context, sched *v1.SnapshotSchedule, vol *v1.AntstorVolume, runTime time.Time,
+	pruned misc.Set, log logr.Logger) (err error) {
+	var name = fmt.Sprintf("%s-%s-%d", sched.Name, vol.Name, runTime.Unix())
+	var existing v1.AntstorSnapshot
+	err = r.Get(ctx, client.ObjectKey{Namespace: vol.Namespace, Name: name}, &existing)
+	if err == nil {
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return
+	}
+
+	// thin snapshots share blocks with the thin volume, they need no reserved space
+	var size = int64(vol.Spec.SizeByte)
+	if !vol.IsThinLVol() {
+		if size, err = r.reservedSnapshotSize(ctx, sched, vol, pruned); err != nil {
+			return
+		}
+	}
+
+	snap := &v1.AntstorSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: vol.Namespace,
+			Name:      name,
+			Labels: map[string]string{
+				v1.OriginVolumeNameLabelKey:      vol.Name,
+				v1.OriginVolumeNamespaceLabelKey: vol.Namespace,
+				v1.SnapshotScheduleLabelKey:      sched.Name,
+			},
+		},
+		Spec: v1.AntstorSnapshotSpec{
+			Uuid:               uuid.NewV4().String(),
+			VolType:            vol.Spec.Type,
+			Size:               size,
+			OriginVolName:      vol.Name,
+			OriginVolNamespace: vol.Namespace,
+			IsThin:             vol.IsThinLVol(),
+		},
+	}
+	snap.Labels[v1.SnapUuidLabelKey] = snap.Spec.Uuid
+	if pvcNS := vol.QuotaNamespace(); pvcNS != "" {
+		snap.Labels[v1.VolumeContextKeyPvcNS] = pvcNS
+		if err = checkSnapshotQuota(ctx, r.Client, pvcNS, snap); err != nil {
+			return
+		}
+	}
+
+	log.Info("create scheduled snapshot", "snapshot", name, "size", size)
+	return r.Create(ctx, snap)
+}
+
+// reservedSnapshotSize decides the size of the new snapshot, and checks it fits in the snapshot reserved space of volume
+func (r *SnapshotScheduleReconciler) reservedSnapshotSize(ctx context.Context, sched *v1.SnapshotSchedule, vol *v1.AntstorVolume, pruned misc.Set) (size int64, err error) {
+	reserved, err := strconv.ParseInt(vol.Annotations[v1.SnapshotReservedSpaceAnnotationKey], 10, 64)
+	if err != nil || reserved <= 0 {
+		return 0, fmt.Errorf("volume has no snapshot reserved space")
+	}
+
+	size = reserved
+	if sched.Spec.SnapshotSize != nil {
+		size = sched.Spec.SnapshotSize.Value()
+	} else if sched.Spec.Retention.KeepLast > 0 {
+		size = reserved / int64(sched.Spec.Retention.KeepLast)
+	}
+	size = size / util.FourMiB * util.FourMiB
+	if size < util.FourMiB {
+		return 0, fmt.Errorf("snapshot size %d is too small, at least 4MiB", size)
+	}
+
+	// all snapshots of the volume share the snapshot reserved space.
+	// Snapshots being deleted or just pruned are not counted, SnapshotReconciler creates the new snapshot after they are deleted.
+	var snaps v1.AntstorSnapshotList
+	if err = r.List(ctx, &snaps, client.InNamespace(vol.Namespace), client.MatchingLabels{v1.OriginVolumeNameLabelKey: vol.Name}); err != nil {
+		return
+	}
+	var used int64
+	for _, item := range snaps.Items {
+		if _, has := item.Labels[v1.SnapshotCopyForLabelKey]; has || item.Status.Status == v1.SnapshotStatusMerged || item.DeletionTimestamp != nil ||
+			pruned.Contains(item.Name) {
+			continue
+		}
+		used += item.Spec.Size
+	}
+	if used+size > reserved {
+		return 0, fmt.Errorf("not enough snapshot reserved space, size %d, used %d, reserved %d", size, used, reserved)
+	}
+	return
+}
+
+// checkSnapshotQuota validates the snapshot against AntstorQuotas of the PVC namespace, like CSI CreateSnapshot
+func checkSnapshotQuota(ctx context.Context, r client.Reader, ns string, snap *v1.AntstorSnapshot) (err error) {
+	var (
+		quotas v1.AntstorQuotaList
+		vols   v1.AntstorVolumeList
+		snaps  v1.AntstorSnapshotList
+	)
+	if err = r.List(ctx, &quotas, client.InNamespace(ns)); err != nil || len(quotas.Items) == 0 {
+		return
+	}
+	if err = r.List(ctx, &vols); err != nil {
+		return
+	}
+	if err = r.List(ctx, &snaps); err != nil {
+		return
+	}
+
+	used := v1.QuotaUsageOfNamespace(ns, vols.Items, snaps.Items)
+	for _, quota := range quotas.Items {
+		if exceeded := quota.ExceededResources(used, snap.QuotaUsage()); len(exceeded) > 0 {
+			return fmt.Errorf("exceeded quota %s/%s of %v", ns, quota.Name, exceeded)
+		}
+	}
+	return
+}
+
+// recordError records an invalid SnapshotSchedule in status. Status is not updated if the error is already recorded.
+func (r *SnapshotScheduleReconciler) recordError(ctx context.Context, sched *v1.SnapshotSchedule, msg string) error {
+	if sched.Status.LastFailureMessage == msg {
+		return nil
+	}
+	var now = metav1.NewTime(r.now())
+	sched.Status.LastFailureTime = &now
+	sched.Status.LastFailureMessage = msg
+	return r.Status().Update(ctx, sched)
+}
+
+// recordResult updates status by the result of schedule run at runTime
+func (r *SnapshotScheduleReconciler) recordResult(ctx context.Context, sched *v1.SnapshotSchedule, runTime *metav1.Time, failures []string) error {
+	var now = metav1.NewTime(r.now())
+	sched.Status.LastScheduleTime = runTime
+	if len(failures) > 0 {
+		sched.Status.LastFailureTime = &now
+		sched.Status.LastFailureMessage = strings.Join(failures, "; ")
+	} else {
+		sched.Status.LastSuccessfulTime = &now
+	}
+	return r.Status().Update(ctx, sched)
+}
+
+func (r *SnapshotScheduleReconciler) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
+
+// requeueBefore returns d, capped by scheduleResyncInterval
+func requeueBefore(d time.Duration) time.Duration {
+	if d <= 0 || d > scheduleResyncInterval {
+		return scheduleResyncInterval
+	}
+	return d
+}
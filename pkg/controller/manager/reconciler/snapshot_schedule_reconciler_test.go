@@ -0,0 +1,289 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+)
+
+func TestSnapshotScheduleReconcile(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+
+	day := func(d, h, m int) time.Time {
+		return time.Date(2024, 1, d, h, m, 0, 0, time.UTC)
+	}
+	newVol := func(name, app, reserved string) *v1.AntstorVolume {
+		vol := &v1.AntstorVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   v1.DefaultNamespace,
+				Name:        name,
+				Labels:      map[string]string{"app": app},
+				Annotations: map[string]string{},
+			},
+			Spec:   v1.AntstorVolumeSpec{Type: v1.VolumeTypeKernelLVol, SizeByte: 10 << 30},
+			Status: v1.AntstorVolumeStatus{Status: v1.VolumeStatusReady},
+		}
+		if reserved != "" {
+			vol.Annotations[v1.SnapshotReservedSpaceAnnotationKey] = reserved
+		}
+		return vol
+	}
+	newSnap := func(name string, created time.Time) *v1.AntstorSnapshot {
+		return &v1.AntstorSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         v1.DefaultNamespace,
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(created),
+				Labels: map[string]string{
+					v1.OriginVolumeNameLabelKey: "vol-1",
+					v1.SnapshotScheduleLabelKey: "daily",
+				},
+			},
+			Spec:   v1.AntstorSnapshotSpec{OriginVolName: "vol-1", Size: 4 << 30},
+			Status: v1.AntstorSnapshotStatus{Status: v1.SnapshotStatusReady},
+		}
+	}
+	sched := &v1.SnapshotSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         v1.DefaultNamespace,
+			Name:              "daily",
+			CreationTimestamp: metav1.NewTime(day(1, 0, 0)),
+		},
+		Spec: v1.SnapshotScheduleSpec{
+			Schedule:  "0 2 * * *",
+			Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			Retention: v1.SnapshotRetention{KeepLast: 2},
+		},
+	}
+	invalid := &v1.SnapshotSchedule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "invalid"},
+		Spec: v1.SnapshotScheduleSpec{
+			Schedule: "0 25 * * *",
+			Selector: &metav1.LabelSelector{},
+		},
+	}
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		sched, invalid,
+		newVol("vol-1", "db", "8589934592"),
+		newVol("vol-2", "db", ""),
+		newVol("vol-3", "web", "8589934592"),
+		newSnap("daily-old", day(1, 0, 0).Add(-48*time.Hour)),
+		newSnap("daily-mid", day(1, 0, 0).Add(-24*time.Hour)),
+	).Build()
+	var now time.Time
+	r := &SnapshotScheduleReconciler{
+		Client: cli,
+		Log:    zap.New(),
+		Now:    func() time.Time { return now },
+	}
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "daily"}
+	listSnaps := func() (names []string) {
+		var list v1.AntstorSnapshotList
+		assert.NoError(t, cli.List(ctx, &list, client.MatchingLabels{v1.SnapshotScheduleLabelKey: "daily"}))
+		for _, item := range list.Items {
+			names = append(names, item.Name)
+		}
+		return
+	}
+
+	// not the time to run
+	now = day(1, 1, 0)
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.Equal(t, scheduleResyncInterval, result.RequeueAfter)
+	assert.ElementsMatch(t, []string{"daily-old", "daily-mid"}, listSnaps())
+
+	// run at 02:00. The oldest snapshot is deleted to keep 2 snapshots.
+	now = day(1, 2, 0).Add(30 * time.Second)
+	result, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.Equal(t, scheduleResyncInterval, result.RequeueAfter)
+	newName := "daily-vol-1-1704074400"
+	assert.ElementsMatch(t, []string{"daily-mid", newName}, listSnaps())
+
+	var snap v1.AntstorSnapshot
+	assert.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: v1.DefaultNamespace, Name: newName}, &snap))
+	assert.Equal(t, int64(4<<30), snap.Spec.Size)
+	assert.Equal(t, "vol-1", snap.Labels[v1.OriginVolumeNameLabelKey])
+	assert.NotEmpty(t, snap.Labels[v1.SnapUuidLabelKey])
+	// fake client does not set creationTimestamp
+	snap.CreationTimestamp = metav1.NewTime(now)
+	assert.NoError(t, cli.Update(ctx, &snap))
+
+	var got v1.SnapshotSchedule
+	assert.NoError(t, cli.Get(ctx, key, &got))
+	assert.True(t, got.Status.LastScheduleTime.Time.Equal(day(1, 2, 0)))
+	assert.NotNil(t, got.Status.LastFailureTime)
+	assert.Contains(t, got.Status.LastFailureMessage, "vol-2: volume has no snapshot reserved space")
+	assert.Nil(t, got.Status.LastSuccessfulTime)
+
+	// already run
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.Len(t, listSnaps(), 2)
+
+	// missed schedules, only the latest one is run
+	now = day(4, 3, 0)
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{newName, "daily-vol-1-1704333600"}, listSnaps())
+	assert.NoError(t, cli.Get(ctx, key, &got))
+	assert.True(t, got.Status.LastScheduleTime.Time.Equal(day(4, 2, 0)))
+
+	// expired by maxAge
+	got.Spec.Retention.MaxAge = &metav1.Duration{Duration: time.Hour}
+	got.Spec.Suspend = true
+	assert.NoError(t, cli.Update(ctx, &got))
+	err = cli.Get(ctx, types.NamespacedName{Namespace: v1.DefaultNamespace, Name: newName}, &snap)
+	assert.NoError(t, err)
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	err = cli.Get(ctx, types.NamespacedName{Namespace: v1.DefaultNamespace, Name: newName}, &snap)
+	assert.True(t, errors.IsNotFound(err))
+
+	// invalid schedule is recorded in status
+	key = types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "invalid"}
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.NoError(t, cli.Get(ctx, key, &got))
+	assert.Contains(t, got.Status.LastFailureMessage, "invalid hour")
+}
+
+func TestSnapshotScheduleThinVolume(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+
+	// thin volume has no snapshot reserved space
+	vol := &v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: v1.DefaultNamespace,
+			Name:      "thin-vol",
+			Labels:    map[string]string{"app": "db"},
+		},
+		Spec:   v1.AntstorVolumeSpec{Type: v1.VolumeTypeKernelLVol, SizeByte: 10 << 30, IsThin: true},
+		Status: v1.AntstorVolumeStatus{Status: v1.VolumeStatusReady},
+	}
+	sched := &v1.SnapshotSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         v1.DefaultNamespace,
+			Name:              "hourly",
+			CreationTimestamp: metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		Spec: v1.SnapshotScheduleSpec{
+			Schedule: "0 * * * *",
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vol, sched).Build()
+	r := &SnapshotScheduleReconciler{
+		Client: cli,
+		Log:    zap.New(),
+		Now:    func() time.Time { return time.Date(2024, 1, 1, 1, 0, 10, 0, time.UTC) },
+	}
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "hourly"}})
+	assert.NoError(t, err)
+
+	var snap v1.AntstorSnapshot
+	assert.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "hourly-thin-vol-1704070800"}, &snap))
+	assert.True(t, snap.Spec.IsThin)
+	assert.Equal(t, int64(10<<30), snap.Spec.Size)
+	assert.Empty(t, snap.QuotaUsage()[v1.QuotaResourceSnapshotStorage])
+}
+
+// cachedDeleteClient does not remove deleted objects, like the cache of manager before the delete event arrives
+type cachedDeleteClient struct {
+	client.Client
+	deleted []string
+}
+
+func (c *cachedDeleteClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.deleted = append(c.deleted, obj.GetName())
+	return nil
+}
+
+func TestSnapshotSchedulePruneBeforeCreate(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+
+	// snapshot reserved space is full of keepLast snapshots
+	vol := &v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   v1.DefaultNamespace,
+			Name:        "vol-1",
+			Labels:      map[string]string{"app": "db"},
+			Annotations: map[string]string{v1.SnapshotReservedSpaceAnnotationKey: "8589934592"},
+		},
+		Spec:   v1.AntstorVolumeSpec{Type: v1.VolumeTypeKernelLVol, SizeByte: 10 << 30},
+		Status: v1.AntstorVolumeStatus{Status: v1.VolumeStatusReady},
+	}
+	newSnap := func(name string, created time.Time) *v1.AntstorSnapshot {
+		return &v1.AntstorSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         v1.DefaultNamespace,
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(created),
+				Labels: map[string]string{
+					v1.OriginVolumeNameLabelKey: "vol-1",
+					v1.SnapshotScheduleLabelKey: "hourly",
+				},
+			},
+			Spec:   v1.AntstorSnapshotSpec{OriginVolName: "vol-1", Size: 4 << 30},
+			Status: v1.AntstorSnapshotStatus{Status: v1.SnapshotStatusReady},
+		}
+	}
+	var created = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sched := &v1.SnapshotSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         v1.DefaultNamespace,
+			Name:              "hourly",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: v1.SnapshotScheduleSpec{
+			Schedule:  "0 * * * *",
+			Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			Retention: v1.SnapshotRetention{KeepLast: 2},
+		},
+	}
+	cli := &cachedDeleteClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		vol, sched,
+		newSnap("hourly-old", created.Add(-2*time.Hour)),
+		newSnap("hourly-mid", created.Add(-time.Hour)),
+	).Build()}
+	r := &SnapshotScheduleReconciler{
+		Client: cli,
+		Log:    zap.New(),
+		Now:    func() time.Time { return created.Add(time.Hour + 10*time.Second) },
+	}
+	ctx := context.Background()
+
+	key := types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "hourly"}
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+
+	// the oldest snapshot is pruned, and its space is used by the new snapshot
+	assert.Equal(t, []string{"hourly-old"}, cli.deleted)
+	var snap v1.AntstorSnapshot
+	assert.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "hourly-vol-1-1704070800"}, &snap))
+	assert.Equal(t, int64(4<<30), snap.Spec.Size)
+	var got v1.SnapshotSchedule
+	assert.NoError(t, cli.Get(ctx, key, &got))
+	assert.Empty(t, got.Status.LastFailureMessage)
+	assert.NotNil(t, got.Status.LastSuccessfulTime)
+}
@@ -0,0 +1,126 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	sched "lite.io/liteio/pkg/controller/manager/scheduler"
+	"lite.io/liteio/pkg/controller/manager/state"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+const (
+	// EventReasonPreempt is recorded on the volume which preempts others
+	EventReasonPreempt = "Preempt"
+	// EventReasonPreempted is recorded on the volume which is preempted
+	EventReasonPreempted = "Preempted"
+)
+
+// preempt evicts the victims of preemption, so that the volume can be scheduled in the next reconciling.
+// Reservations are removed from State, and the preemption is persisted in the StorageReservation, so they are not reserved again.
+// Volumes are unbound from the node and will be scheduled again.
+// Every eviction is recorded as an event.
+func (r *AntstorVolumeReconcileHandler) preempt(ctx context.Context, volume *v1.AntstorVolume, p *sched.Preemption, log logr.Logger) (err error) {
+	var nodeID = p.Node.Info.ID
+
+	for _, resv := range p.Reservations {
+		if err = r.persistPreemption(ctx, resv, nodeID); err != nil {
+			log.Error(err, "persist preemption of reservation failed", "reservation", resv.ID())
+			return
+		}
+		p.Node.Unreserve(resv.ID())
+		msg := fmt.Sprintf("preempted reservation %s (priority %d, size %d) on node %s", resv.ID(), resv.Priority(), resv.Size(), nodeID)
+		log.Info(msg)
+		r.recordEvent(volume, corev1.EventTypeNormal, EventReasonPreempt, msg)
+	}
+
+	for _, item := range p.Volumes {
+		var victim v1.AntstorVolume
+		// unbind from state first, otherwise the victim may be bound to the node again by its reconciling
+		if err = r.State.UnbindAntstorVolume(item.Spec.Uuid); err != nil {
+			log.Error(err, "unbind volume from state failed", "victim", item.Name)
+		}
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() (err error) {
+			// state may be stale, so check the latest volume
+			if err = r.Client.Get(ctx, client.ObjectKeyFromObject(item), &victim); err != nil {
+				return
+			}
+			if victim.Spec.TargetNodeId != nodeID || !sched.IsPreemptible(&victim, volume) {
+				return fmt.Errorf("volume %s is not preemptible any more", victim.Name)
+			}
+			// the patch fails if the victim is changed after the check
+			patch := client.MergeFromWithOptions(victim.DeepCopy(), client.MergeFromWithOptimisticLock{})
+			victim.Spec.TargetNodeId = ""
+			delete(victim.Labels, v1.TargetNodeIdLabelKey)
+			return r.Client.Patch(ctx, &victim, patch)
+		})
+		if err != nil {
+			log.Error(err, "unbind preempted volume failed", "victim", item.Name)
+			if !errors.IsNotFound(err) {
+				if errBind := r.State.BindAntstorVolume(nodeID, item); errBind != nil {
+					log.Error(errBind, "bind volume to state again failed", "victim", item.Name)
+				}
+			}
+			return
+		}
+
+		msg := fmt.Sprintf("preempted volume %s (priority %d, size %d) on node %s", victim.Name, victim.Spec.Priority, victim.Spec.SizeByte, nodeID)
+		log.Info(msg)
+		r.recordEvent(volume, corev1.EventTypeNormal, EventReasonPreempt, msg)
+
+		msg = fmt.Sprintf("preempted by volume %s (priority %d) on node %s", volume.Name, volume.Spec.Priority, nodeID)
+		r.recordEvent(&victim, corev1.EventTypeWarning, EventReasonPreempted, msg)
+		if victim.SetCondition(v1.VolumeCondition{
+			Type:    v1.VolumeConditionScheduled,
+			Status:  v1.StatusError,
+			Reason:  EventReasonPreempted,
+			Message: msg,
+		}) {
+			if errUpdate := r.Client.Status().Update(ctx, &victim); errUpdate != nil {
+				log.Error(errUpdate, "update condition of preempted volume failed", "victim", victim.Name)
+			}
+		}
+	}
+
+	return
+}
+
+// persistPreemption adds the node to status.preemptedNodes of StorageReservation
+func (r *AntstorVolumeReconcileHandler) persistPreemption(ctx context.Context, resv state.ReservationIface, nodeID string) (err error) {
+	ns, name, ok := v1.ParseStorageReservationID(resv.ID())
+	if !ok {
+		return
+	}
+	var sr v1.StorageReservation
+	if err = r.Client.Get(ctx, types.NamespacedName{Namespace: ns, Name: name}, &sr); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if misc.InSliceString(nodeID, sr.Status.PreemptedNodes) {
+		return
+	}
+	sr.Status.PreemptedNodes = append(sr.Status.PreemptedNodes, nodeID)
+	sort.Strings(sr.Status.PreemptedNodes)
+	var reserved []string
+	for _, item := range sr.Status.ReservedNodes {
+		if item != nodeID {
+			reserved = append(reserved, item)
+		}
+	}
+	sr.Status.ReservedNodes = reserved
+	return r.Client.Status().Update(ctx, &sr)
+}
+
+func (r *AntstorVolumeReconcileHandler) recordEvent(obj *v1.AntstorVolume, eventType, reason, msg string) {
+	if r.EventRecorder != nil {
+		r.EventRecorder.Event(obj, eventType, reason, msg)
+	}
+}
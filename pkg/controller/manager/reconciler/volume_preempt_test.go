@@ -0,0 +1,127 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	sched "lite.io/liteio/pkg/controller/manager/scheduler"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+// conflictOnceClient updates the volume before the first patch of it, like another writer
+type conflictOnceClient struct {
+	client.Client
+	conflicted bool
+	patches    int
+}
+
+func (c *conflictOnceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	vol, ok := obj.(*v1.AntstorVolume)
+	if ok {
+		c.patches++
+	}
+	if ok && !c.conflicted {
+		c.conflicted = true
+		var latest v1.AntstorVolume
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(vol), &latest); err != nil {
+			return err
+		}
+		latest.Labels["updated-by"] = "other"
+		if err := c.Client.Update(ctx, &latest); err != nil {
+			return err
+		}
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func TestPreempt(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	stateObj := state.NewState()
+	stateObj.SetStoragePool(&v1.StoragePool{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "node-1"},
+		Spec: v1.StoragePoolSpec{
+			SpdkLVStore: v1.SpdkLVStore{Name: "lvs-node-1", Bytes: 100 << 30},
+			NodeInfo:    v1.NodeInfo{ID: "node-1"},
+		},
+		Status: v1.StoragePoolStatus{
+			Status:     v1.PoolStatusReady,
+			VGFreeSize: *resource.NewQuantity(100<<30, resource.BinarySI),
+		},
+	})
+	node, err := stateObj.GetNodeByNodeID("node-1")
+	assert.NoError(t, err)
+
+	sr := &v1.StorageReservation{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "best-effort"},
+		Spec:       v1.StorageReservationSpec{Size: resource.MustParse("10Gi")},
+		Status:     v1.StorageReservationStatus{ReservedNodes: []string{"node-1"}},
+	}
+	victim := &v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: v1.DefaultNamespace,
+			Name:      "victim",
+			Labels:    map[string]string{v1.TargetNodeIdLabelKey: "node-1"},
+		},
+		Spec:   v1.AntstorVolumeSpec{Uuid: "victim-uuid", SizeByte: 10 << 30, TargetNodeId: "node-1"},
+		Status: v1.AntstorVolumeStatus{Status: v1.VolumeStatusCreating},
+	}
+	srResv := state.NewReservationWithPriority(sr.ReservationID(), 10<<30, 0)
+	node.Reserve(srResv)
+	assert.NoError(t, stateObj.BindAntstorVolume("node-1", victim))
+
+	cli := &conflictOnceClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(sr, victim).Build()}
+	r := &AntstorVolumeReconcileHandler{Client: cli, State: stateObj}
+	vol := &v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "vol-1"},
+		Spec:       v1.AntstorVolumeSpec{SizeByte: 10 << 30, Priority: 10},
+	}
+	ctx := context.Background()
+
+	err = r.preempt(ctx, vol, &sched.Preemption{
+		Node:         node,
+		Reservations: []state.ReservationIface{srResv},
+		Volumes:      []*v1.AntstorVolume{victim},
+	}, zap.New())
+	assert.NoError(t, err)
+	assert.Empty(t, node.Reservations())
+	assert.Empty(t, node.Volumes)
+
+	// victim is checked and patched again after the conflict
+	assert.Equal(t, 2, cli.patches)
+	var gotVol v1.AntstorVolume
+	assert.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "victim"}, &gotVol))
+	assert.Empty(t, gotVol.Spec.TargetNodeId)
+	assert.Empty(t, gotVol.Labels[v1.TargetNodeIdLabelKey])
+	assert.Equal(t, "other", gotVol.Labels["updated-by"])
+
+	// preemption is persisted
+	var gotSR v1.StorageReservation
+	assert.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "best-effort"}, &gotSR))
+	assert.Equal(t, []string{"node-1"}, gotSR.Status.PreemptedNodes)
+	assert.Empty(t, gotSR.Status.ReservedNodes)
+
+	// StorageReservation is not reserved again on the preempted node
+	rr := &StorageReservationReconciler{Client: cli, Log: zap.New(), State: stateObj}
+	_, err = rr.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "best-effort"}})
+	assert.NoError(t, err)
+	_, has := node.GetReservation(sr.ReservationID())
+	assert.False(t, has)
+	assert.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "best-effort"}, &gotSR))
+	assert.Equal(t, []string{"node-1"}, gotSR.Status.PreemptedNodes)
+	assert.Empty(t, gotSR.Status.ReservedNodes)
+}
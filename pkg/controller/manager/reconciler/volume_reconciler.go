@@ -14,9 +14,11 @@ import (
 	"lite.io/liteio/pkg/generated/clientset/versioned"
 	"lite.io/liteio/pkg/util/misc"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -26,6 +28,7 @@ const (
 	EventReasonDeleteSpdkFailure  = "DelSpdkFailure"
 	EventReasonDeleteLvmFailure   = "DelLvmFailure"
 	EventReasonSchedVolumeFailure = "SchedVolumeFailure"
+	EventReasonSchedVolumeSuccess = "Scheduled"
 	EventReasonCreateLvmFailure   = "CreateLvmFailure"
 	EventReasonCreateSpdkFailure  = "CreateSpdkFailure"
 )
@@ -38,8 +41,8 @@ type AntstorVolumeReconcileHandler struct {
 	// if Scheduler is nil, Reconciler will not schedule Volume
 	Scheduler   sched.SchedulerIface
 	AntstoreCli versioned.Interface
-	// EventRecorder
-	// EventRecorder record.EventRecorder
+	// EventRecorder records events of volume and PVC. It is optional.
+	EventRecorder record.EventRecorder
 }
 
 func (r *AntstorVolumeReconcileHandler) ResourceName() string {
@@ -319,7 +322,31 @@ func (r *AntstorVolumeReconcileHandler) scheduleVolume(ctx context.Context, volu
 		// do scehdule
 		nodeInfo, err = scheduler.ScheduleVolume(stateObj.GetAllNodes(), volume)
 		if filter.IsNoStoragePoolAvailable(err) {
-			if !strings.Contains(volume.Status.Message, filter.NoStoragePoolAvailable) {
+			// try to preempt reservations and volumes with lower priority
+			if p := scheduler.Preempt(stateObj.GetAllNodes(), volume); p != nil {
+				errPreempt := r.preempt(ctx, volume, p, log)
+				if errPreempt == nil {
+					// schedule again after victims are evicted
+					return plugin.Result{
+						Break:  true,
+						Result: reconcile.Result{Requeue: true},
+					}
+				}
+				log.Error(errPreempt, "preempt failed")
+			}
+
+			var msg = err.Error()
+			if mergedErr, ok := err.(*filter.MergedError); ok {
+				msg = mergedErr.Summary()
+			}
+
+			condChanged := volume.SetCondition(v1.VolumeCondition{
+				Type:    v1.VolumeConditionScheduled,
+				Status:  v1.StatusError,
+				Reason:  filter.NoStoragePoolAvailable,
+				Message: msg,
+			})
+			if condChanged || !strings.Contains(volume.Status.Message, filter.NoStoragePoolAvailable) {
 				volume.Status.Message = err.Error()
 				volume.Status.Status = v1.VolumeStatusCreating
 				errUpdate := r.Client.Status().Update(ctx, volume)
@@ -329,8 +356,10 @@ func (r *AntstorVolumeReconcileHandler) scheduleVolume(ctx context.Context, volu
 			}
 
 			log.Error(err, "no Pool is fit for volume")
-			// record event
-			// r.EventRecorder.Event(&volume, corev1.EventTypeWarning, EventReasonSchedVolumeFailure, err.Error())
+			// record event only if the failure is changed, otherwise every retry records the same event
+			if condChanged {
+				r.recordSchedFailure(ctx, volume, msg, log)
+			}
 
 			// non-nil err will cause requeue
 			return plugin.Result{
@@ -366,8 +395,47 @@ func (r *AntstorVolumeReconcileHandler) scheduleVolume(ctx context.Context, volu
 			return plugin.Result{Error: err}
 		}
 
+		msg := fmt.Sprintf("volume is scheduled to node %s", nodeInfo.ID)
+		if volume.SetCondition(v1.VolumeCondition{
+			Type:    v1.VolumeConditionScheduled,
+			Status:  v1.StatusOK,
+			Reason:  EventReasonSchedVolumeSuccess,
+			Message: msg,
+		}) {
+			volume.Status.Message = ""
+			if errUpdate := r.Client.Status().Update(ctx, volume); errUpdate != nil {
+				log.Error(errUpdate, "update volume condition failed")
+			}
+		}
+		if r.EventRecorder != nil {
+			r.EventRecorder.Event(volume, corev1.EventTypeNormal, EventReasonSchedVolumeSuccess, msg)
+		}
+
 		return plugin.Result{Break: true}
 	}
 
 	return
 }
+
+// recordSchedFailure records a warning event on the volume, and the PVC from which the volume is provisioned.
+func (r *AntstorVolumeReconcileHandler) recordSchedFailure(ctx context.Context, volume *v1.AntstorVolume, msg string, log logr.Logger) {
+	if r.EventRecorder == nil {
+		return
+	}
+	r.EventRecorder.Event(volume, corev1.EventTypeWarning, EventReasonSchedVolumeFailure, msg)
+
+	var (
+		pvcName = volume.Labels[v1.VolumeContextKeyPvcName]
+		pvcNS   = volume.Labels[v1.VolumeContextKeyPvcNS]
+		pvc     corev1.PersistentVolumeClaim
+	)
+	if pvcName == "" || pvcNS == "" {
+		return
+	}
+	err := r.Client.Get(ctx, client.ObjectKey{Namespace: pvcNS, Name: pvcName}, &pvc)
+	if err != nil {
+		log.Error(err, "get PVC of volume failed", "pvc", pvcNS+"/"+pvcName)
+		return
+	}
+	r.EventRecorder.Eventf(&pvc, corev1.EventTypeWarning, EventReasonSchedVolumeFailure, "volume %s: %s", volume.Name, msg)
+}
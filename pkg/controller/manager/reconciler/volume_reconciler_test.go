@@ -0,0 +1,82 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/config"
+	sched "lite.io/liteio/pkg/controller/manager/scheduler"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+func TestScheduleFailureEvent(t *testing.T) {
+	var scheme = runtime.NewScheme()
+	assert.NoError(t, v1.AddToScheme(scheme))
+
+	var (
+		stateObj = state.NewState()
+		recorder = record.NewFakeRecorder(10)
+		ctx      = context.Background()
+	)
+	vol := &v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "vol-1"},
+		Spec:       v1.AntstorVolumeSpec{Uuid: "vol-1-uuid", Type: v1.VolumeTypeKernelLVol, SizeByte: 10 << 30},
+		Status:     v1.AntstorVolumeStatus{Status: v1.VolumeStatusCreating},
+	}
+	newOfflinePool := func(nodeID string) *v1.StoragePool {
+		return &v1.StoragePool{
+			ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: nodeID},
+			Spec: v1.StoragePoolSpec{
+				SpdkLVStore: v1.SpdkLVStore{Name: "lvs-" + nodeID, Bytes: 100 << 30},
+				NodeInfo:    v1.NodeInfo{ID: nodeID},
+			},
+			Status: v1.StoragePoolStatus{
+				Status:     v1.PoolStatusOffline,
+				VGFreeSize: *resource.NewQuantity(100<<30, resource.BinarySI),
+			},
+		}
+	}
+	stateObj.SetStoragePool(newOfflinePool("node-1"))
+
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(vol).Build()
+	r := &AntstorVolumeReconcileHandler{
+		Client: cli,
+		State:  stateObj,
+		Scheduler: sched.NewScheduler(config.Config{
+			Scheduler: config.SchedulerConfig{Filters: []string{"Basic"}},
+		}),
+		EventRecorder: recorder,
+	}
+	schedule := func() {
+		var latest v1.AntstorVolume
+		assert.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: v1.DefaultNamespace, Name: "vol-1"}, &latest))
+		result := r.scheduleVolume(ctx, &latest, zap.New())
+		assert.True(t, result.Break)
+	}
+
+	schedule()
+	assert.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, EventReasonSchedVolumeFailure)
+	assert.Contains(t, event, "0/1 pools available")
+
+	// the same failure is not recorded again
+	schedule()
+	assert.Len(t, recorder.Events, 0)
+
+	// failure is changed
+	stateObj.SetStoragePool(newOfflinePool("node-2"))
+	schedule()
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "0/2 pools available")
+}
@@ -0,0 +1,187 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/scheduler/filter"
+	"lite.io/liteio/pkg/controller/manager/scheduler/priority"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+const (
+	explainVolumeName = "sched-explain"
+)
+
+// ExplainRequest describes a hypothetical volume to be scheduled
+type ExplainRequest struct {
+	// Size of volume, e.g. 10Gi
+	Size string `json:"size"`
+	// +optional
+	Type v1.VolumeType `json:"type,omitempty"`
+	// +optional
+	IsThin bool `json:"isThin,omitempty"`
+	// +optional
+	PositionAdvice v1.VolumePosition `json:"positionAdvice,omitempty"`
+	// HostNode is the node id where the consumer pod runs
+	// +optional
+	HostNode string `json:"hostNode,omitempty"`
+	// +optional
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+	// +optional
+	PoolAffinity *corev1.NodeAffinity `json:"poolAffinity,omitempty"`
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ExplainResult is the result of a dry-run scheduling
+type ExplainResult struct {
+	// Profile is the name of scheduler profile. Empty means default profile.
+	Profile string `json:"profile"`
+	// SelectedNode is the node id of the best pool
+	SelectedNode string `json:"selectedNode,omitempty"`
+	// Reasons are the merged failure reasons and counts
+	Reasons map[string]int `json:"reasons,omitempty"`
+	// Message is the error message when no pool is available
+	Message string `json:"message,omitempty"`
+	// Pools are the results of every pool
+	Pools []PoolExplainResult `json:"pools"`
+}
+
+type PoolExplainResult struct {
+	Name    string   `json:"name"`
+	Passed  bool     `json:"passed"`
+	Reasons []string `json:"reasons,omitempty"`
+	// Score is nil if the pool does not pass filters
+	Score *int `json:"score,omitempty"`
+}
+
+// ToVolume converts the request to a volume. Node info of HostNode is copied from the state if the node has a pool.
+func (req ExplainRequest) ToVolume(allNodes []*state.Node) (vol *v1.AntstorVolume, err error) {
+	size, err := resource.ParseQuantity(req.Size)
+	if err != nil {
+		err = fmt.Errorf("invalid size %q, %w", req.Size, err)
+		return
+	}
+	if size.Sign() <= 0 {
+		err = fmt.Errorf("size %q must be positive", req.Size)
+		return
+	}
+
+	vol = &v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   v1.DefaultNamespace,
+			Name:        explainVolumeName,
+			Labels:      make(map[string]string),
+			Annotations: make(map[string]string),
+		},
+		Spec: v1.AntstorVolumeSpec{
+			Uuid:           explainVolumeName,
+			Type:           req.Type,
+			SizeByte:       uint64(size.Value()),
+			PositionAdvice: req.PositionAdvice,
+			IsThin:         req.IsThin,
+			NodeAffinity:   req.NodeAffinity,
+			PoolAffinity:   req.PoolAffinity,
+			HostNode: &v1.NodeInfo{
+				ID: req.HostNode,
+			},
+		},
+	}
+	if vol.Spec.Type == "" {
+		vol.Spec.Type = v1.VolumeTypeFlexible
+	}
+	for key, val := range req.Labels {
+		vol.Labels[key] = val
+	}
+	for key, val := range req.Annotations {
+		vol.Annotations[key] = val
+	}
+
+	for _, item := range allNodes {
+		if req.HostNode != "" && item.Info.ID == req.HostNode {
+			vol.Spec.HostNode = item.Info.DeepCopy()
+			break
+		}
+	}
+
+	return
+}
+
+// ExplainVolume runs filters and priorities for the volume, without binding it to any node
+func (s *scheduler) ExplainVolume(allNodes []*state.Node, vol *v1.AntstorVolume) (result ExplainResult) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	result.Profile = vol.Annotations[v1.SchedulerProfileAnnoKey]
+	profile, err := s.profileOf(allNodes, vol.Name, vol.Annotations)
+	if mergedErr, ok := err.(*filter.MergedError); ok {
+		result.Reasons = mergedErr.Reasons()
+		result.Message = mergedErr.Summary()
+		return
+	}
+	var cfg = s.cfg.Scheduler
+	cfg.Filters = profile.Filters
+
+	chain := filter.NewFilterChain(cfg).
+		Input(allNodes, vol).
+		LoadFilterFromConfig()
+	filterResults, err := chain.MatchEach()
+
+	var candidates []*state.Node
+	result.Pools = make([]PoolExplainResult, 0, len(filterResults))
+	for _, item := range filterResults {
+		result.Pools = append(result.Pools, PoolExplainResult{
+			Name:    item.Node.Pool.Name,
+			Passed:  item.Passed,
+			Reasons: item.Reasons,
+		})
+		if item.Passed {
+			candidates = append(candidates, item.Node)
+		}
+	}
+
+	if err != nil {
+		result.Message = err.Error()
+	}
+	if mergedErr, ok := err.(*filter.MergedError); ok {
+		result.Reasons = mergedErr.Reasons()
+		result.Message = mergedErr.Summary()
+	}
+
+	if len(candidates) > 0 {
+		scores := priority.NewPriorityCalculator(cfg).
+			Input(candidates, vol).
+			WithContextValue(priority.CtxKeyAllNodes, allNodes).
+			LoadPriorityFromProfile(profile).
+			Scores()
+		var scoreMap = make(map[string]int, len(scores))
+		for _, item := range scores {
+			scoreMap[item.NodeID] = item.Score
+		}
+
+		var bestScore int
+		for i, item := range result.Pools {
+			if score, has := scoreMap[filterResults[i].Node.Info.ID]; has && item.Passed {
+				result.Pools[i].Score = &score
+				if result.SelectedNode == "" || score > bestScore {
+					bestScore = score
+					result.SelectedNode = filterResults[i].Node.Info.ID
+				}
+			}
+		}
+	}
+
+	sort.Slice(result.Pools, func(i, j int) bool {
+		return result.Pools[i].Name < result.Pools[j].Name
+	})
+
+	return
+}
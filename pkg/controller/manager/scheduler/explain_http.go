@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"k8s.io/klog/v2"
+
+	"lite.io/liteio/pkg/controller/manager/state"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+const (
+	// ExplainURI is the path of explain API on the metrics service of controller
+	ExplainURI = "/scheduler/explain"
+)
+
+func NewExplainHandler(s state.StateIface, sched SchedulerIface) *ExplainHandler {
+	return &ExplainHandler{
+		state: s,
+		sched: sched,
+	}
+}
+
+// ExplainHandler serves dry-run scheduling requests. It accepts POST of ExplainRequest in JSON.
+type ExplainHandler struct {
+	state state.StateIface
+	sched SchedulerIface
+}
+
+func (h *ExplainHandler) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		misc.WriteJSON(writer, http.StatusMethodNotAllowed, misc.ErrorResponse{Error: "only POST is allowed"})
+		return
+	}
+
+	var explainReq ExplainRequest
+	if err := json.NewDecoder(req.Body).Decode(&explainReq); err != nil {
+		misc.WriteJSON(writer, http.StatusBadRequest, misc.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	allNodes := h.state.GetAllNodes()
+	vol, err := explainReq.ToVolume(allNodes)
+	if err != nil {
+		misc.WriteJSON(writer, http.StatusBadRequest, misc.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	result := h.sched.ExplainVolume(allNodes, vol)
+	klog.Infof("explain scheduling of %+v, selected node %q, reasons %+v", explainReq, result.SelectedNode, result.Reasons)
+	misc.WriteJSON(writer, http.StatusOK, result)
+}
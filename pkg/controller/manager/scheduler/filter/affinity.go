@@ -15,7 +15,7 @@ func AffinityFilterFunc(ctx *FilterContext, n *state.Node, vol *v1.AntstorVolume
 		// It is a little tricky to create a Node only with Labels. This is an easy way to reuse MatchNodeSelectorTerms.
 		// MatchNodeSelectorTerms extracts nodeLabels and nodeFileds, and use NodeSelector to match them.
 		// Code: https://github.com/kubernetes/component-helpers/blob/master/scheduling/corev1/nodeaffinity/nodeaffinity.go#L84
-		match, err := schedcore.MatchNodeSelectorTerms(convertNodeInfo(n.Info), vol.Spec.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+		match, err := schedcore.MatchNodeSelectorTerms(ConvertNodeInfo(n.Info), vol.Spec.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
 		if !match || err != nil {
 			klog.Infof("[SchedFail] vol=%s Pool %s NodeAffnity fail", vol.Name, n.Pool.Name)
 			ctx.Error.AddReason(ReasonNodeAffinity)
@@ -40,7 +40,7 @@ func AffinityFilterFunc(ctx *FilterContext, n *state.Node, vol *v1.AntstorVolume
 
 	// consider pool affinity
 	if vol.Spec.PoolAffinity != nil && vol.Spec.PoolAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
-		match, err := schedcore.MatchNodeSelectorTerms(convertPoolLabels(n.Pool.Labels), vol.Spec.PoolAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+		match, err := schedcore.MatchNodeSelectorTerms(ConvertPoolLabels(n.Pool.Labels), vol.Spec.PoolAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
 		if !match || err != nil {
 			klog.Infof("[SchedFail] vol=%s Pool %s PoolAffinity fail", vol.Name, n.Pool.Name)
 			ctx.Error.AddReason(ReasonPoolAffinity)
@@ -64,14 +64,16 @@ func AffinityFilterFunc(ctx *FilterContext, n *state.Node, vol *v1.AntstorVolume
 	return true
 }
 
-func convertNodeInfo(nodeInfo *v1.NodeInfo) (node *corev1.Node) {
+// ConvertNodeInfo builds a Node only with name and labels of NodeInfo, for matching NodeSelector
+func ConvertNodeInfo(nodeInfo *v1.NodeInfo) (node *corev1.Node) {
 	node = &corev1.Node{}
 	node.Name = nodeInfo.ID
 	node.Labels = nodeInfo.Labels
 	return
 }
 
-func convertPoolLabels(labels labels.Set) (node *corev1.Node) {
+// ConvertPoolLabels builds a Node only with labels of Pool, for matching NodeSelector
+func ConvertPoolLabels(labels labels.Set) (node *corev1.Node) {
 	node = &corev1.Node{}
 	node.Labels = labels
 	return
@@ -19,6 +19,8 @@
 package filter
 
 import (
+	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -37,16 +39,40 @@ const (
 	ReasonReservationSize   = "ReservationTooSmall"
 	ReasonReserveNotMatch   = "ReservationNotMatch"
 	ReasonThinProvision     = "ThinProvision"
+	ReasonDataHolderSpread  = "DataHolderSpread"
+	ReasonIOLoad            = "IOLoadTooHigh"
+	ReasonProfileNotFound   = "ProfileNotFound"
 
 	NoStoragePoolAvailable = "NoStoragePoolAvailable"
 	//
 	CtxErrKey = "globalError"
 )
 
+// reasonMessages are human readable descriptions of reasons
+var reasonMessages = map[string]string{
+	ReasonPoolFreeSize:      "insufficient space",
+	ReasonSpdkUnhealthy:     "SPDK unhealthy",
+	ReasonRemoteVolMaxCount: "too many remote volumes",
+	ReasonPositionNotMatch:  "position not match",
+	ReasonVolTypeNotMatch:   "volume type not match",
+	ReasonDataConflict:      "data conflict",
+	ReasonNodeAffinity:      "node affinity not match",
+	ReasonPoolAffinity:      "pool affinity not match",
+	ReasonPoolUnschedulable: "pool unschedulable",
+	ReasonReservationSize:   "reservation too small",
+	ReasonReserveNotMatch:   "reservation not match",
+	ReasonThinProvision:     "thin provision not supported",
+	ReasonDataHolderSpread:  "data-holder spread conflict",
+	ReasonIOLoad:            "IO load too high",
+	ReasonProfileNotFound:   "scheduler profile not found",
+}
+
 type MergedError struct {
 	// reason -> count
 	reasons map[string]int
-	lock    sync.Mutex
+	// total count of filtered pools
+	total int
+	lock  sync.Mutex
 }
 
 func NewMergedError() *MergedError {
@@ -79,6 +105,63 @@ func (e *MergedError) AddReason(reason string) {
 	}
 }
 
+// Reasons returns a copy of reasons and counts
+func (e *MergedError) Reasons() map[string]int {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	reasons := make(map[string]int, len(e.reasons))
+	for reason, cnt := range e.reasons {
+		reasons[reason] = cnt
+	}
+	return reasons
+}
+
+// Merge adds reasons of other MergedError
+func (e *MergedError) Merge(other *MergedError) {
+	for reason, cnt := range other.Reasons() {
+		e.lock.Lock()
+		e.reasons[reason] += cnt
+		e.lock.Unlock()
+	}
+}
+
+// SetTotal sets the count of pools which are filtered
+func (e *MergedError) SetTotal(total int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.total = total
+}
+
+// Summary returns an aggregated message like "0/12 pools available: 7 insufficient space, 5 SPDK unhealthy".
+// Reasons are sorted by count in descending order.
+func (e *MergedError) Summary() string {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	reasons := make([]string, 0, len(e.reasons))
+	for reason := range e.reasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if e.reasons[reasons[i]] != e.reasons[reasons[j]] {
+			return e.reasons[reasons[i]] > e.reasons[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	items := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		msg, has := reasonMessages[reason]
+		if !has {
+			msg = reason
+		}
+		items = append(items, fmt.Sprintf("%d %s", e.reasons[reason], msg))
+	}
+
+	return fmt.Sprintf("0/%d pools available: %s", e.total, strings.Join(items, ", "))
+}
+
 func IsNoStoragePoolAvailable(err error) bool {
 	if err == nil {
 		return false
@@ -2,6 +2,7 @@ package filter
 
 import (
 	"context"
+	"sort"
 
 	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
 	"lite.io/liteio/pkg/controller/manager/config"
@@ -13,6 +14,8 @@ type FilterContext struct {
 	Ctx    context.Context
 	Config config.SchedulerConfig
 	Error  *MergedError
+	// Nodes are all the input nodes, for filters which need to know the whole cluster
+	Nodes []*state.Node
 }
 
 type PredicateFunc func(*FilterContext, *state.Node, *v1.AntstorVolume) bool
@@ -45,6 +48,13 @@ func (fc *FilterChain) WithContextValue(key, val interface{}) *FilterChain {
 func (fc *FilterChain) Input(nodes []*state.Node, vol *v1.AntstorVolume) *FilterChain {
 	fc.nodes = nodes
 	fc.vol = vol
+	fc.ctx.Nodes = nodes
+	return fc
+}
+
+// Candidates limits the nodes to be filtered. FilterContext.Nodes is still the whole input nodes.
+func (fc *FilterChain) Candidates(nodes []*state.Node) *FilterChain {
+	fc.nodes = nodes
 	return fc
 }
 
@@ -75,11 +85,57 @@ func (fc *FilterChain) MatchAll() (candidates []*state.Node, err error) {
 		}
 	}
 	if len(candidates) == 0 {
+		fc.ctx.Error.SetTotal(len(fc.nodes))
 		err = fc.ctx.Error
 	}
 	return
 }
 
+// FilterResult is the filtering result of one Node
+type FilterResult struct {
+	Node   *state.Node
+	Passed bool
+	// Reasons of the failure
+	Reasons []string
+}
+
+// MatchEach filters all nodes and returns the result of each node.
+// The failure reasons are also merged into the error of the chain.
+func (fc *FilterChain) MatchEach() (results []FilterResult, err error) {
+	var (
+		mergedErr = fc.ctx.Error
+		passed    int
+	)
+	defer func() {
+		fc.ctx.Error = mergedErr
+	}()
+
+	results = make([]FilterResult, 0, len(fc.nodes))
+	for _, node := range fc.nodes {
+		fc.ctx.Error = NewMergedError()
+		result := FilterResult{
+			Node:   node,
+			Passed: fc.passAllFilters(fc.filters, node, fc.vol),
+		}
+		for reason := range fc.ctx.Error.Reasons() {
+			result.Reasons = append(result.Reasons, reason)
+		}
+		sort.Strings(result.Reasons)
+		mergedErr.Merge(fc.ctx.Error)
+
+		if result.Passed {
+			passed++
+		}
+		results = append(results, result)
+	}
+
+	if passed == 0 {
+		mergedErr.SetTotal(len(fc.nodes))
+		err = mergedErr
+	}
+	return
+}
+
 func (fc *FilterChain) passAllFilters(filters []PredicateFunc, node *state.Node, vol *v1.AntstorVolume) bool {
 	for _, filterFunc := range filters {
 		if !filterFunc(fc.ctx, node, vol) {
@@ -0,0 +1,44 @@
+package filter
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+// IOLoadFilterFunc rejects the Pool for remote volumes, if IO load of the Pool exceeds the ceiling in config.
+// Local volumes are not affected, because they have no other choices. Expired IO stats are ignored.
+func IOLoadFilterFunc(ctx *FilterContext, n *state.Node, vol *v1.AntstorVolume) bool {
+	var (
+		stats = n.Pool.FreshIOStats(time.Now())
+		limit = ctx.Config.IOLoad
+	)
+	if stats == nil {
+		return true
+	}
+	if vol.Spec.HostNode != nil && vol.Spec.HostNode.ID == n.Info.ID {
+		return true
+	}
+
+	if ExceedIOLoad(stats, limit) {
+		klog.Infof("[SchedFail] vol=%s Pool %s IO load %+v exceeds %+v", vol.Name, n.Pool.Name, *stats, limit)
+		ctx.Error.AddReason(ReasonIOLoad)
+		return false
+	}
+
+	return true
+}
+
+// ExceedIOLoad returns true if any of IOPS, bandwidth and queue time exceeds the limit
+func ExceedIOLoad(stats *v1.PoolIOStats, limit config.IOLoadConfig) bool {
+	if stats == nil {
+		return false
+	}
+	return (limit.MaxIOPS > 0 && stats.IOPS() > limit.MaxIOPS) ||
+		(limit.MaxBps > 0 && stats.Bps() > limit.MaxBps) ||
+		(limit.MaxQueueTimeUs > 0 && stats.QueueTimeUs > limit.MaxQueueTimeUs)
+}
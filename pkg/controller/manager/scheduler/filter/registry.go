@@ -15,6 +15,8 @@ func init() {
 	RegisterFilter("Basic", BasicFilterFunc)
 	RegisterFilter("Affinity", AffinityFilterFunc)
 	RegisterFilter("MinLocalStorage", MinLocalStorageFilterFunc)
+	RegisterFilter("DataHolderSpread", DataHolderSpreadFilterFunc)
+	RegisterFilter("IOLoad", IOLoadFilterFunc)
 }
 
 func RegisterFilter(name string, filter PredicateFunc) {
@@ -0,0 +1,93 @@
+package filter
+
+import (
+	"k8s.io/klog/v2"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/kubeutil"
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+// DataHolderSpreadFilterFunc rejects the Pool, if the failure domain (node, rack or room) of the Pool
+// already has a volume with the same data-holder.
+func DataHolderSpreadFilterFunc(ctx *FilterContext, n *state.Node, vol *v1.AntstorVolume) bool {
+	var (
+		spreadCfg  = ctx.Config.DataHolderSpread
+		dataHolder = vol.Labels[v1.VolumeDataHolderKey]
+	)
+	if dataHolder == "" {
+		return true
+	}
+
+	domain := FailureDomainOf(n, spreadCfg)
+	for _, node := range ctx.Nodes {
+		if FailureDomainOf(node, spreadCfg) != domain {
+			continue
+		}
+		if cnt := CountDataHolderVolumes(node, vol); cnt > 0 {
+			klog.Infof("[SchedFail] vol=%s Pool %s, data-holder %s already has %d volumes on %s %s (Pool %s)",
+				vol.Name, n.Pool.Name, dataHolder, cnt, spreadLevel(spreadCfg), domain, node.Pool.Name)
+			ctx.Error.AddReason(ReasonDataHolderSpread)
+			return false
+		}
+	}
+
+	return true
+}
+
+// FailureDomainOf returns the name of failure domain where the Node is located.
+// If rack or room label is missing on the Node, node id is returned.
+func FailureDomainOf(n *state.Node, cfg config.DataHolderSpreadConfig) (domain string) {
+	return FailureDomainOfLevel(n, spreadLevel(cfg), cfg)
+}
+
+// FailureDomainOfLevel returns the name of failure domain at the specified level
+func FailureDomainOfLevel(n *state.Node, level config.SpreadLevel, cfg config.DataHolderSpreadConfig) (domain string) {
+	if n == nil || n.Info == nil {
+		return
+	}
+
+	info := kubeutil.FromLabels(kubeutil.NodeInfoOption(cfg.NodeKeys), n.Info.Labels)
+	switch level {
+	case config.SpreadLevelRack:
+		domain = info.Rack
+	case config.SpreadLevelRoom:
+		domain = info.Room
+	}
+
+	if domain == "" {
+		domain = n.Info.ID
+	} else {
+		domain = string(level) + "/" + domain
+	}
+
+	return
+}
+
+// CountDataHolderVolumes returns the count of volumes on the Node, which have the same data-holder with vol.
+// vol itself is not counted.
+func CountDataHolderVolumes(n *state.Node, vol *v1.AntstorVolume) (cnt int) {
+	dataHolder := vol.Labels[v1.VolumeDataHolderKey]
+	if dataHolder == "" {
+		return
+	}
+
+	for _, item := range n.View().Volumes {
+		if item.Spec.Uuid == vol.Spec.Uuid && item.Name == vol.Name {
+			continue
+		}
+		if item.Labels[v1.VolumeDataHolderKey] == dataHolder {
+			cnt++
+		}
+	}
+
+	return
+}
+
+func spreadLevel(cfg config.DataHolderSpreadConfig) config.SpreadLevel {
+	if cfg.Level == "" {
+		return config.SpreadLevelNode
+	}
+	return cfg.Level
+}
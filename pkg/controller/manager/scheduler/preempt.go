@@ -0,0 +1,170 @@
+package scheduler
+
+import (
+	"sort"
+
+	"k8s.io/klog/v2"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/scheduler/filter"
+	"lite.io/liteio/pkg/controller/manager/state"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+// Preemption is the decision of evicting reservations and volumes with lower priority from a node,
+// so that a volume with higher priority can be scheduled to the node.
+type Preemption struct {
+	Node *state.Node
+	// Reservations to be removed from the node
+	Reservations []state.ReservationIface
+	// Volumes to be unbound from the node. They are bound to the node, but not created yet.
+	Volumes []*v1.AntstorVolume
+}
+
+// victim is either a reservation or a volume
+type victim struct {
+	resv state.ReservationIface
+	vol  *v1.AntstorVolume
+}
+
+func (v victim) priority() int32 {
+	if v.resv != nil {
+		return v.resv.Priority()
+	}
+	return v.vol.Spec.Priority
+}
+
+func (v victim) size() int64 {
+	if v.resv != nil {
+		return v.resv.Size()
+	}
+	return int64(v.vol.GetTotalSize())
+}
+
+// IsPreemptible returns true if victim can be preempted by vol.
+// The victim must have lower priority, and its logic volume is not created yet.
+func IsPreemptible(victim, vol *v1.AntstorVolume) bool {
+	return victim.Spec.Priority < vol.Spec.Priority &&
+		victim.Name != vol.Name &&
+		victim.DeletionTimestamp == nil &&
+		victim.Status.Status != v1.VolumeStatusReady &&
+		!misc.InSliceString(v1.LogicVolumeFinalizer, victim.Finalizers) &&
+		// volume is bound to the node selected by kube-scheduler
+		victim.Annotations[v1.SelectedTgtNodeKey] == ""
+}
+
+// Preempt finds a node, where vol can be scheduled after evicting reservations and volumes with lower priority.
+// On each node, victims are evicted in ascending order of priority until vol passes the filters.
+// The node whose highest victim priority is the lowest is chosen, then the node with fewer victims.
+// It returns nil if preemption does not help.
+func (s *scheduler) Preempt(allNodes []*state.Node, vol *v1.AntstorVolume) (p *Preemption) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	profile, err := s.profileOf(allNodes, vol.Name, vol.Annotations)
+	if err != nil {
+		return
+	}
+	var cfg = s.cfg.Scheduler
+	cfg.Filters = profile.Filters
+
+	var best []victim
+	for idx, node := range allNodes {
+		victims := victimsOf(node, vol)
+		if len(victims) == 0 {
+			continue
+		}
+
+		var (
+			resvIDs []string
+			volKeys []string
+			nodes   = make([]*state.Node, len(allNodes))
+		)
+		copy(nodes, allNodes)
+		for i, item := range victims {
+			if item.resv != nil {
+				resvIDs = append(resvIDs, item.resv.ID())
+			} else {
+				volKeys = append(volKeys, item.vol.Namespace+"/"+item.vol.Name)
+			}
+
+			nodes[idx] = node.CopyWithout(resvIDs, volKeys)
+			qualified, _ := filter.NewFilterChain(cfg).
+				Input(nodes, vol).
+				Candidates(nodes[idx : idx+1]).
+				LoadFilterFromConfig().
+				MatchAll()
+			if len(qualified) > 0 {
+				if p == nil || lessVictims(victims[:i+1], best) {
+					p = newPreemption(node, victims[:i+1])
+					best = victims[:i+1]
+				}
+				break
+			}
+		}
+	}
+
+	if p != nil {
+		klog.Infof("vol %s (priority %d) can preempt %d reservations and %d volumes on node %s",
+			vol.Name, vol.Spec.Priority, len(p.Reservations), len(p.Volumes), p.Node.Info.ID)
+	}
+
+	return
+}
+
+// victimsOf returns reservations and volumes on the node which can be preempted by vol.
+// Victims are sorted by priority. With the same priority, reservations are evicted before volumes, and larger ones first.
+func victimsOf(node *state.Node, vol *v1.AntstorVolume) (victims []victim) {
+	var view = node.View()
+	for _, resv := range view.Reservations {
+		if isPreemptibleReservation(resv) && resv.Priority() < vol.Spec.Priority && resv.ID() != vol.ReservationID() {
+			victims = append(victims, victim{resv: resv})
+		}
+	}
+	for _, item := range view.Volumes {
+		if IsPreemptible(item, vol) {
+			victims = append(victims, victim{vol: item})
+		}
+	}
+
+	sort.SliceStable(victims, func(i, j int) bool {
+		if victims[i].priority() != victims[j].priority() {
+			return victims[i].priority() < victims[j].priority()
+		}
+		if (victims[i].resv != nil) != (victims[j].resv != nil) {
+			return victims[i].resv != nil
+		}
+		return victims[i].size() > victims[j].size()
+	})
+
+	return
+}
+
+// isPreemptibleReservation returns true if the reservation is owned by a StorageReservation, where the preemption is persisted.
+// Reservation of a PVC is consumed by its volume, which only passes filters on the pool holding the reservation.
+// Reservations of config are loaded again with the StoragePool. So they are not preempted.
+func isPreemptibleReservation(resv state.ReservationIface) bool {
+	_, _, isStorageResv := v1.ParseStorageReservationID(resv.ID())
+	return isStorageResv
+}
+
+// lessVictims returns true if evicting a costs less than b
+func lessVictims(a, b []victim) bool {
+	// victims are sorted by priority, so the last one has the highest priority
+	if pa, pb := a[len(a)-1].priority(), b[len(b)-1].priority(); pa != pb {
+		return pa < pb
+	}
+	return len(a) < len(b)
+}
+
+func newPreemption(node *state.Node, victims []victim) (p *Preemption) {
+	p = &Preemption{Node: node}
+	for _, item := range victims {
+		if item.resv != nil {
+			p.Reservations = append(p.Reservations, item.resv)
+		} else {
+			p.Volumes = append(p.Volumes, item.vol)
+		}
+	}
+	return
+}
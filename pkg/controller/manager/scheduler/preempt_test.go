@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+func TestPreempt(t *testing.T) {
+	const gib = 1 << 30
+
+	newPool := func(nodeID string, vgFree int64) *v1.StoragePool {
+		return &v1.StoragePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      nodeID,
+				Namespace: v1.DefaultNamespace,
+			},
+			Spec: v1.StoragePoolSpec{
+				SpdkLVStore: v1.SpdkLVStore{
+					Name:  "lvs-" + nodeID,
+					UUID:  "lvs-uuid-" + nodeID,
+					Bytes: 100 * gib,
+				},
+				NodeInfo: v1.NodeInfo{ID: nodeID},
+			},
+			Status: v1.StoragePoolStatus{
+				Status:     v1.PoolStatusReady,
+				VGFreeSize: *resource.NewQuantity(vgFree, resource.BinarySI),
+				Conditions: []v1.PoolCondition{
+					{Type: v1.PoolConditionSpkdHealth, Status: v1.StatusOK},
+				},
+			},
+		}
+	}
+	newVol := func(name string, size uint64, priority int32, status v1.VolumeStatus) *v1.AntstorVolume {
+		return &v1.AntstorVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: v1.DefaultNamespace,
+			},
+			Spec: v1.AntstorVolumeSpec{
+				Uuid:     name + "-uuid",
+				Type:     v1.VolumeTypeSpdkLVol,
+				SizeByte: size,
+				Priority: priority,
+				HostNode: &v1.NodeInfo{ID: "host-node"},
+			},
+			Status: v1.AntstorVolumeStatus{
+				Status: status,
+			},
+		}
+	}
+
+	memState := state.NewState()
+	memState.SetStoragePool(newPool("node-1", 100*gib))
+	memState.SetStoragePool(newPool("node-2", 10*gib))
+	// node-1: 60Gi best-effort reservation, 5Gi reservation of config and 30Gi volume not created yet, 5Gi free
+	node1, _ := memState.GetNodeByNodeID("node-1")
+	node1.Reserve(state.NewReservationWithPriority(v1.StorageReservationID("app", "best-effort"), 60*gib, 0))
+	// reservation of config is not preemptible
+	node1.Reserve(state.NewReservation("config-resv", 5*gib))
+	assert.NoError(t, memState.BindAntstorVolume("node-1", newVol("creating", 30*gib, 0, v1.VolumeStatusCreating)))
+	// node-2: 90Gi volume is created, 10Gi free
+	assert.NoError(t, memState.BindAntstorVolume("node-2", newVol("ready", 90*gib, 0, v1.VolumeStatusReady)))
+
+	sched := NewScheduler(config.Config{
+		Scheduler: config.SchedulerConfig{
+			MaxRemoteVolumeCount: 10,
+			Filters:              []string{"Basic"},
+			Priorities:           []string{"LeastResource"},
+		},
+	})
+
+	// same priority, no preemption
+	vol := newVol("vol", 50*gib, 0, v1.VolumeStatusCreating)
+	_, err := sched.ScheduleVolume(memState.GetAllNodes(), vol)
+	assert.Error(t, err)
+	assert.Nil(t, sched.Preempt(memState.GetAllNodes(), vol))
+
+	// evicting reservation is enough
+	vol.Spec.Priority = 100
+	p := sched.Preempt(memState.GetAllNodes(), vol)
+	if assert.NotNil(t, p) {
+		assert.Equal(t, "node-1", p.Node.Info.ID)
+		assert.Len(t, p.Reservations, 1)
+		assert.Empty(t, p.Volumes)
+	}
+
+	// evict reservation and volume
+	vol.Spec.SizeByte = 80 * gib
+	p = sched.Preempt(memState.GetAllNodes(), vol)
+	if assert.NotNil(t, p) {
+		assert.Equal(t, "node-1", p.Node.Info.ID)
+		assert.Len(t, p.Reservations, 1)
+		assert.Len(t, p.Volumes, 1)
+		assert.Equal(t, "creating", p.Volumes[0].Name)
+	}
+	assert.Len(t, victimsOf(node1, vol), 2)
+	// simulation does not change state
+	_, has := node1.GetReservation(v1.StorageReservationID("app", "best-effort"))
+	assert.True(t, has)
+	assert.Len(t, node1.Volumes, 1)
+
+	// created volume cannot be preempted
+	node2, _ := memState.GetNodeByNodeID("node-2")
+	assert.Empty(t, victimsOf(node2, vol))
+	vol.Spec.SizeByte = 101 * gib
+	assert.Nil(t, sched.Preempt(memState.GetAllNodes(), vol))
+}
+
+func TestPreemptPvcReservation(t *testing.T) {
+	const gib = 1 << 30
+
+	memState := state.NewState()
+	memState.SetStoragePool(newReadyStoragePool("node-1", 20*gib))
+	node1, _ := memState.GetNodeByNodeID("node-1")
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "data"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("15Gi")},
+			},
+		},
+	}
+	pvcResv := state.NewPvcReservation(pvc)
+	node1.Reserve(pvcResv)
+
+	sched := NewScheduler(config.Config{
+		Scheduler: config.SchedulerConfig{
+			MaxRemoteVolumeCount: 10,
+			Filters:              []string{"Basic"},
+			Priorities:           []string{"LeastResource"},
+		},
+	})
+	newVol := func(name string, size uint64, priority int32) *v1.AntstorVolume {
+		return &v1.AntstorVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   v1.DefaultNamespace,
+				Annotations: map[string]string{},
+			},
+			Spec: v1.AntstorVolumeSpec{
+				Uuid:     name + "-uuid",
+				Type:     v1.VolumeTypeSpdkLVol,
+				SizeByte: size,
+				Priority: priority,
+				HostNode: &v1.NodeInfo{ID: "host-node"},
+			},
+			Status: v1.AntstorVolumeStatus{Status: v1.VolumeStatusCreating},
+		}
+	}
+
+	// reservation of PVC is not preempted by volume with higher priority
+	vol := newVol("critical", 10*gib, 100)
+	_, err := sched.ScheduleVolume(memState.GetAllNodes(), vol)
+	assert.Error(t, err)
+	assert.Empty(t, victimsOf(node1, vol))
+	assert.Nil(t, sched.Preempt(memState.GetAllNodes(), vol))
+
+	// volume of the PVC is scheduled to the node holding its reservation
+	vol = newVol("data", 15*gib, 0)
+	vol.Annotations[v1.ReservationIDKey] = pvcResv.ID()
+	node, err := sched.ScheduleVolume(memState.GetAllNodes(), vol)
+	assert.NoError(t, err)
+	assert.Equal(t, "node-1", node.ID)
+}
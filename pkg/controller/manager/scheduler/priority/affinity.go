@@ -0,0 +1,64 @@
+package priority
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	"k8s.io/klog/v2"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/scheduler/filter"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+// PriorityByPreferredAffinity is a PriorityFunc. It sums up weights of matched PreferredSchedulingTerms
+// in NodeAffinity and PoolAffinity of the volume, and normalizes the sum to [0, 100].
+func PriorityByPreferredAffinity(ctx context.Context, n *state.Node, vol *v1.AntstorVolume) int {
+	var (
+		matched int64
+		total   int64
+	)
+
+	if vol.Spec.NodeAffinity != nil {
+		terms := vol.Spec.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		matched += scorePreferredTerms(terms, filter.ConvertNodeInfo(n.Info))
+		total += sumTermWeights(terms)
+	}
+
+	if vol.Spec.PoolAffinity != nil {
+		terms := vol.Spec.PoolAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		matched += scorePreferredTerms(terms, filter.ConvertPoolLabels(n.Pool.Labels))
+		total += sumTermWeights(terms)
+	}
+
+	if total <= 0 {
+		return 0
+	}
+
+	return int(matched * 100 / total)
+}
+
+func scorePreferredTerms(terms []corev1.PreferredSchedulingTerm, node *corev1.Node) int64 {
+	if len(terms) == 0 {
+		return 0
+	}
+
+	preferred, err := nodeaffinity.NewPreferredSchedulingTerms(terms)
+	if err != nil {
+		klog.Errorf("invalid PreferredSchedulingTerms %+v, %+v", terms, err)
+		return 0
+	}
+
+	return preferred.Score(node)
+}
+
+func sumTermWeights(terms []corev1.PreferredSchedulingTerm) (sum int64) {
+	for _, item := range terms {
+		// weight of 0 is ignored in Score()
+		if item.Weight > 0 {
+			sum += int64(item.Weight)
+		}
+	}
+	return
+}
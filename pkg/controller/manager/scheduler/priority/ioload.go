@@ -0,0 +1,73 @@
+package priority
+
+import (
+	"context"
+	"time"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+// PriorityByLeastIOLoad is a PriorityFunc. Nodes with less IO load are more prefered.
+// IOPS, bandwidth and queue time are scaled by the ceilings in config, or the max values among all nodes if ceilings are not set.
+// Pools without IO stats or with expired IO stats are regarded as idle.
+func PriorityByLeastIOLoad(ctx context.Context, n *state.Node, vol *v1.AntstorVolume) int {
+	var (
+		now      = time.Now()
+		stats    = n.Pool.FreshIOStats(now)
+		cfg, _   = ctx.Value(CtxKeySchedConfig).(config.SchedulerConfig)
+		nodes, _ = ctx.Value(CtxKeyAllNodes).([]*state.Node)
+		limit    = cfg.IOLoad
+	)
+	if stats == nil {
+		return 100
+	}
+	if len(nodes) == 0 {
+		nodes = []*state.Node{n}
+	}
+
+	var maxIOPS, maxBps, maxQueueTime = limit.MaxIOPS, limit.MaxBps, limit.MaxQueueTimeUs
+	for _, node := range nodes {
+		if node.Pool == nil {
+			continue
+		}
+		item := node.Pool.FreshIOStats(now)
+		if item == nil {
+			continue
+		}
+		if limit.MaxIOPS <= 0 && item.IOPS() > maxIOPS {
+			maxIOPS = item.IOPS()
+		}
+		if limit.MaxBps <= 0 && item.Bps() > maxBps {
+			maxBps = item.Bps()
+		}
+		if limit.MaxQueueTimeUs <= 0 && item.QueueTimeUs > maxQueueTime {
+			maxQueueTime = item.QueueTimeUs
+		}
+	}
+
+	var load, cnt int
+	for _, item := range []struct {
+		val, max int64
+	}{
+		{val: stats.IOPS(), max: maxIOPS},
+		{val: stats.Bps(), max: maxBps},
+		{val: stats.QueueTimeUs, max: maxQueueTime},
+	} {
+		if item.max <= 0 {
+			continue
+		}
+		ratio := int(item.val * 100 / item.max)
+		if ratio > 100 {
+			ratio = 100
+		}
+		load += ratio
+		cnt++
+	}
+	if cnt == 0 {
+		return 100
+	}
+
+	return 100 - load/cnt
+}
@@ -10,6 +10,16 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// ctxKey is the type of context keys of PriorityFuncs, to avoid colliding with keys of other packages
+type ctxKey string
+
+const (
+	// CtxKeySchedConfig is the context key of SchedulerConfig
+	CtxKeySchedConfig ctxKey = "schedConfig"
+	// CtxKeyAllNodes is the context key of all nodes in state, including the filtered out ones
+	CtxKeyAllNodes ctxKey = "allNodes"
+)
+
 type PriorityResult struct {
 	NodeID string
 	Score  int
@@ -30,16 +40,19 @@ func (p PriorityResultList) Swap(i, j int) {
 type PriorityFunc func(context.Context, *state.Node, *v1.AntstorVolume) int
 
 type PriorityCalculator struct {
-	nodes []*state.Node
-	vol   *v1.AntstorVolume
-	funcs []PriorityFunc
-	ctx   context.Context
-	cfg   config.SchedulerConfig
+	nodes   []*state.Node
+	vol     *v1.AntstorVolume
+	funcs   []PriorityFunc
+	weights []int
+	// normalize scales scores of each PriorityFunc to [0, 100]
+	normalize bool
+	ctx       context.Context
+	cfg       config.SchedulerConfig
 }
 
 func NewPriorityCalculator(cfg config.SchedulerConfig) *PriorityCalculator {
 	return &PriorityCalculator{
-		ctx: context.Background(),
+		ctx: context.WithValue(context.Background(), CtxKeySchedConfig, cfg),
 		cfg: cfg,
 	}
 }
@@ -65,8 +78,34 @@ func (pc *PriorityCalculator) LoadPriorityFromConfig() *PriorityCalculator {
 	return pc
 }
 
+// LoadPriorityFromProfile loads weighted priorities and normalization option from the profile
+func (pc *PriorityCalculator) LoadPriorityFromProfile(profile config.SchedulerProfile) *PriorityCalculator {
+	for _, item := range profile.Priorities {
+		fn, err := GetPriorityByName(item.Name)
+		if err != nil {
+			klog.Error(err)
+			continue
+		} else {
+			klog.Infof("use priority %s, weight %d", item.Name, item.Weight)
+			pc.AddWeightedPriorityFunc(fn, item.Weight)
+		}
+	}
+	pc.normalize = profile.NormalizeScore
+
+	return pc
+}
+
 func (pc *PriorityCalculator) AddPriorityFunc(f PriorityFunc) *PriorityCalculator {
+	return pc.AddWeightedPriorityFunc(f, 1)
+}
+
+// AddWeightedPriorityFunc adds PriorityFunc with weight. Weight less than 1 is regarded as 1.
+func (pc *PriorityCalculator) AddWeightedPriorityFunc(f PriorityFunc, weight int) *PriorityCalculator {
+	if weight <= 0 {
+		weight = 1
+	}
 	pc.funcs = append(pc.funcs, f)
+	pc.weights = append(pc.weights, weight)
 	return pc
 }
 
@@ -75,28 +114,47 @@ func (pc *PriorityCalculator) WithContextValue(key, val interface{}) *PriorityCa
 	return pc
 }
 
-// GetFirstByScore returns the best Node and score int
-func (pc *PriorityCalculator) GetFirstByScore() (*state.Node, int) {
-	if len(pc.nodes) == 0 {
-		return nil, 0
-	}
-
+// Scores returns the final score of each input Node, in the same order of input
+func (pc *PriorityCalculator) Scores() PriorityResultList {
 	if pc.ctx == nil {
 		pc.ctx = context.Background()
 	}
 
-	resultList := make([]PriorityResult, 0, len(pc.nodes))
-	for _, node := range pc.nodes {
-		var result = PriorityResult{
-			NodeID: node.Pool.Spec.NodeInfo.ID,
+	resultList := make(PriorityResultList, len(pc.nodes))
+	for i, node := range pc.nodes {
+		resultList[i].NodeID = node.Pool.Spec.NodeInfo.ID
+	}
+
+	var rawScores = make([]int, len(pc.nodes))
+	for idx, pfunc := range pc.funcs {
+		var maxScore int
+		for i, node := range pc.nodes {
+			rawScores[i] = pfunc(pc.ctx, node, pc.vol)
+			if rawScores[i] > maxScore {
+				maxScore = rawScores[i]
+			}
 		}
-		for _, pfunc := range pc.funcs {
-			result.Score += pfunc(pc.ctx, node, pc.vol)
+
+		for i := range pc.nodes {
+			score := rawScores[i]
+			if pc.normalize {
+				score = normalizeScore(score, maxScore)
+			}
+			resultList[i].Score += score * pc.weights[idx]
 		}
-		resultList = append(resultList, result)
 	}
 
-	sort.Sort(sort.Reverse(PriorityResultList(resultList)))
+	return resultList
+}
+
+// GetFirstByScore returns the best Node and score int
+func (pc *PriorityCalculator) GetFirstByScore() (*state.Node, int) {
+	if len(pc.nodes) == 0 {
+		return nil, 0
+	}
+
+	resultList := pc.Scores()
+	sort.Sort(sort.Reverse(resultList))
 
 	score := resultList[0].Score
 	nodeID := resultList[0].NodeID
@@ -108,3 +166,11 @@ func (pc *PriorityCalculator) GetFirstByScore() (*state.Node, int) {
 
 	return nil, 0
 }
+
+// normalizeScore scales score to [0, 100] by the max score
+func normalizeScore(score, maxScore int) int {
+	if maxScore <= 0 || score <= 0 {
+		return 0
+	}
+	return score * 100 / maxScore
+}
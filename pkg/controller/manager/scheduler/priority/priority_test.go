@@ -1,9 +1,18 @@
 package priority
 
 import (
+	"context"
 	"sort"
 	"strconv"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/state"
 )
 
 func TestPriorityList(t *testing.T) {
@@ -17,3 +26,87 @@ func TestPriorityList(t *testing.T) {
 
 	t.Logf("%+v", list)
 }
+
+func TestWeightedPriority(t *testing.T) {
+	var nodes = []*state.Node{
+		state.NewNode(&v1.StoragePool{Spec: v1.StoragePoolSpec{NodeInfo: v1.NodeInfo{ID: "node-1"}}}),
+		state.NewNode(&v1.StoragePool{Spec: v1.StoragePoolSpec{NodeInfo: v1.NodeInfo{ID: "node-2"}}}),
+	}
+	// node-1 is slightly better at big
+	var bigFn = func(ctx context.Context, n *state.Node, vol *v1.AntstorVolume) int {
+		if n.Info.ID == "node-1" {
+			return 90
+		}
+		return 80
+	}
+	// node-2 is much better at small
+	var smallFn = func(ctx context.Context, n *state.Node, vol *v1.AntstorVolume) int {
+		if n.Info.ID == "node-2" {
+			return 5
+		}
+		return 0
+	}
+	var vol = &v1.AntstorVolume{}
+
+	// raw scores are summed up
+	node, score := NewPriorityCalculator(config.SchedulerConfig{}).
+		Input(nodes, vol).
+		AddPriorityFunc(bigFn).
+		AddPriorityFunc(smallFn).
+		GetFirstByScore()
+	assert.Equal(t, "node-1", node.Info.ID)
+	assert.Equal(t, 90, score)
+
+	// normalized scores with weights
+	node, score = NewPriorityCalculator(config.SchedulerConfig{}).
+		Input(nodes, vol).
+		LoadPriorityFromProfile(config.SchedulerProfile{NormalizeScore: true}).
+		AddWeightedPriorityFunc(bigFn, 1).
+		AddWeightedPriorityFunc(smallFn, 2).
+		GetFirstByScore()
+	assert.Equal(t, "node-2", node.Info.ID)
+	assert.Equal(t, 88+200, score)
+}
+
+func TestLeastIOLoad(t *testing.T) {
+	var newNode = func(id string, stats *v1.PoolIOStats) *state.Node {
+		pool := &v1.StoragePool{Spec: v1.StoragePoolSpec{NodeInfo: v1.NodeInfo{ID: id}}}
+		if stats != nil {
+			stats.UpdateTime = metav1.Now()
+		}
+		pool.Status.IOStats = stats
+		return state.NewNode(pool)
+	}
+	var nodes = []*state.Node{
+		newNode("node-1", &v1.PoolIOStats{ReadIOPS: 1000, WriteIOPS: 1000, ReadBps: 100 << 20, QueueTimeUs: 200}),
+		newNode("node-2", &v1.PoolIOStats{ReadIOPS: 500, ReadBps: 50 << 20, QueueTimeUs: 100}),
+		newNode("node-3", nil),
+	}
+	var vol = &v1.AntstorVolume{}
+
+	ctx := context.WithValue(context.Background(), CtxKeyAllNodes, nodes)
+	assert.Equal(t, 0, PriorityByLeastIOLoad(ctx, nodes[0], vol))
+	// (25 + 50 + 50) / 3 = 41
+	assert.Equal(t, 59, PriorityByLeastIOLoad(ctx, nodes[1], vol))
+	assert.Equal(t, 100, PriorityByLeastIOLoad(ctx, nodes[2], vol))
+
+	// IOPS is scaled by ceiling, others are scaled by max values. (50 + 100 + 100) / 3 = 83
+	ctx = context.WithValue(ctx, CtxKeySchedConfig, config.SchedulerConfig{
+		IOLoad: config.IOLoadConfig{MaxIOPS: 4000},
+	})
+	assert.Equal(t, 17, PriorityByLeastIOLoad(ctx, nodes[0], vol))
+
+	node, _ := NewPriorityCalculator(config.SchedulerConfig{}).
+		Input(nodes[:2], vol).
+		WithContextValue(CtxKeyAllNodes, nodes).
+		AddPriorityFunc(PriorityByLeastIOLoad).
+		GetFirstByScore()
+	assert.Equal(t, "node-2", node.Info.ID)
+
+	// expired stats are ignored. IOPS of node-1 is the max value.
+	nodes[1].Pool.Status.IOStats.UpdateTime = metav1.NewTime(time.Now().Add(-v1.PoolIOStatsExpiration - time.Minute))
+	ctx = context.WithValue(context.Background(), CtxKeyAllNodes, nodes)
+	assert.Equal(t, 100, PriorityByLeastIOLoad(ctx, nodes[1], vol))
+	nodes[0].Pool.Status.IOStats.ReadIOPS = 100
+	assert.Equal(t, 0, PriorityByLeastIOLoad(ctx, nodes[0], vol))
+}
@@ -14,6 +14,9 @@ var (
 func init() {
 	RegisterPriorityFunc("LeastResource", PriorityByLeastResource)
 	RegisterPriorityFunc("PositionAdvice", PriorityByPositionAdivce)
+	RegisterPriorityFunc("DataHolderSpread", PriorityByDataHolderSpread)
+	RegisterPriorityFunc("PreferredAffinity", PriorityByPreferredAffinity)
+	RegisterPriorityFunc("LeastIOLoad", PriorityByLeastIOLoad)
 }
 
 func RegisterPriorityFunc(name string, filter PriorityFunc) {
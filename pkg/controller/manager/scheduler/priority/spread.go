@@ -0,0 +1,51 @@
+package priority
+
+import (
+	"context"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/scheduler/filter"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+var (
+	// penalty of sharing the same failure domain with a volume of the same data-holder
+	spreadPenalties = []struct {
+		level   config.SpreadLevel
+		penalty int
+	}{
+		{level: config.SpreadLevelNode, penalty: 50},
+		{level: config.SpreadLevelRack, penalty: 30},
+		{level: config.SpreadLevelRoom, penalty: 20},
+	}
+)
+
+// PriorityByDataHolderSpread is a PriorityFunc. Nodes in failure domains without volumes of the same data-holder are more prefered.
+func PriorityByDataHolderSpread(ctx context.Context, n *state.Node, vol *v1.AntstorVolume) int {
+	if vol.Labels[v1.VolumeDataHolderKey] == "" {
+		return 0
+	}
+
+	var (
+		score    = 100
+		cfg, _   = ctx.Value(CtxKeySchedConfig).(config.SchedulerConfig)
+		nodes, _ = ctx.Value(CtxKeyAllNodes).([]*state.Node)
+		spread   = cfg.DataHolderSpread
+	)
+	if len(nodes) == 0 {
+		nodes = []*state.Node{n}
+	}
+
+	for _, item := range spreadPenalties {
+		domain := filter.FailureDomainOfLevel(n, item.level, spread)
+		for _, node := range nodes {
+			if filter.FailureDomainOfLevel(node, item.level, spread) == domain && filter.CountDataHolderVolumes(node, vol) > 0 {
+				score -= item.penalty
+				break
+			}
+		}
+	}
+
+	return score
+}
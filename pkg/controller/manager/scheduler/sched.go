@@ -14,6 +14,13 @@ import (
 type SchedulerIface interface {
 	ScheduleVolume(allNodes []*state.Node, vol *v1.AntstorVolume) (node v1.NodeInfo, err error)
 	ScheduleVolumeGroup(allNodes []*state.Node, volGroup *v1.AntstorVolumeGroup) (err error)
+	// ExplainVolume is a dry-run of ScheduleVolume. It returns results of each pool.
+	ExplainVolume(allNodes []*state.Node, vol *v1.AntstorVolume) (result ExplainResult)
+	// Preempt finds reservations and volumes with lower priority, whose eviction makes the volume schedulable.
+	// It returns nil if preemption does not help.
+	Preempt(allNodes []*state.Node, vol *v1.AntstorVolume) (p *Preemption)
+	// UpdateConfig replaces the config. Volumes being scheduled are not affected.
+	UpdateConfig(cfg config.Config)
 }
 
 type scheduler struct {
@@ -29,6 +36,13 @@ func NewScheduler(cfg config.Config) SchedulerIface {
 	}
 }
 
+// UpdateConfig implements config.Reloadable
+func (s *scheduler) UpdateConfig(cfg config.Config) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.cfg = cfg
+}
+
 // ScheduleVolume return error if there is no StoragePool available
 func (s *scheduler) ScheduleVolume(allNodes []*state.Node, vol *v1.AntstorVolume) (node v1.NodeInfo, err error) {
 	// schedule volume one by one
@@ -62,13 +76,39 @@ func (s *scheduler) ScheduleVolume(allNodes []*state.Node, vol *v1.AntstorVolume
 	return
 }
 
-func (s *scheduler) sched(nodes []*state.Node, vol *v1.AntstorVolume) (node *state.Node, err error) {
-	nodes, err = predicate(nodes, vol, s.cfg.Scheduler)
+func (s *scheduler) sched(allNodes []*state.Node, vol *v1.AntstorVolume) (node *state.Node, err error) {
+	profile, err := s.profileOf(allNodes, vol.Name, vol.Annotations)
+	if err != nil {
+		return
+	}
+	var cfg = s.cfg.Scheduler
+	cfg.Filters = profile.Filters
+
+	nodes, err := predicate(allNodes, vol, cfg)
 	if err != nil {
 		return
 	}
 
-	node = byPriority(nodes, vol, s.cfg.Scheduler)
+	node = byPriority(allNodes, nodes, vol, cfg, profile)
+
+	return
+}
+
+// profileOf returns the scheduler profile specified by annotation. Default profile is returned if the annotation is missing.
+// If the profile is not found, all pools are filtered out by ReasonProfileNotFound, so the failure is recorded like other scheduling failures.
+func (s *scheduler) profileOf(allNodes []*state.Node, name string, annotations map[string]string) (profile config.SchedulerProfile, err error) {
+	var profileName = annotations[v1.SchedulerProfileAnnoKey]
+
+	profile, err = s.cfg.Scheduler.GetProfile(profileName)
+	if err != nil {
+		klog.Errorf("%s: %+v", name, err)
+		mergedErr := filter.NewMergedError()
+		for range allNodes {
+			mergedErr.AddReason(filter.ReasonProfileNotFound)
+		}
+		mergedErr.SetTotal(len(allNodes))
+		err = mergedErr
+	}
 
 	return
 }
@@ -82,16 +122,17 @@ func predicate(nodes []*state.Node, vol *v1.AntstorVolume, cfg config.SchedulerC
 	return
 }
 
-func byPriority(nodes []*state.Node, vol *v1.AntstorVolume, cfg config.SchedulerConfig) (node *state.Node) {
+func byPriority(allNodes, nodes []*state.Node, vol *v1.AntstorVolume, cfg config.SchedulerConfig, profile config.SchedulerProfile) (node *state.Node) {
 	if len(nodes) == 0 || vol == nil {
 		return
 	}
 
 	node, _ = priority.NewPriorityCalculator(cfg).
 		Input(nodes, vol).
+		WithContextValue(priority.CtxKeyAllNodes, allNodes).
 		// AddPriorityFunc(priority.PriorityByPositionAdivce).
 		// AddPriorityFunc(priority.PriorityByLeastResource).
-		LoadPriorityFromConfig().
+		LoadPriorityFromProfile(profile).
 		GetFirstByScore()
 
 	return
@@ -2,9 +2,11 @@ package scheduler
 
 import (
 	"testing"
+	"time"
 
 	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
 	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/scheduler/filter"
 	"lite.io/liteio/pkg/controller/manager/state"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -255,6 +257,275 @@ func TestSchedVolGroup(t *testing.T) {
 
 }
 
+func TestSchedDataHolderSpread(t *testing.T) {
+	var tenGiB uint64 = 10 << 30
+	memState := state.NewState()
+	cfg := config.Config{
+		Scheduler: config.SchedulerConfig{
+			MaxRemoteVolumeCount: 3,
+			Filters:              []string{"Basic", "Affinity", "DataHolderSpread"},
+			Priorities:           []string{"LeastResource", "DataHolderSpread"},
+			DataHolderSpread: config.DataHolderSpreadConfig{
+				Level: config.SpreadLevelRack,
+			},
+		},
+	}
+	config.SetDefaults(&cfg)
+	sched := NewScheduler(cfg)
+
+	for _, item := range []struct {
+		nodeID string
+		rack   string
+	}{
+		{nodeID: "node-1", rack: "rack-a"},
+		{nodeID: "node-2", rack: "rack-a"},
+		{nodeID: "node-3", rack: "rack-b"},
+	} {
+		pool := newReadyStoragePool(item.nodeID, tenGiB)
+		pool.Spec.NodeInfo.Labels[cfg.Scheduler.DataHolderSpread.NodeKeys.RackLabelKey] = item.rack
+		memState.SetStoragePool(pool)
+	}
+
+	err := memState.BindAntstorVolume("node-1", newVolume("vol-1", tenGiB/10))
+	assert.NoError(t, err)
+
+	// node-2 is in the same rack with node-1
+	vol := newVolume("vol-2", tenGiB/10)
+	targetNode, err := sched.ScheduleVolume(memState.GetAllNodes(), vol)
+	assert.NoError(t, err)
+	assert.Equal(t, "node-3", targetNode.ID)
+	err = memState.BindAntstorVolume(targetNode.ID, vol)
+	assert.NoError(t, err)
+
+	// all racks are occupied by the data-holder
+	vol = newVolume("vol-3", tenGiB/10)
+	_, err = sched.ScheduleVolume(memState.GetAllNodes(), vol)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), filter.ReasonDataHolderSpread)
+
+	// volume of another data-holder is not affected
+	vol.Labels[v1.VolumeDataHolderKey] = "ob.clusterName.zoneName_02"
+	_, err = sched.ScheduleVolume(memState.GetAllNodes(), vol)
+	assert.NoError(t, err)
+}
+
+func TestSchedPreferredAffinity(t *testing.T) {
+	var tenGiB uint64 = 10 << 30
+	memState := state.NewState()
+	sched := NewScheduler(
+		config.Config{
+			Scheduler: config.SchedulerConfig{
+				MaxRemoteVolumeCount: 3,
+				Filters:              []string{"Basic", "Affinity"},
+				Priorities:           []string{"LeastResource", "PreferredAffinity"},
+			},
+		})
+
+	for _, item := range []struct {
+		nodeID string
+		media  string
+	}{
+		{nodeID: "node-1", media: "ssd"},
+		{nodeID: "node-2", media: "nvme"},
+		{nodeID: "node-3", media: "ssd"},
+	} {
+		pool := newReadyStoragePool(item.nodeID, tenGiB)
+		pool.Labels["media"] = item.media
+		memState.SetStoragePool(pool)
+	}
+
+	vol := newVolume("vol-1", tenGiB/10)
+	vol.Spec.PoolAffinity = &corev1.NodeAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{
+			{
+				Weight: 80,
+				Preference: corev1.NodeSelectorTerm{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{
+							Key:      "media",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"nvme"},
+						},
+					},
+				},
+			},
+			{
+				Weight: 20,
+				Preference: corev1.NodeSelectorTerm{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{
+							Key:      "media",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"ssd"},
+						},
+					},
+				},
+			},
+		},
+	}
+	targetNode, err := sched.ScheduleVolume(memState.GetAllNodes(), vol)
+	assert.NoError(t, err)
+	assert.Equal(t, "node-2", targetNode.ID)
+
+	// fall back to ssd pools if nvme pool is unschedulable
+	pool, err := memState.GetStoragePoolByNodeID("node-2")
+	assert.NoError(t, err)
+	pool.Status.Status = v1.PoolStatusLocked
+	targetNode, err = sched.ScheduleVolume(memState.GetAllNodes(), vol)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "node-2", targetNode.ID)
+}
+
+func TestExplainVolume(t *testing.T) {
+	var tenGiB uint64 = 10 << 30
+	memState := state.NewState()
+	sched := NewScheduler(
+		config.Config{
+			Scheduler: config.SchedulerConfig{
+				MaxRemoteVolumeCount: 3,
+				Filters:              []string{"Basic", "Affinity"},
+				Priorities:           []string{"LeastResource"},
+			},
+		})
+
+	for _, nodeID := range []string{"node-1", "node-2", "node-3"} {
+		pool := newReadyStoragePool(nodeID, tenGiB)
+		pool.Spec.NodeInfo.Labels["rack"] = nodeID
+		memState.SetStoragePool(pool)
+	}
+	pool, err := memState.GetStoragePoolByNodeID("node-3")
+	assert.NoError(t, err)
+	pool.Status.Status = v1.PoolStatusLocked
+
+	req := ExplainRequest{
+		Size: "1Gi",
+		Annotations: map[string]string{
+			v1.NodeLabelSelectorKey: "rack!=node-2",
+		},
+	}
+	vol, err := req.ToVolume(memState.GetAllNodes())
+	assert.NoError(t, err)
+
+	result := sched.ExplainVolume(memState.GetAllNodes(), vol)
+	t.Logf("%+v", result)
+	assert.Equal(t, "node-1", result.SelectedNode)
+	assert.Len(t, result.Pools, 3)
+	assert.True(t, result.Pools[0].Passed)
+	assert.NotNil(t, result.Pools[0].Score)
+	assert.Equal(t, []string{filter.ReasonNodeAffinity}, result.Pools[1].Reasons)
+	assert.Equal(t, []string{filter.ReasonPoolUnschedulable}, result.Pools[2].Reasons)
+	assert.Nil(t, result.Pools[2].Score)
+
+	// nothing is bound to the state
+	node, err := memState.GetNodeByNodeID("node-1")
+	assert.NoError(t, err)
+	assert.Len(t, node.Volumes, 0)
+
+	// no pool has enough space
+	req.Size = "100Gi"
+	vol, err = req.ToVolume(memState.GetAllNodes())
+	assert.NoError(t, err)
+	result = sched.ExplainVolume(memState.GetAllNodes(), vol)
+	assert.Empty(t, result.SelectedNode)
+	assert.Equal(t, "0/3 pools available: 2 insufficient space, 1 pool unschedulable", result.Message)
+	assert.Equal(t, 2, result.Reasons[filter.ReasonPoolFreeSize])
+	assert.Equal(t, 1, result.Reasons[filter.ReasonPoolUnschedulable])
+
+	_, err = ExplainRequest{Size: "abc"}.ToVolume(nil)
+	assert.Error(t, err)
+}
+
+func TestSchedProfileNotFound(t *testing.T) {
+	var tenGiB uint64 = 10 << 30
+	memState := state.NewState()
+	sched := NewScheduler(
+		config.Config{
+			Scheduler: config.SchedulerConfig{
+				MaxRemoteVolumeCount: 3,
+				Filters:              []string{"Basic"},
+				Priorities:           []string{"LeastResource"},
+			},
+		})
+	for _, nodeID := range []string{"node-1", "node-2"} {
+		memState.SetStoragePool(newReadyStoragePool(nodeID, tenGiB))
+	}
+
+	// unknown profile fails scheduling instead of falling back to the default profile
+	vol := newVolume("vol-1", tenGiB/10)
+	vol.Annotations = map[string]string{v1.SchedulerProfileAnnoKey: "not-exist"}
+	_, err := sched.ScheduleVolume(memState.GetAllNodes(), vol)
+	assert.True(t, filter.IsNoStoragePoolAvailable(err))
+	mergedErr, ok := err.(*filter.MergedError)
+	assert.True(t, ok)
+	assert.Equal(t, "0/2 pools available: 2 scheduler profile not found", mergedErr.Summary())
+	assert.Nil(t, sched.Preempt(memState.GetAllNodes(), vol))
+
+	result := sched.ExplainVolume(memState.GetAllNodes(), vol)
+	assert.Empty(t, result.SelectedNode)
+	assert.Equal(t, 2, result.Reasons[filter.ReasonProfileNotFound])
+}
+
+func TestSchedIOLoad(t *testing.T) {
+	var tenGiB uint64 = 10 << 30
+	memState := state.NewState()
+	sched := NewScheduler(
+		config.Config{
+			Scheduler: config.SchedulerConfig{
+				MaxRemoteVolumeCount: 3,
+				Filters:              []string{"Basic", "Affinity", "IOLoad"},
+				Priorities:           []string{"LeastIOLoad"},
+				IOLoad: config.IOLoadConfig{
+					MaxIOPS: 10000,
+				},
+			},
+		})
+
+	for _, item := range []struct {
+		nodeID string
+		iops   int64
+	}{
+		{nodeID: "node-1", iops: 20000},
+		{nodeID: "node-2", iops: 8000},
+		{nodeID: "node-3", iops: 2000},
+	} {
+		pool := newReadyStoragePool(item.nodeID, tenGiB)
+		pool.Status.IOStats = &v1.PoolIOStats{ReadIOPS: item.iops, UpdateTime: metav1.Now()}
+		pool.Spec.NodeInfo.Labels["kubernetes.io/hostname"] = item.nodeID
+		memState.SetStoragePool(pool)
+	}
+
+	// remote volume goes to the least loaded pool
+	vol := newVolume("vol-1", tenGiB/10)
+	vol.Spec.HostNode.ID = "node-4"
+	targetNode, err := sched.ScheduleVolume(memState.GetAllNodes(), vol)
+	assert.NoError(t, err)
+	assert.Equal(t, "node-3", targetNode.ID)
+
+	// hot pool is filtered out for remote volume
+	vol = newVolume("vol-2", tenGiB/10)
+	vol.Spec.HostNode.ID = "node-4"
+	vol.Annotations = map[string]string{v1.NodeLabelSelectorKey: "kubernetes.io/hostname in (node-1)"}
+	_, err = sched.ScheduleVolume(memState.GetAllNodes(), vol)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), filter.ReasonIOLoad)
+
+	// expired stats are ignored
+	hotPool, err := memState.GetStoragePoolByNodeID("node-1")
+	assert.NoError(t, err)
+	hotPool.Status.IOStats.UpdateTime = metav1.NewTime(time.Now().Add(-v1.PoolIOStatsExpiration - time.Minute))
+	targetNode, err = sched.ScheduleVolume(memState.GetAllNodes(), vol)
+	assert.NoError(t, err)
+	assert.Equal(t, "node-1", targetNode.ID)
+	hotPool.Status.IOStats.UpdateTime = metav1.Now()
+
+	// local volume is not affected
+	vol = newVolume("vol-3", tenGiB/10)
+	vol.Spec.PositionAdvice = v1.MustLocal
+	targetNode, err = sched.ScheduleVolume(memState.GetAllNodes(), vol)
+	assert.NoError(t, err)
+	assert.Equal(t, "node-1", targetNode.ID)
+}
+
 func newStoragePool(nodeID string, size uint64) (pool *v1.StoragePool) {
 	pool = &v1.StoragePool{
 		ObjectMeta: metav1.ObjectMeta{
@@ -290,6 +561,13 @@ func newStoragePool(nodeID string, size uint64) (pool *v1.StoragePool) {
 	return
 }
 
+// newReadyStoragePool returns a pool whose VG is all free
+func newReadyStoragePool(nodeID string, size uint64) (pool *v1.StoragePool) {
+	pool = newStoragePool(nodeID, size)
+	pool.Status.VGFreeSize = *resource.NewQuantity(int64(size), resource.BinarySI)
+	return
+}
+
 func newVolume(name string, size uint64) *v1.AntstorVolume {
 	vol := &v1.AntstorVolume{
 		ObjectMeta: metav1.ObjectMeta{
@@ -310,3 +588,32 @@ func newVolume(name string, size uint64) *v1.AntstorVolume {
 	}
 	return vol
 }
+
+func TestValidateConfig(t *testing.T) {
+	var cfg = config.Config{
+		Scheduler: config.SchedulerConfig{
+			Filters:    []string{"Basic", "Affinity"},
+			Priorities: []string{"LeastResource"},
+			Profiles: []config.SchedulerProfile{
+				{Name: "p1", Filters: []string{"Basic"}, Priorities: []config.WeightedPriority{{Name: "LeastResource", Weight: 2}}},
+			},
+		},
+	}
+	assert.NoError(t, ValidateConfig(cfg))
+
+	invalid := cfg
+	invalid.Scheduler.Filters = []string{"Basic", "Unknown"}
+	assert.Error(t, ValidateConfig(invalid))
+
+	invalid = cfg
+	invalid.Scheduler.Priorities = []string{"Unknown"}
+	assert.Error(t, ValidateConfig(invalid))
+
+	invalid = cfg
+	invalid.Scheduler.Profiles = []config.SchedulerProfile{{Name: "p1"}, {Name: "p1"}}
+	assert.Error(t, ValidateConfig(invalid))
+
+	invalid = cfg
+	invalid.Scheduler.Profiles = []config.SchedulerProfile{{Name: "p1", Filters: []string{"Unknown"}}}
+	assert.Error(t, ValidateConfig(invalid))
+}
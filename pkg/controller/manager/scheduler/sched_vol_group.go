@@ -236,8 +236,15 @@ func (s *scheduler) filterNodes(allNodes []*state.Node, volGroup *v1.AntstorVolu
 		}
 	)
 
+	profile, err := s.profileOf(allNodes, volGroup.Name, volGroup.Annotations)
+	if err != nil {
+		return
+	}
+	var cfg = s.cfg.Scheduler
+	cfg.Filters = profile.Filters
+
 	// filter out unqualified nodes
-	qualified, err = filter.NewFilterChain(s.cfg.Scheduler).
+	qualified, err = filter.NewFilterChain(cfg).
 		Filter(func(ctx *filter.FilterContext, node *state.Node, vol *v1.AntstorVolume) bool {
 			// filter empty node
 			if !volGroup.Spec.Stragety.AllowEmptyNode {
@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/scheduler/filter"
+	"lite.io/liteio/pkg/controller/manager/scheduler/priority"
+)
+
+// ValidateConfig checks the scheduler section of config. Unknown filter or priority names and invalid profiles are errors.
+func ValidateConfig(cfg config.Config) (err error) {
+	var sc = cfg.Scheduler
+	if err = validateFilters(sc.Filters); err != nil {
+		return
+	}
+	for _, name := range sc.Priorities {
+		if _, err = priority.GetPriorityByName(name); err != nil {
+			return
+		}
+	}
+
+	var names = make(map[string]bool, len(sc.Profiles))
+	for _, profile := range sc.Profiles {
+		if profile.Name == "" {
+			return fmt.Errorf("name of scheduler profile is empty")
+		}
+		if names[profile.Name] {
+			return fmt.Errorf("duplicate scheduler profile %s", profile.Name)
+		}
+		names[profile.Name] = true
+
+		if err = validateFilters(profile.Filters); err != nil {
+			return fmt.Errorf("scheduler profile %s: %w", profile.Name, err)
+		}
+		for _, item := range profile.Priorities {
+			if _, err = priority.GetPriorityByName(item.Name); err != nil {
+				return fmt.Errorf("scheduler profile %s: %w", profile.Name, err)
+			}
+		}
+	}
+
+	return
+}
+
+func validateFilters(names []string) (err error) {
+	for _, name := range names {
+		if _, err = filter.GetFilterByName(name); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,370 @@
+package simulate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/scheduler"
+	"lite.io/liteio/pkg/controller/manager/scheduler/filter"
+	"lite.io/liteio/pkg/controller/manager/state"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+const (
+	// ReasonGroupSizeNotSatisfied means the volume group is scheduled partially, because CountRange.Max is reached
+	ReasonGroupSizeNotSatisfied = "GroupSizeNotSatisfied"
+	// ReasonError is the reason of errors other than filter failures
+	ReasonError = "Error"
+
+	volumeNamePrefix = "sim-"
+)
+
+var errGroupSizeNotSatisfied = errors.New(ReasonGroupSizeNotSatisfied)
+
+// Workload is a stream of synthetic requests, which are replayed in order
+type Workload struct {
+	Requests []Request `json:"requests"`
+}
+
+// Request is a template of volume requests
+type Request struct {
+	// Count of volumes created by the template. Default is 1.
+	Count int `json:"count,omitempty"`
+	// Size of volume, or total size of volume group, e.g. 100Gi
+	Size string `json:"size"`
+	// Type of volume. Default is Flexible.
+	Type           v1.VolumeType     `json:"type,omitempty"`
+	IsThin         bool              `json:"isThin,omitempty"`
+	PositionAdvice v1.VolumePosition `json:"positionAdvice,omitempty"`
+	Priority       int32             `json:"priority,omitempty"`
+	// HostNodes are the candidates of node where the consumer pod runs. A random pool is picked if it is empty.
+	HostNodes   []string          `json:"hostNodes,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// VolumeGroup is set if the request is scheduled as an AntstorVolumeGroup
+	VolumeGroup *VolumeGroupRequest `json:"volumeGroup,omitempty"`
+}
+
+type VolumeGroupRequest struct {
+	// MaxVolumes is the max count of volumes in the group. Default is 1.
+	MaxVolumes int `json:"maxVolumes,omitempty"`
+	// MinVolumeSize is the min size of each volume. Default is 1Gi.
+	MinVolumeSize string `json:"minVolumeSize,omitempty"`
+	// MaxVolumeSize is the max size of each volume. Default is the size of request.
+	MaxVolumeSize  string `json:"maxVolumeSize,omitempty"`
+	AllowEmptyNode bool   `json:"allowEmptyNode,omitempty"`
+}
+
+// sizeRange returns the range of volume size in the group
+func (g *VolumeGroupRequest) sizeRange(size resource.Quantity) (min, max resource.Quantity, err error) {
+	min = resource.MustParse("1Gi")
+	max = size.DeepCopy()
+	if g.MinVolumeSize != "" {
+		if min, err = resource.ParseQuantity(g.MinVolumeSize); err != nil {
+			return
+		}
+	}
+	if g.MaxVolumeSize != "" {
+		max, err = resource.ParseQuantity(g.MaxVolumeSize)
+	}
+	return
+}
+
+// Report is the result of simulation
+type Report struct {
+	Requests  int `json:"requests"`
+	Scheduled int `json:"scheduled"`
+	Failed    int `json:"failed"`
+	// FailureReasons counts failed requests by reason. A request is counted once for each reason it failed with.
+	FailureReasons map[string]int `json:"failureReasons"`
+	Fragmentation  Fragmentation  `json:"fragmentation"`
+	Pools          []PoolReport   `json:"pools"`
+}
+
+// Fragmentation describes how the free space is scattered among pools
+type Fragmentation struct {
+	TotalFree   int64 `json:"totalFree"`
+	MaxPoolFree int64 `json:"maxPoolFree"`
+	// Ratio is 1 - MaxPoolFree/TotalFree. 0 means all free space is on one pool.
+	Ratio float64 `json:"ratio"`
+	// StrandedFree is the free space on pools which cannot hold the smallest request
+	StrandedFree int64 `json:"strandedFree"`
+}
+
+// PoolReport is the final usage of a pool
+type PoolReport struct {
+	Name      string `json:"name"`
+	Capacity  int64  `json:"capacity"`
+	Allocated int64  `json:"allocated"`
+	Reserved  int64  `json:"reserved"`
+	Free      int64  `json:"free"`
+	// Utilization is the percentage of used space
+	Utilization   float64 `json:"utilization"`
+	Volumes       int     `json:"volumes"`
+	RemoteVolumes int     `json:"remoteVolumes"`
+}
+
+// LoadWorkload reads Workload in YAML or JSON
+func LoadWorkload(r io.Reader) (w Workload, err error) {
+	err = yaml.NewYAMLOrJSONDecoder(r, 4096).Decode(&w)
+	return
+}
+
+// Simulator replays requests against an in-memory State with a real scheduler
+type Simulator struct {
+	state state.StateIface
+	sched scheduler.SchedulerIface
+	rand  *rand.Rand
+}
+
+func NewSimulator(cfg config.Config, s state.StateIface, seed int64) *Simulator {
+	return &Simulator{
+		state: s,
+		sched: scheduler.NewScheduler(cfg),
+		rand:  rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Run schedules the requests of workload in order. Scheduled volumes are bound to State,
+// so that later requests see the space consumed by earlier ones.
+func (s *Simulator) Run(w Workload) (report Report, err error) {
+	var minSize int64 = -1
+	report.FailureReasons = make(map[string]int)
+
+	for i, req := range w.Requests {
+		var size resource.Quantity
+		size, err = resource.ParseQuantity(req.Size)
+		if err != nil {
+			err = fmt.Errorf("invalid size %q of request %d: %w", req.Size, i, err)
+			return
+		}
+		if req.Count <= 0 {
+			req.Count = 1
+		}
+
+		var reqMin = size.Value()
+		if req.VolumeGroup != nil {
+			var minQ resource.Quantity
+			if minQ, _, err = req.VolumeGroup.sizeRange(size); err != nil {
+				err = fmt.Errorf("invalid volumeGroup of request %d: %w", i, err)
+				return
+			}
+			reqMin = minQ.Value()
+		}
+		if minSize < 0 || reqMin < minSize {
+			minSize = reqMin
+		}
+
+		for j := 0; j < req.Count; j++ {
+			var (
+				name     = fmt.Sprintf("%s%d-%d", volumeNamePrefix, i, j)
+				errSched error
+			)
+			if req.VolumeGroup != nil {
+				errSched = s.scheduleVolumeGroup(name, req, size)
+			} else {
+				errSched = s.scheduleVolume(name, req, size)
+			}
+
+			report.Requests++
+			if errSched == nil {
+				report.Scheduled++
+				continue
+			}
+			report.Failed++
+			for _, reason := range reasonsOf(errSched) {
+				report.FailureReasons[reason]++
+			}
+		}
+	}
+
+	report.Pools, report.Fragmentation = s.summarize(minSize)
+	return
+}
+
+func (s *Simulator) scheduleVolume(name string, req Request, size resource.Quantity) (err error) {
+	var vol = &v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   v1.DefaultNamespace,
+			Labels:      misc.CopyLabel(req.Labels),
+			Annotations: misc.CopyLabel(req.Annotations),
+		},
+		Spec: v1.AntstorVolumeSpec{
+			Uuid:           name,
+			Type:           req.Type,
+			SizeByte:       uint64(size.Value()),
+			IsThin:         req.IsThin,
+			PositionAdvice: req.PositionAdvice,
+			Priority:       req.Priority,
+			HostNode:       &v1.NodeInfo{ID: s.pickHostNode(req.HostNodes)},
+		},
+	}
+	if vol.Spec.Type == "" {
+		vol.Spec.Type = v1.VolumeTypeFlexible
+	}
+
+	node, err := s.sched.ScheduleVolume(s.allNodes(), vol)
+	if err != nil {
+		return
+	}
+	return s.state.BindAntstorVolume(node.ID, vol)
+}
+
+func (s *Simulator) scheduleVolumeGroup(name string, req Request, size resource.Quantity) (err error) {
+	var group = req.VolumeGroup
+	minQ, maxQ, err := group.sizeRange(size)
+	if err != nil {
+		return
+	}
+	var maxVolumes = group.MaxVolumes
+	if maxVolumes <= 0 {
+		maxVolumes = 1
+	}
+
+	var volGroup = &v1.AntstorVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   v1.DefaultNamespace,
+			Labels:      misc.CopyLabel(req.Labels),
+			Annotations: misc.CopyLabel(req.Annotations),
+		},
+		Spec: v1.AntstorVolumeGroupSpec{
+			Uuid:      name,
+			TotalSize: size.Value(),
+			IsThin:    req.IsThin,
+			DesiredVolumeSpec: v1.DesiredVolumeSpec{
+				CountRange: v1.IntRange{Max: maxVolumes},
+				SizeRange:  v1.QuantityRange{Min: minQ, Max: maxQ},
+			},
+			Stragety: v1.VolumeGroupStrategy{
+				AllowEmptyNode: group.AllowEmptyNode,
+			},
+		},
+	}
+
+	if err = s.sched.ScheduleVolumeGroup(s.allNodes(), volGroup); err != nil {
+		return
+	}
+
+	var scheduled int64
+	for _, item := range volGroup.Spec.Volumes {
+		scheduled += item.Size
+	}
+	if scheduled < volGroup.Spec.TotalSize {
+		return errGroupSizeNotSatisfied
+	}
+
+	for _, item := range volGroup.Spec.Volumes {
+		vol := &v1.AntstorVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      item.VolId.Name,
+				Namespace: item.VolId.Namespace,
+				Labels:    misc.CopyLabel(req.Labels),
+			},
+			Spec: v1.AntstorVolumeSpec{
+				Uuid:     item.VolId.UUID,
+				Type:     v1.VolumeTypeFlexible,
+				SizeByte: uint64(item.Size),
+				IsThin:   req.IsThin,
+				HostNode: &v1.NodeInfo{},
+			},
+		}
+		if err = s.state.BindAntstorVolume(item.TargetNodeName, vol); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// pickHostNode picks one of candidates, or one of all pools if candidates is empty
+func (s *Simulator) pickHostNode(candidates []string) string {
+	if len(candidates) == 0 {
+		for _, node := range s.allNodes() {
+			candidates = append(candidates, node.Info.ID)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[s.rand.Intn(len(candidates))]
+}
+
+// allNodes returns nodes sorted by id, so that the result is reproducible
+func (s *Simulator) allNodes() (nodes []*state.Node) {
+	nodes = s.state.GetAllNodes()
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Info.ID < nodes[j].Info.ID
+	})
+	return
+}
+
+func (s *Simulator) summarize(minSize int64) (pools []PoolReport, frag Fragmentation) {
+	for _, node := range s.allNodes() {
+		var item = PoolReport{
+			Name:          node.Info.ID,
+			Free:          node.FreeResource.Storage().Value(),
+			Volumes:       len(node.Volumes),
+			RemoteVolumes: node.RemoteVolumesCount(nil),
+		}
+		if q, has := node.Pool.Status.Capacity[v1.ResourceDiskPoolByte]; has {
+			item.Capacity = q.Value()
+		}
+		for _, vol := range node.Volumes {
+			item.Allocated += int64(vol.GetTotalSize())
+		}
+		for _, resv := range node.Reservations() {
+			item.Reserved += resv.Size()
+		}
+		if item.Capacity > 0 {
+			item.Utilization = float64(item.Capacity-item.Free) / float64(item.Capacity) * 100
+		}
+		pools = append(pools, item)
+
+		if item.Free <= 0 {
+			continue
+		}
+		frag.TotalFree += item.Free
+		if item.Free > frag.MaxPoolFree {
+			frag.MaxPoolFree = item.Free
+		}
+		if minSize > 0 && item.Free < minSize {
+			frag.StrandedFree += item.Free
+		}
+	}
+
+	if frag.TotalFree > 0 {
+		frag.Ratio = 1 - float64(frag.MaxPoolFree)/float64(frag.TotalFree)
+	}
+
+	return
+}
+
+// reasonsOf returns the reasons of scheduling failure
+func reasonsOf(err error) (reasons []string) {
+	var merged *filter.MergedError
+	if errors.As(err, &merged) {
+		for reason := range merged.Reasons() {
+			reasons = append(reasons, reason)
+		}
+		if len(reasons) == 0 {
+			reasons = append(reasons, filter.NoStoragePoolAvailable)
+		}
+		sort.Strings(reasons)
+		return
+	}
+	if errors.Is(err, errGroupSizeNotSatisfied) {
+		return []string{ReasonGroupSizeNotSatisfied}
+	}
+	return []string{ReasonError}
+}
@@ -0,0 +1,110 @@
+package simulate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"lite.io/liteio/pkg/controller/manager/config"
+	"lite.io/liteio/pkg/controller/manager/scheduler/filter"
+)
+
+const gib = 1 << 30
+
+const dumpYAML = `
+apiVersion: v1
+kind: List
+items:
+- apiVersion: volume.antstor.alipay.com/v1
+  kind: StoragePool
+  metadata:
+    name: node-1
+    namespace: obnvmf
+  spec:
+    nodeInfo:
+      id: node-1
+    kernelLVM:
+      name: vg-1
+      bytes: 107374182400
+  status:
+    status: ready
+    vgFreeSize: 100Gi
+    conditions:
+    - type: Spdk
+      status: OK
+- apiVersion: volume.antstor.alipay.com/v1
+  kind: AntstorVolume
+  metadata:
+    name: vol-1
+    namespace: obnvmf
+  spec:
+    uuid: vol-1-uuid
+    type: KernelLVol
+    sizeByte: 42949672960
+    targetNodeId: node-1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+`
+
+// output of state.StateHandler
+const dumpJSON = `[{"name":"node-2","poolLabels":{},"kernelLVM":{"name":"vg-2","bytes":107374182400},
+"volumes":null,"vgFreeSize":107374182400,"conditions":{"Spdk":"OK"},"reservations":[{"id":"resv-1","size":10737418240}]}]`
+
+const workloadYAML = `
+requests:
+- count: 4
+  size: 30Gi
+- size: 100Gi
+`
+
+func TestSimulate(t *testing.T) {
+	var dump Dump
+	assert.NoError(t, LoadDump(strings.NewReader(dumpYAML), &dump))
+	assert.NoError(t, LoadDump(strings.NewReader(dumpJSON), &dump))
+	assert.Len(t, dump.Pools, 2)
+	assert.Len(t, dump.Volumes, 1)
+
+	memState, err := dump.NewState()
+	assert.NoError(t, err)
+	node1, err := memState.GetNodeByNodeID("node-1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(60*gib), node1.FreeResource.Storage().Value())
+	node2, err := memState.GetNodeByNodeID("node-2")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(90*gib), node2.FreeResource.Storage().Value())
+
+	workload, err := LoadWorkload(strings.NewReader(workloadYAML))
+	assert.NoError(t, err)
+
+	sim := NewSimulator(config.Config{
+		Scheduler: config.SchedulerConfig{
+			MaxRemoteVolumeCount: 10,
+			Filters:              []string{"Basic"},
+			Priorities:           []string{"LeastResource"},
+		},
+	}, memState, 1)
+	report, err := sim.Run(workload)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 5, report.Requests)
+	assert.Equal(t, 4, report.Scheduled)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, map[string]int{filter.ReasonPoolFreeSize: 1}, report.FailureReasons)
+
+	// 150Gi free space is shared by 4 volumes of 30Gi, and 30Gi is left on one pool
+	assert.Equal(t, int64(30*gib), report.Fragmentation.TotalFree)
+	assert.Equal(t, int64(30*gib), report.Fragmentation.MaxPoolFree)
+	assert.Zero(t, report.Fragmentation.Ratio)
+	assert.Zero(t, report.Fragmentation.StrandedFree)
+
+	if assert.Len(t, report.Pools, 2) {
+		assert.Equal(t, "node-1", report.Pools[0].Name)
+		assert.Equal(t, int64(100*gib), report.Pools[0].Capacity)
+		assert.Equal(t, int64(10*gib), report.Pools[1].Reserved)
+		assert.Equal(t, 5, report.Pools[0].Volumes+report.Pools[1].Volumes)
+	}
+}
@@ -0,0 +1,211 @@
+package simulate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/controller/manager/state"
+)
+
+const (
+	kindStoragePool   = "StoragePool"
+	kindAntstorVolume = "AntstorVolume"
+)
+
+// Dump is the recorded cluster state
+type Dump struct {
+	Pools   []*v1.StoragePool
+	Volumes []*v1.AntstorVolume
+	// Reservations are loaded from NodeStateAPI, keyed by node id
+	Reservations map[string][]state.ReservationBreif
+}
+
+// LoadDump reads StoragePools and AntstorVolumes from r. The content is a stream of YAML or JSON documents.
+// Each document is one of
+// 1. a StoragePool or AntstorVolume, or a List of them, e.g. output of `kubectl get storagepool,antstorvolume -A -o yaml`
+// 2. a NodeStateAPI returned by state.StateHandler, or an array of them
+// Objects of other kinds are ignored.
+func LoadDump(r io.Reader, dump *Dump) (err error) {
+	if dump.Reservations == nil {
+		dump.Reservations = make(map[string][]state.ReservationBreif)
+	}
+
+	var decoder = yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var raw json.RawMessage
+		if err = decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return
+		}
+		if err = dump.add(raw); err != nil {
+			return
+		}
+	}
+}
+
+func (d *Dump) add(raw json.RawMessage) (err error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return
+	}
+
+	// array of NodeStateAPI or objects
+	if raw[0] == '[' {
+		var items []json.RawMessage
+		if err = json.Unmarshal(raw, &items); err != nil {
+			return
+		}
+		for _, item := range items {
+			if err = d.add(item); err != nil {
+				return
+			}
+		}
+		return
+	}
+
+	var meta struct {
+		metav1.TypeMeta `json:",inline"`
+		Items           []json.RawMessage `json:"items"`
+		// Name is set in NodeStateAPI
+		Name string `json:"name"`
+	}
+	if err = json.Unmarshal(raw, &meta); err != nil {
+		return
+	}
+
+	switch {
+	case meta.Kind == kindStoragePool:
+		var pool v1.StoragePool
+		if err = json.Unmarshal(raw, &pool); err != nil {
+			return
+		}
+		d.Pools = append(d.Pools, &pool)
+	case meta.Kind == kindAntstorVolume:
+		var vol v1.AntstorVolume
+		if err = json.Unmarshal(raw, &vol); err != nil {
+			return
+		}
+		d.Volumes = append(d.Volumes, &vol)
+	case meta.Kind == "List" || meta.Items != nil:
+		for _, item := range meta.Items {
+			if err = d.add(item); err != nil {
+				return
+			}
+		}
+	case meta.Kind == "" && meta.Name != "":
+		var api state.NodeStateAPI
+		if err = json.Unmarshal(raw, &api); err != nil {
+			return
+		}
+		d.addNodeState(api)
+	}
+
+	return
+}
+
+// addNodeState converts NodeStateAPI to StoragePool and AntstorVolumes
+func (d *Dump) addNodeState(api state.NodeStateAPI) {
+	var pool = &v1.StoragePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      api.Name,
+			Namespace: v1.DefaultNamespace,
+			Labels:    api.PoolLabels,
+		},
+		Spec: v1.StoragePoolSpec{
+			NodeInfo: v1.NodeInfo{ID: api.Name},
+		},
+		Status: v1.StoragePoolStatus{
+			Status:     v1.PoolStatusReady,
+			VGFreeSize: *resource.NewQuantity(api.VgFreeSize, resource.BinarySI),
+		},
+	}
+	var volType = v1.VolumeTypeKernelLVol
+	if api.KernelLVM != nil {
+		pool.Spec.KernelLVM = *api.KernelLVM
+	}
+	if api.SpdkLVS != nil {
+		pool.Spec.SpdkLVStore = *api.SpdkLVS
+		if api.SpdkLVS.Name != "" {
+			volType = v1.VolumeTypeSpdkLVol
+		}
+	}
+
+	// sort conditions to keep the result stable
+	var condTypes []string
+	for typ := range api.Conditions {
+		condTypes = append(condTypes, string(typ))
+	}
+	sort.Strings(condTypes)
+	for _, typ := range condTypes {
+		pool.Status.Conditions = append(pool.Status.Conditions, v1.PoolCondition{
+			Type:   v1.PoolConditionType(typ),
+			Status: api.Conditions[v1.PoolConditionType(typ)],
+		})
+	}
+	d.Pools = append(d.Pools, pool)
+
+	for _, item := range api.Volumes {
+		vol := &v1.AntstorVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      item.Name,
+				Namespace: item.Namespace,
+				Labels:    map[string]string{},
+			},
+			Spec: v1.AntstorVolumeSpec{
+				Uuid:         fmt.Sprintf("%s/%s", item.Namespace, item.Name),
+				Type:         volType,
+				SizeByte:     uint64(item.Size),
+				TargetNodeId: api.Name,
+			},
+			Status: v1.AntstorVolumeStatus{
+				Status: v1.VolumeStatusReady,
+			},
+		}
+		if item.DataHolder != "" {
+			vol.Labels[v1.VolumeDataHolderKey] = item.DataHolder
+		}
+		d.Volumes = append(d.Volumes, vol)
+	}
+
+	d.Reservations[api.Name] = append(d.Reservations[api.Name], api.Resvervations...)
+}
+
+// NewState builds an in-memory State from the dump. Volumes not bound to any node are ignored.
+func (d *Dump) NewState() (s state.StateIface, err error) {
+	s = state.NewState()
+	for _, pool := range d.Pools {
+		s.SetStoragePool(pool.DeepCopy())
+	}
+
+	for _, vol := range d.Volumes {
+		if vol.Spec.TargetNodeId == "" {
+			continue
+		}
+		if err = s.BindAntstorVolume(vol.Spec.TargetNodeId, vol); err != nil {
+			err = fmt.Errorf("bind volume %s/%s to node %s failed: %w", vol.Namespace, vol.Name, vol.Spec.TargetNodeId, err)
+			return
+		}
+	}
+
+	for nodeID, resvs := range d.Reservations {
+		node, errGet := s.GetNodeByNodeID(nodeID)
+		if errGet != nil {
+			continue
+		}
+		for _, item := range resvs {
+			node.Reserve(state.NewReservation(item.ID, item.Size))
+		}
+	}
+
+	return
+}
@@ -1,10 +1,10 @@
 package state
 
 import (
-	"encoding/json"
 	"net/http"
 
 	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/util/misc"
 )
 
 type NodeStateAPI struct {
@@ -49,13 +49,13 @@ type StateHandler struct {
 func (h *StateHandler) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
 	var spName = req.URL.Query().Get("name")
 	if spName == "" {
-		writer.Write([]byte("query param name is empty"))
+		misc.WriteJSON(writer, http.StatusBadRequest, misc.ErrorResponse{Error: "query param name is empty"})
 		return
 	}
 
 	var node, err = h.state.GetNodeByNodeID(spName)
 	if err != nil {
-		writer.Write([]byte(err.Error()))
+		misc.WriteJSON(writer, http.StatusNotFound, misc.ErrorResponse{Error: err.Error()})
 		return
 	}
 
@@ -93,10 +93,5 @@ func (h *StateHandler) ServeHTTP(writer http.ResponseWriter, req *http.Request)
 		}
 	}
 
-	bs, err := json.Marshal(api)
-	if err != nil {
-		writer.Write([]byte(err.Error()))
-		return
-	}
-	writer.Write(bs)
+	misc.WriteJSON(writer, http.StatusOK, api)
 }
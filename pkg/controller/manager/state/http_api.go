@@ -0,0 +1,272 @@
+package state
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+const (
+	// StateAPIPrefix is the path prefix of read-only state API on the metrics service of controller
+	StateAPIPrefix = "/state/"
+)
+
+// NodeSummary is the usage of a node. A volume is local if its host node is the node, otherwise it is remote.
+type NodeSummary struct {
+	Name   string            `json:"name"`
+	Status v1.PoolStatus     `json:"status"`
+	Mode   v1.PoolMode       `json:"mode"`
+	IsThin bool              `json:"isThin"`
+	Labels map[string]string `json:"labels,omitempty"`
+
+	CapacityBytes        int64 `json:"capacityBytes"`
+	FreeBytes            int64 `json:"freeBytes"`
+	AllocatedBytes       int64 `json:"allocatedBytes"`
+	AllocatedLocalBytes  int64 `json:"allocatedLocalBytes"`
+	AllocatedRemoteBytes int64 `json:"allocatedRemoteBytes"`
+	ReservedBytes        int64 `json:"reservedBytes"`
+	VolumeCount          int   `json:"volumeCount"`
+	RemoteVolumeCount    int   `json:"remoteVolumeCount"`
+}
+
+// NodeDetail is the usage of a node with its volumes and reservations
+type NodeDetail struct {
+	NodeSummary  `json:",inline"`
+	Volumes      []VolumeState      `json:"volumes"`
+	Reservations []ReservationState `json:"reservations"`
+}
+
+type VolumeState struct {
+	UUID      string        `json:"uuid"`
+	Namespace string        `json:"namespace"`
+	Name      string        `json:"name"`
+	NodeID    string        `json:"nodeId"`
+	HostNode  string        `json:"hostNode,omitempty"`
+	Type      v1.VolumeType `json:"type"`
+	IsThin    bool          `json:"isThin"`
+	IsLocal   bool          `json:"isLocal"`
+	Priority  int32         `json:"priority,omitempty"`
+	SizeBytes int64         `json:"sizeBytes"`
+	// TotalBytes includes the space reserved for snapshot
+	TotalBytes int64 `json:"totalBytes"`
+}
+
+type ReservationState struct {
+	ID       string `json:"id"`
+	NodeID   string `json:"nodeId"`
+	Size     int64  `json:"size"`
+	Priority int32  `json:"priority,omitempty"`
+}
+
+// ClusterSummary is the total usage of all nodes
+type ClusterSummary struct {
+	NodeCount        int   `json:"nodeCount"`
+	VolumeCount      int   `json:"volumeCount"`
+	ReservationCount int   `json:"reservationCount"`
+	CapacityBytes    int64 `json:"capacityBytes"`
+	FreeBytes        int64 `json:"freeBytes"`
+	AllocatedBytes   int64 `json:"allocatedBytes"`
+	ReservedBytes    int64 `json:"reservedBytes"`
+
+	Local  VolumeUsage `json:"local"`
+	Remote VolumeUsage `json:"remote"`
+	Thin   VolumeUsage `json:"thin"`
+	Thick  VolumeUsage `json:"thick"`
+}
+
+type VolumeUsage struct {
+	VolumeCount int   `json:"volumeCount"`
+	Bytes       int64 `json:"bytes"`
+}
+
+func (u *VolumeUsage) add(size int64) {
+	u.VolumeCount++
+	u.Bytes += size
+}
+
+func NewStateAPIHandler(s StateIface) *StateAPIHandler {
+	return &StateAPIHandler{state: s}
+}
+
+// StateAPIHandler serves read-only state of scheduler in JSON
+// GET /state/nodes lists usage of all nodes
+// GET /state/nodes/<id> shows usage, volumes and reservations of a node
+// GET /state/volumes/<uuid> shows a volume and its node
+// GET /state/reservations lists reservations, filtered by query param node
+// GET /state/summary shows cluster totals
+type StateAPIHandler struct {
+	state StateIface
+}
+
+func (h *StateAPIHandler) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		misc.WriteJSON(writer, http.StatusMethodNotAllowed, misc.ErrorResponse{Error: "only GET is allowed"})
+		return
+	}
+
+	var path = strings.Trim(strings.TrimPrefix(req.URL.Path, StateAPIPrefix), "/")
+	switch {
+	case path == "nodes":
+		var list = make([]NodeSummary, 0)
+		for _, node := range sortedNodes(h.state) {
+			list = append(list, summaryOfNode(node, node.View()))
+		}
+		misc.WriteJSON(writer, http.StatusOK, list)
+	case strings.HasPrefix(path, "nodes/"):
+		node, err := h.state.GetNodeByNodeID(strings.TrimPrefix(path, "nodes/"))
+		if err != nil {
+			misc.WriteJSON(writer, http.StatusNotFound, misc.ErrorResponse{Error: err.Error()})
+			return
+		}
+		misc.WriteJSON(writer, http.StatusOK, detailOfNode(node))
+	case strings.HasPrefix(path, "volumes/"):
+		vol, err := h.state.GetVolumeByID(strings.TrimPrefix(path, "volumes/"))
+		if err != nil {
+			misc.WriteJSON(writer, http.StatusNotFound, misc.ErrorResponse{Error: err.Error()})
+			return
+		}
+		misc.WriteJSON(writer, http.StatusOK, stateOfVolume(vol))
+	case path == "reservations":
+		var (
+			nodeID = req.URL.Query().Get("node")
+			list   = make([]ReservationState, 0)
+		)
+		for _, node := range sortedNodes(h.state) {
+			if nodeID == "" || nodeID == node.Info.ID {
+				list = append(list, reservationsOfNode(node.Info.ID, node.Reservations())...)
+			}
+		}
+		misc.WriteJSON(writer, http.StatusOK, list)
+	case path == "summary":
+		misc.WriteJSON(writer, http.StatusOK, summaryOfCluster(sortedNodes(h.state)))
+	default:
+		misc.WriteJSON(writer, http.StatusNotFound, misc.ErrorResponse{Error: "unknown path " + req.URL.Path})
+	}
+}
+
+func sortedNodes(s StateIface) (nodes []*Node) {
+	nodes = s.GetAllNodes()
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Info.ID < nodes[j].Info.ID
+	})
+	return
+}
+
+// summaryOfNode sums up the view of node, which is read with lock
+func summaryOfNode(node *Node, view NodeView) (sum NodeSummary) {
+	sum = NodeSummary{
+		Name:      node.Info.ID,
+		Status:    node.Pool.Status.Status,
+		Mode:      node.Pool.Mode(),
+		IsThin:    node.Pool.IsThin,
+		Labels:    node.Pool.Labels,
+		FreeBytes: view.FreeResource.Storage().Value(),
+	}
+	if q, has := node.Pool.Status.Capacity[v1.ResourceDiskPoolByte]; has {
+		sum.CapacityBytes = q.Value()
+	}
+	for _, vol := range view.Volumes {
+		size := int64(vol.GetTotalSize())
+		sum.AllocatedBytes += size
+		sum.VolumeCount++
+		if isLocalVolume(vol) {
+			sum.AllocatedLocalBytes += size
+		} else {
+			sum.AllocatedRemoteBytes += size
+			sum.RemoteVolumeCount++
+		}
+	}
+	for _, resv := range view.Reservations {
+		sum.ReservedBytes += resv.Size()
+	}
+	return
+}
+
+func detailOfNode(node *Node) (detail NodeDetail) {
+	var view = node.View()
+	detail = NodeDetail{
+		NodeSummary:  summaryOfNode(node, view),
+		Volumes:      make([]VolumeState, 0, len(view.Volumes)),
+		Reservations: reservationsOfNode(node.Info.ID, view.Reservations),
+	}
+	for _, vol := range view.Volumes {
+		detail.Volumes = append(detail.Volumes, stateOfVolume(vol))
+	}
+	sort.Slice(detail.Volumes, func(i, j int) bool {
+		return detail.Volumes[i].Name < detail.Volumes[j].Name
+	})
+	return
+}
+
+func reservationsOfNode(nodeID string, resvs []ReservationIface) (list []ReservationState) {
+	list = make([]ReservationState, 0)
+	for _, resv := range resvs {
+		list = append(list, ReservationState{
+			ID:       resv.ID(),
+			NodeID:   nodeID,
+			Size:     resv.Size(),
+			Priority: resv.Priority(),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return
+}
+
+func stateOfVolume(vol *v1.AntstorVolume) (vs VolumeState) {
+	vs = VolumeState{
+		UUID:       vol.Spec.Uuid,
+		Namespace:  vol.Namespace,
+		Name:       vol.Name,
+		NodeID:     vol.Spec.TargetNodeId,
+		Type:       vol.Spec.Type,
+		IsThin:     vol.Spec.IsThin,
+		IsLocal:    isLocalVolume(vol),
+		Priority:   vol.Spec.Priority,
+		SizeBytes:  int64(vol.Spec.SizeByte),
+		TotalBytes: int64(vol.GetTotalSize()),
+	}
+	if vol.Spec.HostNode != nil {
+		vs.HostNode = vol.Spec.HostNode.ID
+	}
+	return
+}
+
+func summaryOfCluster(nodes []*Node) (sum ClusterSummary) {
+	for _, node := range nodes {
+		view := node.View()
+		nodeSum := summaryOfNode(node, view)
+		sum.NodeCount++
+		sum.VolumeCount += nodeSum.VolumeCount
+		sum.ReservationCount += len(view.Reservations)
+		sum.CapacityBytes += nodeSum.CapacityBytes
+		sum.FreeBytes += nodeSum.FreeBytes
+		sum.AllocatedBytes += nodeSum.AllocatedBytes
+		sum.ReservedBytes += nodeSum.ReservedBytes
+
+		for _, vol := range view.Volumes {
+			size := int64(vol.GetTotalSize())
+			if isLocalVolume(vol) {
+				sum.Local.add(size)
+			} else {
+				sum.Remote.add(size)
+			}
+			if vol.Spec.IsThin {
+				sum.Thin.add(size)
+			} else {
+				sum.Thick.add(size)
+			}
+		}
+	}
+	return
+}
+
+// isLocalVolume returns true if the volume is consumed on the node where it resides.
+// Volumes without host node, e.g. volumes of AntstorVolumeGroup, are remote.
+func isLocalVolume(vol *v1.AntstorVolume) bool {
+	return vol.Spec.HostNode != nil && vol.Spec.HostNode.ID == vol.Spec.TargetNodeId
+}
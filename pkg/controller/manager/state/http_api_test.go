@@ -0,0 +1,123 @@
+package state
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/util/misc"
+)
+
+func TestStateAPIHandler(t *testing.T) {
+	const gib = 1 << 30
+
+	newPool := func(nodeID string) *v1.StoragePool {
+		return &v1.StoragePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: v1.DefaultNamespace,
+				Name:      nodeID,
+			},
+			Spec: v1.StoragePoolSpec{
+				NodeInfo:  v1.NodeInfo{ID: nodeID},
+				KernelLVM: v1.KernelLVM{Name: "vg", Bytes: 100 * gib},
+			},
+			Status: v1.StoragePoolStatus{
+				Status:     v1.PoolStatusReady,
+				VGFreeSize: resource.MustParse("100Gi"),
+				Capacity: corev1.ResourceList{
+					v1.ResourceDiskPoolByte: resource.MustParse("100Gi"),
+				},
+			},
+		}
+	}
+	newVol := func(name, hostNode string, size uint64, thin bool) *v1.AntstorVolume {
+		return &v1.AntstorVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: v1.DefaultNamespace,
+				Name:      name,
+			},
+			Spec: v1.AntstorVolumeSpec{
+				Uuid:     name + "-uuid",
+				Type:     v1.VolumeTypeKernelLVol,
+				SizeByte: size,
+				IsThin:   thin,
+				HostNode: &v1.NodeInfo{ID: hostNode},
+			},
+		}
+	}
+
+	s := NewState()
+	s.SetStoragePool(newPool("node-1"))
+	s.SetStoragePool(newPool("node-2"))
+	assert.NoError(t, s.BindAntstorVolume("node-1", newVol("local", "node-1", 10*gib, false)))
+	assert.NoError(t, s.BindAntstorVolume("node-1", newVol("remote", "node-2", 20*gib, true)))
+	node2, _ := s.GetNodeByNodeID("node-2")
+	node2.Reserve(NewReservationWithPriority("app/resv", 5*gib, 10))
+
+	handler := NewStateAPIHandler(s)
+	get := func(path string, body interface{}) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), body))
+		return rec.Code
+	}
+
+	var nodes []NodeSummary
+	assert.Equal(t, http.StatusOK, get("/state/nodes", &nodes))
+	if assert.Len(t, nodes, 2) {
+		assert.Equal(t, "node-1", nodes[0].Name)
+		assert.Equal(t, int64(30*gib), nodes[0].AllocatedBytes)
+		assert.Equal(t, int64(10*gib), nodes[0].AllocatedLocalBytes)
+		assert.Equal(t, int64(20*gib), nodes[0].AllocatedRemoteBytes)
+		assert.Equal(t, int64(70*gib), nodes[0].FreeBytes)
+		assert.Equal(t, 1, nodes[0].RemoteVolumeCount)
+		assert.Equal(t, int64(5*gib), nodes[1].ReservedBytes)
+	}
+
+	var detail NodeDetail
+	assert.Equal(t, http.StatusOK, get("/state/nodes/node-1", &detail))
+	assert.Equal(t, "node-1", detail.Name)
+	assert.Len(t, detail.Volumes, 2)
+	assert.Empty(t, detail.Reservations)
+
+	var vol VolumeState
+	assert.Equal(t, http.StatusOK, get("/state/volumes/remote-uuid", &vol))
+	assert.Equal(t, "node-1", vol.NodeID)
+	assert.Equal(t, "node-2", vol.HostNode)
+	assert.False(t, vol.IsLocal)
+
+	var resvs []ReservationState
+	assert.Equal(t, http.StatusOK, get("/state/reservations?node=node-2", &resvs))
+	assert.Equal(t, []ReservationState{{ID: "app/resv", NodeID: "node-2", Size: 5 * gib, Priority: 10}}, resvs)
+	assert.Equal(t, http.StatusOK, get("/state/reservations?node=node-1", &resvs))
+	assert.Empty(t, resvs)
+
+	var sum ClusterSummary
+	assert.Equal(t, http.StatusOK, get("/state/summary", &sum))
+	assert.Equal(t, 2, sum.NodeCount)
+	assert.Equal(t, int64(200*gib), sum.CapacityBytes)
+	assert.Equal(t, VolumeUsage{VolumeCount: 1, Bytes: 10 * gib}, sum.Local)
+	assert.Equal(t, VolumeUsage{VolumeCount: 1, Bytes: 20 * gib}, sum.Remote)
+	assert.Equal(t, VolumeUsage{VolumeCount: 1, Bytes: 20 * gib}, sum.Thin)
+	assert.Equal(t, VolumeUsage{VolumeCount: 1, Bytes: 10 * gib}, sum.Thick)
+
+	var errResp misc.ErrorResponse
+	assert.Equal(t, http.StatusNotFound, get("/state/nodes/node-3", &errResp))
+	assert.NotEmpty(t, errResp.Error)
+	assert.Equal(t, http.StatusNotFound, get("/state/volumes/none", &errResp))
+	assert.Equal(t, http.StatusNotFound, get("/state/unknown", &errResp))
+
+	req := httptest.NewRequest(http.MethodPost, "/state/nodes", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
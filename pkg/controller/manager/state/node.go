@@ -299,3 +299,76 @@ func (n *Node) Unreserve(id string) {
 func (n *Node) GetReservation(id string) (r ReservationIface, has bool) {
 	return n.resvSet.GetById(id)
 }
+
+// Reservations returns all the reservations on the node
+func (n *Node) Reservations() []ReservationIface {
+	return n.resvSet.Items()
+}
+
+// CopyWithout returns a copy of the node without the given reservations and volumes, which are namespace/name.
+// It is used to simulate preemption, and does not change the node.
+func (n *Node) CopyWithout(resvIDs, volKeys []string) (node *Node) {
+	n.volLock.Lock()
+	defer n.volLock.Unlock()
+
+	var (
+		resvSet = misc.NewEmptySet()
+		volSet  = misc.NewEmptySet()
+	)
+	for _, id := range resvIDs {
+		resvSet.Add(id)
+	}
+	for _, key := range volKeys {
+		volSet.Add(key)
+	}
+
+	node = NewNode(n.Pool)
+	for _, vol := range n.Volumes {
+		if !volSet.Contains(vol.Namespace + "/" + vol.Name) {
+			node.Volumes = append(node.Volumes, vol)
+		}
+	}
+	for _, item := range n.resvSet.Items() {
+		if !resvSet.Contains(item.ID()) {
+			node.resvSet.Reserve(item)
+		}
+	}
+	node.FreeResource = node.GetFreeResourceNonLock()
+
+	return
+}
+
+// IsReservationConsumed returns true if a volume on the node has consumed the reservation
+func (n *Node) IsReservationConsumed(id string) bool {
+	n.volLock.Lock()
+	defer n.volLock.Unlock()
+
+	for _, vol := range n.Volumes {
+		if getVolumeReservationID(vol) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// NodeView is a consistent copy of the volumes, reservations and free resource of a Node
+type NodeView struct {
+	Volumes      []*v1.AntstorVolume
+	Reservations []ReservationIface
+	FreeResource corev1.ResourceList
+}
+
+// View returns a copy of the node with lock, so that callers can read it while volumes are being added or removed.
+// Volumes are deep copied, because AddVolume replaces the content of a duplicate volume.
+func (n *Node) View() (view NodeView) {
+	n.volLock.Lock()
+	defer n.volLock.Unlock()
+
+	view.Volumes = make([]*v1.AntstorVolume, 0, len(n.Volumes))
+	for _, vol := range n.Volumes {
+		view.Volumes = append(view.Volumes, vol.DeepCopy())
+	}
+	view.Reservations = n.resvSet.Items()
+	view.FreeResource = n.FreeResource.DeepCopy()
+	return
+}
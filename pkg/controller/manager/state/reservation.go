@@ -20,6 +20,8 @@ type ReservationIface interface {
 	ID() string
 	Size() int64
 	NamespacedName() string
+	// Priority of the reservation, which can be preempted by volumes with higher priority
+	Priority() int32
 }
 
 type reservationSet struct {
@@ -75,6 +77,7 @@ type reservation struct {
 	id             string
 	namespacedName string
 	sizeByte       int64
+	priority       int32
 }
 
 func NewReservation(id string, size int64) ReservationIface {
@@ -84,6 +87,15 @@ func NewReservation(id string, size int64) ReservationIface {
 	}
 }
 
+// NewReservationWithPriority creates a reservation which can be preempted by volumes with higher priority
+func NewReservationWithPriority(id string, size int64, priority int32) ReservationIface {
+	return &reservation{
+		id:       id,
+		sizeByte: size,
+		priority: priority,
+	}
+}
+
 func NewPvcReservation(pvc *corev1.PersistentVolumeClaim) ReservationIface {
 	if pvc.DeletionTimestamp != nil {
 		return nil
@@ -112,6 +124,10 @@ func (r *reservation) Size() int64 {
 	return r.sizeByte
 }
 
+func (r *reservation) Priority() int32 {
+	return r.priority
+}
+
 func getVolumeReservationID(vol *v1.AntstorVolume) (id string) {
 	if resvId, has := vol.Annotations[v1.ReservationIDKey]; has {
 		return resvId
@@ -34,6 +34,8 @@ type StateIface interface {
 	FindVolumesByNodeID(nodeID string) (vols []*v1.AntstorVolume, err error)
 	BindAntstorVolume(nodeID string, vol *v1.AntstorVolume) (err error)
 	UnbindAntstorVolume(volID string) (err error)
+	// GetVolumeIndex returns a copy of index from volume UUID to node ID
+	GetVolumeIndex() (index map[string]string)
 }
 
 type state struct {
@@ -221,6 +223,17 @@ func (s *state) GetVolumeByID(volID string) (vol *v1.AntstorVolume, err error) {
 	return node.GetVolumeByID(volID)
 }
 
+func (s *state) GetVolumeIndex() (index map[string]string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	index = make(map[string]string, len(s.volIDMap))
+	for volID, nodeID := range s.volIDMap {
+		index[volID] = nodeID
+	}
+	return
+}
+
 func newNotFoundNodeError(id string) error {
 	return fmt.Errorf("%w by id %s", ErrNotFoundNode, id)
 }
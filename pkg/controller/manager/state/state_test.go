@@ -98,6 +98,31 @@ func TestNode(t *testing.T) {
 	assert.Equal(t, 0, len(node.resvSet.Items()))
 }
 
+func TestNodeView(t *testing.T) {
+	node := NewNode(&v1.StoragePool{
+		Spec: v1.StoragePoolSpec{NodeInfo: v1.NodeInfo{ID: "node1"}},
+		Status: v1.StoragePoolStatus{
+			Capacity: corev1.ResourceList{
+				v1.ResourceDiskPoolByte: resource.MustParse("10Gi"),
+			},
+			VGFreeSize: resource.MustParse("10Gi"),
+		},
+	})
+	assert.NoError(t, node.AddVolume(&v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "vol1"},
+		Spec:       v1.AntstorVolumeSpec{Uuid: "uuid-1", SizeByte: 1 << 30},
+	}))
+	node.Reserve(NewReservation("resv-1", 1<<30))
+
+	// view is a copy of the node
+	view := node.View()
+	assert.Len(t, view.Volumes, 1)
+	assert.Len(t, view.Reservations, 1)
+	assert.Equal(t, "8Gi", view.FreeResource.Storage().String())
+	view.Volumes[0].Spec.SizeByte = 0
+	assert.NotZero(t, node.Volumes[0].Spec.SizeByte)
+}
+
 func TestCompareError(t *testing.T) {
 	err := newNotFoundNodeError("test")
 	assert.True(t, IsNotFoundNodeError(err))
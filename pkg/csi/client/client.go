@@ -127,6 +127,11 @@ func (cm *KubeAPIClient) GetSnapshotByName(ns, name string) (snapshot *Snapshot,
 	return
 }
 
+func (cm *KubeAPIClient) GetSnapshotBackup(ns, name string) (backup *v1.SnapshotBackup, err error) {
+	backup, err = cm.cli.VolumeV1().SnapshotBackups(ns).Get(context.Background(), name, metav1.GetOptions{})
+	return
+}
+
 func (cm *KubeAPIClient) DeleteSnapshot(snapID string) (err error) {
 	if snapID == "" {
 		err = fmt.Errorf("invalid empty snapID")
@@ -150,6 +155,31 @@ func (cm *KubeAPIClient) DeleteSnapshot(snapID string) (err error) {
 	return
 }
 
+func (cm *KubeAPIClient) ListSnapshots() (list *v1.AntstorSnapshotList, err error) {
+	list, err = cm.cli.VolumeV1().AntstorSnapshots(defaultNamespace).List(context.Background(), metav1.ListOptions{})
+	return
+}
+
+func (cm *KubeAPIClient) UpdateSnapshot(snap *Snapshot) (snapshot *Snapshot, err error) {
+	snapshot, err = cm.cli.VolumeV1().AntstorSnapshots(snap.Namespace).Update(context.Background(), snap, metav1.UpdateOptions{})
+	return
+}
+
+func (cm *KubeAPIClient) ListVolumes() (list *v1.AntstorVolumeList, err error) {
+	list, err = cm.cli.VolumeV1().AntstorVolumes(defaultNamespace).List(context.Background(), metav1.ListOptions{})
+	return
+}
+
+func (cm *KubeAPIClient) ListDataControls() (list *v1.AntstorDataControlList, err error) {
+	list, err = cm.cli.VolumeV1().AntstorDataControls(defaultNamespace).List(context.Background(), metav1.ListOptions{})
+	return
+}
+
+func (cm *KubeAPIClient) ListQuota(ns string) (list *v1.AntstorQuotaList, err error) {
+	list, err = cm.cli.VolumeV1().AntstorQuotas(ns).List(context.Background(), metav1.ListOptions{})
+	return
+}
+
 func (cm *KubeAPIClient) GetStoragePoolByName(ns, name string) (sp *StoragePool, err error) {
 	sp, err = cm.cli.VolumeV1().StoragePools(ns).Get(context.Background(), name, metav1.GetOptions{})
 	return
@@ -52,6 +52,8 @@ type PVCreateOption struct {
 	PositionAdvice string
 
 	IsThin bool // vol thin provision
+	// priority of volume
+	Priority int32
 
 	PvType string
 	// for data control
@@ -74,6 +76,12 @@ type PvBaseIface interface {
 	DeletePV(id string) (err error)
 
 	ResizePV(id string, size int64) (err error)
+
+	// ListVolumes returns Volumes, which are PVs of Volume type
+	ListVolumes() (list *v1.AntstorVolumeList, err error)
+
+	// ListDataControls returns DataControls, which are PVs of VolumeGroup type
+	ListDataControls() (list *v1.AntstorDataControlList, err error)
 }
 
 type PvAdvancedIface interface {
@@ -93,6 +101,13 @@ type SnapshotIface interface {
 	GetSnapshotByName(ns, name string) (snapshot *Snapshot, err error)
 	CreateSnapshot(snap Snapshot) (snapID string, err error)
 	DeleteSnapshot(snapID string) (err error)
+	ListSnapshots() (list *v1.AntstorSnapshotList, err error)
+	UpdateSnapshot(snap *Snapshot) (snapshot *Snapshot, err error)
+	GetSnapshotBackup(ns, name string) (backup *v1.SnapshotBackup, err error)
+}
+
+type QuotaIface interface {
+	ListQuota(ns string) (list *v1.AntstorQuotaList, err error)
 }
 
 type StoragePoolIface interface {
@@ -106,6 +121,7 @@ type AntstorClientIface interface {
 	PvIface
 	SnapshotIface
 	StoragePoolIface
+	QuotaIface
 }
 
 func (p *PV) GetSize() int64 {
@@ -105,6 +105,7 @@ func (cm *KubeAPIClient) CreatePV(opt PVCreateOption) (volId string, err error)
 				HostNode:       &opt.HostNode,
 				PositionAdvice: v1.VolumePosition(opt.PositionAdvice),
 				IsThin:         opt.IsThin,
+				Priority:       opt.Priority,
 			},
 			Status: v1.AntstorVolumeStatus{
 				Status: v1.VolumeStatusCreating,
@@ -29,12 +29,14 @@ import (
 	"lite.io/liteio/pkg/csi/driver"
 	csimetric "lite.io/liteio/pkg/csi/metric"
 	"lite.io/liteio/pkg/csi/rpcserver"
+	"lite.io/liteio/pkg/generated/clientset/versioned"
 	hostnvme "lite.io/liteio/pkg/host-nvme"
 	"lite.io/liteio/pkg/spdk/jsonrpc/nvme"
 	"lite.io/liteio/pkg/util"
 	"lite.io/liteio/pkg/util/mount"
 	"lite.io/liteio/pkg/version"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
@@ -161,6 +163,11 @@ func (opt *RpcServerOption) run() (err error) {
 		go metric.NewHttpServer(csimetric.Registry).Serve(listener)
 	}
 
+	if !opt.IsController {
+		// freeze filesystems of volumes mounted on this node for snapshots
+		go rpcserver.NewFsFreezer(opt.NodeID, cloudMgr, versioned.NewForConfigOrDie(cfg)).Start(wait.NeverStop)
+	}
+
 	rpcserver.StartServer(opt.Endpoint, drv, mount.NewSafeMounter(), cloudMgr, kubeClient)
 
 	return
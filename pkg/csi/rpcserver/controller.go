@@ -73,6 +73,11 @@ const (
 	// CSI CreateVolumeRequest Context key, thin provision
 	thinProvisionKey = "thinProvision"
 
+	// CSI CreateVolumeRequest Context key, name of scheduler profile in controller config
+	schedulerProfileKey = "schedulerProfile"
+	// CSI CreateVolumeRequest Context key, priority of volume. Volume preempts reservations and volumes with lower priority.
+	priorityKey = "priority"
+
 	// CSI CreateVolumeRequest Context key for DataControl and VoluemGroup
 	// value is Volume or VolumeGroup
 	pvTypeKey             = "obnvmf/pv-type"
@@ -84,6 +89,11 @@ const (
 	volGroupMaxVolumesKey = "volgroup/max-volumes"
 	volGroupAllowEmptyKey = "volgroup/allow-empty-node"
 
+	// CSI CreateSnapshotRequest parameter key, freeze filesystem of the volume on the host node before the snapshot is cut
+	fsFreezeKey = "fsFreeze"
+	// CSI CreateSnapshotRequest parameter key, max seconds to keep the filesystem frozen
+	fsFreezeTimeoutSecondsKey = "fsFreezeTimeoutSeconds"
+
 	// Volume Annotation key, value is KernelLVM or SpdkLVS or Flexible
 	volumeTypeAnnoKey = "obnvmf/volume-type"
 
@@ -117,6 +127,8 @@ type ControllerServer struct {
 	cli     client.AntstorClientIface
 	locks   *misc.ResourceLocks
 	kubeCli kubernetes.Interface
+	// quotaLocks serializes checking quota and creating resources in the same namespace
+	quotaLocks *misc.KeyedMutex
 	// detachLimiter common.RetryLimiter
 	caps *storagePoolCaps
 }
@@ -125,11 +137,12 @@ type ControllerServer struct {
 // Create controller server
 func NewControllerServer(driver *driver.CSIDriver, cli client.AntstorClientIface, kubeCli kubernetes.Interface) *ControllerServer {
 	return &ControllerServer{
-		driver:  driver,
-		cli:     cli,
-		locks:   misc.NewResourceLocks(),
-		kubeCli: kubeCli,
-		caps:    newStoragePoolCaps(cli),
+		driver:     driver,
+		cli:        cli,
+		locks:      misc.NewResourceLocks(),
+		quotaLocks: misc.NewKeyedMutex(),
+		kubeCli:    kubeCli,
+		caps:       newStoragePoolCaps(cli),
 	}
 }
 
@@ -160,6 +173,8 @@ func (cs *ControllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 
 		err error
 		opt client.PVCreateOption
+		// type of the source volume or snapshot
+		srcType v1.VolumeType
 		// attributes for AntstroVolume
 		volLabels      = make(map[string]string)
 		volAnnotations = make(map[string]string)
@@ -183,12 +198,22 @@ func (cs *ControllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 	opt.PvType = req.Parameters[pvTypeKey]
 
 	opt.IsThin, _ = strconv.ParseBool(req.Parameters[thinProvisionKey])
+	if val, has := req.Parameters[priorityKey]; has {
+		priority, errParse := strconv.ParseInt(val, 10, 32)
+		if errParse != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid priority %q: %v", val, errParse)
+		}
+		opt.Priority = int32(priority)
+	}
 
 	volLabels[v1.FsTypeLabelKey] = fsType
 	volLabels[v1.VolumePVNameLabelKey] = req.Name
 	volLabels[pvcNameKeyForLabel] = pvcName
 	volLabels[pvcNamespaceKeyForLabel] = pvcNs
 	volAnnotations[v1.FsTypeLabelKey] = fsType
+	if profile := req.Parameters[schedulerProfileKey]; profile != "" {
+		volAnnotations[v1.SchedulerProfileAnnoKey] = profile
+	}
 
 	opt.RaidLevel = req.Parameters[raidLevelKey]
 	opt.EngineType = req.Parameters[engineTypeKey]
@@ -219,7 +244,44 @@ func (cs *ControllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 		}
 		volLabels[v1.VolumeSourceSnapNameLabelKey] = snap.Name
 		volLabels[v1.VolumeSourceSnapNamespaceLabelKey] = snap.Namespace
-		volAnnotations[v1.PoolLabelSelectorKey] = fmt.Sprintf("%s=%s", v1.PoolLabelsNodeSnKey, snap.Spec.OriginVolTargetNodeID)
+		srcType = snap.Spec.VolType
+		// SPDK lvol clone shares clusters with the snapshot, so it must be in the same lvstore.
+		// Data of LVM snapshot is copied by agent, so the volume can be scheduled to any pool.
+		if snap.Spec.VolType == v1.VolumeTypeSpdkLVol {
+			volAnnotations[v1.PoolLabelSelectorKey] = fmt.Sprintf("%s=%s", v1.PoolLabelsNodeSnKey, snap.Spec.OriginVolTargetNodeID)
+		}
+	}
+
+	if req.VolumeContentSource.GetVolume() != nil {
+		id := req.VolumeContentSource.GetVolume().VolumeId
+		pv, err := cs.cli.GetPvByID(id)
+		if err == client.ErrorNotFoundResource {
+			return nil, status.Errorf(codes.NotFound, "source volume %s is not found", id)
+		}
+		if err != nil {
+			klog.Error(err)
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if pv.Type != client.PvTypeVolume {
+			return nil, status.Errorf(codes.InvalidArgument, "cloning %s volume %s is not supported", pv.Type, id)
+		}
+		srcVol := pv.Volume
+		if srcVol.Status.Status != v1.VolumeStatusReady {
+			err = fmt.Errorf("source volume has not been ready yet, status %s", srcVol.Status.Status)
+			klog.Error(err)
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if opt.Size < int64(srcVol.Spec.SizeByte) {
+			return nil, status.Errorf(codes.InvalidArgument, "size %d is smaller than source volume size %d", opt.Size, srcVol.Spec.SizeByte)
+		}
+		volLabels[v1.VolumeSourceVolNameLabelKey] = srcVol.Name
+		volLabels[v1.VolumeSourceVolNamespaceLabelKey] = srcVol.Namespace
+		srcType = srcVol.Spec.Type
+		// SPDK lvol clone shares clusters with the source lvol, so it must be in the same lvstore.
+		// LVM volume is copied by agent, which can be in any pool.
+		if srcVol.Spec.Type == v1.VolumeTypeSpdkLVol {
+			volAnnotations[v1.PoolLabelSelectorKey] = fmt.Sprintf("%s=%s", v1.PoolLabelsNodeSnKey, srcVol.Spec.TargetNodeId)
+		}
 	}
 
 	if pvcNs != "" && pvcName != "" {
@@ -246,6 +308,15 @@ func (cs *ControllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 		if typ, has := pvc.Annotations[volumeTypeAnnoKey]; has {
 			opt.VolumeType = v1.VolumeType(typ)
 		}
+		// data of volume is restored from backup by agent
+		if source, has := pvc.Annotations[v1.VolumeSourceBackupAnnoKey]; has {
+			if req.VolumeContentSource != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "annotation %s cannot be used with dataSource", v1.VolumeSourceBackupAnnoKey)
+			}
+			if err = cs.validateSourceBackup(pvc.Namespace, source); err != nil {
+				return nil, err
+			}
+		}
 
 		klog.Infof("PVC ResourceVersion %s, Annotations %+v", pvc.ResourceVersion, pvc.Annotations)
 
@@ -257,6 +328,11 @@ func (cs *ControllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 		}
 	}
 
+	// volume has the same type as the source volume or snapshot
+	if srcType != "" {
+		opt.VolumeType = srcType
+	}
+
 	// set HostNode info
 	if nodeName != "" {
 		// TODO: config
@@ -287,6 +363,23 @@ func (cs *ControllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 	opt.Labels = volLabels
 	opt.Annotations = volAnnotations
 
+	// check AntstorQuota of PVC's namespace
+	quotaReq := (&v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: volAnnotations,
+		},
+		Spec: v1.AntstorVolumeSpec{
+			Type:           opt.VolumeType,
+			SizeByte:       uint64(opt.Size),
+			PositionAdvice: v1.VolumePosition(opt.PositionAdvice),
+			IsThin:         opt.IsThin,
+		},
+	}).QuotaUsage(true)
+	defer cs.lockQuota(pvcNs)()
+	if err = cs.checkQuota(pvcNs, opt.PvName, quotaReq); err != nil {
+		return nil, err
+	}
+
 	volID, err := cs.cli.CreatePV(opt)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
@@ -307,6 +400,27 @@ func (cs *ControllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 	return resp, nil
 }
 
+// validateSourceBackup checks the SnapshotBackup which the volume of PVC is restored from.
+// The backup must be Completed, and the origin volume must belong to a PVC in the same namespace, so data is not restored across namespaces.
+func (cs *ControllerServer) validateSourceBackup(pvcNs, source string) error {
+	ns, name := v1.ParseSourceBackup(source)
+	backup, err := cs.cli.GetSnapshotBackup(ns, name)
+	if errors.IsNotFound(err) {
+		return status.Errorf(codes.InvalidArgument, "source backup %s is not found", source)
+	}
+	if err != nil {
+		klog.Error(err)
+		return status.Error(codes.Internal, err.Error())
+	}
+	if backup.Status.Phase != v1.SnapshotBackupPhaseCompleted {
+		return status.Errorf(codes.InvalidArgument, "source backup %s is not completed, phase %q", source, backup.Status.Phase)
+	}
+	if backup.Status.PvcNamespace != pvcNs {
+		return status.Errorf(codes.InvalidArgument, "source backup %s does not belong to namespace %s", source, pvcNs)
+	}
+	return nil
+}
+
 // DeleteVolume deletes the volume. This operation MUST be idempotent
 // volume id is REQUIRED in csi.DeleteVolumeRequest
 func (cs *ControllerServer) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
@@ -323,9 +437,44 @@ func (cs *ControllerServer) DeleteVolume(ctx context.Context, req *csi.DeleteVol
 	return &csi.DeleteVolumeResponse{}, nil
 }
 
-func (cs *ControllerServer) ControllerGetVolume(context.Context, *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
+// ControllerGetVolume returns size and host node of the volume. The volume is abnormal if its StoragePool is offline or has error conditions.
+func (cs *ControllerServer) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
+	klog.Infof("ControllerGetVolume req=%s", req.String())
+	if req.GetVolumeId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "ControllerGetVolumeRequest.VolumeId is empty")
+	}
+
+	pv, err := cs.cli.GetPvByID(req.GetVolumeId())
+	if err == client.ErrorNotFoundResource {
+		return nil, status.Errorf(codes.NotFound, "volume %s is not found", req.GetVolumeId())
+	}
+	if err != nil {
+		klog.Error(err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	var pools = make(map[string]*v1.StoragePool, 1)
+	if targetNode := pv.GetTargetNodeId(); targetNode != "" {
+		pool, err := cs.cli.GetStoragePoolByName(v1.DefaultNamespace, targetNode)
+		if err != nil && !errors.IsNotFound(err) {
+			klog.Error(err)
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if err == nil {
+			pools[targetNode] = pool
+		}
+	}
 
-	return nil, status.Error(codes.Unimplemented, "")
+	return &csi.ControllerGetVolumeResponse{
+		Volume: &csi.Volume{
+			VolumeId:      pv.UUID,
+			CapacityBytes: pv.GetSize(),
+		},
+		Status: &csi.ControllerGetVolumeResponse_VolumeStatus{
+			PublishedNodeIds: publishedNodeIDs(pv),
+			VolumeCondition:  volumeCondition(pv, pools),
+		},
+	}, nil
 }
 
 // ControllerPublishVolume attaches the volume to the node
@@ -453,9 +602,46 @@ func (cs *ControllerServer) ControllerExpandVolume(ctx context.Context, req *csi
 	return resp, nil
 }
 
+// ListVolumes returns Volumes and DataControls, with their host nodes and conditions of StoragePools.
+// starting_token is the offset of the first entry.
 func (cs *ControllerServer) ListVolumes(ctx context.Context, req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
 	klog.Infof("ListVolumes req=%s", req.String())
-	return nil, status.Error(codes.Unimplemented, "")
+	if req.GetMaxEntries() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "ListVolumesRequest.MaxEntries is negative")
+	}
+
+	pvs, err := cs.listPVs()
+	if err != nil {
+		klog.Error(err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	start, end, nextToken, err := paginate(len(pvs), req.GetMaxEntries(), req.GetStartingToken())
+	if err != nil {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+	pools, err := cs.listPoolsByName()
+	if err != nil {
+		klog.Error(err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &csi.ListVolumesResponse{
+		NextToken: nextToken,
+	}
+	for _, pv := range pvs[start:end] {
+		resp.Entries = append(resp.Entries, &csi.ListVolumesResponse_Entry{
+			Volume: &csi.Volume{
+				VolumeId:      pv.UUID,
+				CapacityBytes: pv.GetSize(),
+			},
+			Status: &csi.ListVolumesResponse_VolumeStatus{
+				PublishedNodeIds: publishedNodeIDs(pv),
+				VolumeCondition:  volumeCondition(pv, pools),
+			},
+		})
+	}
+
+	return resp, nil
 }
 
 func (cs *ControllerServer) GetCapacity(ctx context.Context, req *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
@@ -559,6 +745,9 @@ CreateSnapshotResponse, error) {
 	snapLabels := make(map[string]string)
 	snapLabels[v1.OriginVolumeNameLabelKey] = vol.Name
 	snapLabels[v1.OriginVolumeNamespaceLabelKey] = vol.Namespace
+	if pvcNs := vol.QuotaNamespace(); pvcNs != "" {
+		snapLabels[v1.VolumeContextKeyPvcNS] = pvcNs
+	}
 	snap := v1.AntstorSnapshot{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:   v1.DefaultNamespace,
@@ -573,11 +762,29 @@ CreateSnapshotResponse, error) {
 			Size:               int64(vol.Spec.SizeByte),
 			OriginVolName:      vol.Name,
 			OriginVolNamespace: vol.Namespace,
+			IsThin:             vol.IsThinLVol(),
 		},
 		Status: v1.AntstorSnapshotStatus{
 			Status: v1.SnapshotStatusCreating,
 		},
 	}
+	// application consistent snapshot
+	if freeze, _ := strconv.ParseBool(req.Parameters[fsFreezeKey]); freeze {
+		snap.Spec.FsFreezeTimeoutSeconds = v1.DefaultFreezeTimeoutSeconds
+		if val, has := req.Parameters[fsFreezeTimeoutSecondsKey]; has {
+			sec, err := strconv.Atoi(val)
+			if err != nil || sec <= 0 {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid %s %q", fsFreezeTimeoutSecondsKey, val)
+			}
+			snap.Spec.FsFreezeTimeoutSeconds = int32(sec)
+		}
+	}
+	// check AntstorQuota of PVC's namespace
+	defer cs.lockQuota(vol.QuotaNamespace())()
+	if err = cs.checkQuota(vol.QuotaNamespace(), "", snap.QuotaUsage()); err != nil {
+		return nil, err
+	}
+
 	snapID, err := cs.cli.CreateSnapshot(snap)
 	if err != nil {
 		klog.Error(err)
@@ -618,9 +825,34 @@ func (cs *ControllerServer) DeleteSnapshot(ctx context.Context, req *csi.DeleteS
 	return &csi.DeleteSnapshotResponse{}, nil
 }
 
+// ListSnapshots returns AntstorSnapshots filtered by snapshot ID or source volume ID.
+// starting_token is the offset of the first entry.
 func (cs *ControllerServer) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
 	klog.Infof("ListSnapshots Req=%s", req.String())
-	return nil, status.Error(codes.Unimplemented, "")
+	if req.GetMaxEntries() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "ListSnapshotsRequest.MaxEntries is negative")
+	}
+
+	snaps, err := cs.listSnapshots(req.GetSnapshotId(), req.GetSourceVolumeId())
+	if err != nil {
+		klog.Error(err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	start, end, nextToken, err := paginate(len(snaps), req.GetMaxEntries(), req.GetStartingToken())
+	if err != nil {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+
+	resp := &csi.ListSnapshotsResponse{
+		NextToken: nextToken,
+	}
+	for _, snap := range snaps[start:end] {
+		resp.Entries = append(resp.Entries, &csi.ListSnapshotsResponse_Entry{
+			Snapshot: snap,
+		})
+	}
+
+	return resp, nil
 }
 
 func (cs *ControllerServer) ControllerGetCapabilities(ctx context.Context,
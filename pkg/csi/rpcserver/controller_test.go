@@ -0,0 +1,64 @@
+package rpcserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/csi/client"
+)
+
+// fakeBackupClient serves SnapshotBackups from memory
+type fakeBackupClient struct {
+	client.AntstorClientIface
+	backups []v1.SnapshotBackup
+}
+
+func (c *fakeBackupClient) GetSnapshotBackup(ns, name string) (*v1.SnapshotBackup, error) {
+	for i := range c.backups {
+		if c.backups[i].Namespace == ns && c.backups[i].Name == name {
+			return &c.backups[i], nil
+		}
+	}
+	return nil, errors.NewNotFound(schema.GroupResource{Resource: "snapshotbackups"}, name)
+}
+
+func TestValidateSourceBackup(t *testing.T) {
+	newBackup := func(name string, phase v1.SnapshotBackupPhase, pvcNs string) v1.SnapshotBackup {
+		return v1.SnapshotBackup{
+			ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: name},
+			Status: v1.SnapshotBackupStatus{
+				Phase:        phase,
+				PvcNamespace: pvcNs,
+			},
+		}
+	}
+	cs := &ControllerServer{cli: &fakeBackupClient{backups: []v1.SnapshotBackup{
+		newBackup("completed", v1.SnapshotBackupPhaseCompleted, "app"),
+		newBackup("uploading", v1.SnapshotBackupPhaseUploading, "app"),
+	}}}
+
+	for _, item := range []struct {
+		source string
+		code   codes.Code
+	}{
+		{source: "completed", code: codes.OK},
+		{source: v1.DefaultNamespace + "/completed", code: codes.OK},
+		{source: "missing", code: codes.InvalidArgument},
+		{source: "other-ns/completed", code: codes.InvalidArgument},
+		{source: "uploading", code: codes.InvalidArgument},
+	} {
+		err := cs.validateSourceBackup("app", item.source)
+		assert.Equal(t, item.code, status.Code(err), item.source)
+	}
+
+	// backup of volume in another namespace cannot be restored
+	err := cs.validateSourceBackup("other", "completed")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
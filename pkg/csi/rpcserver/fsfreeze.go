@@ -0,0 +1,238 @@
+package rpcserver
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeutil "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/csi/client"
+	"lite.io/liteio/pkg/generated/clientset/versioned"
+	"lite.io/liteio/pkg/util/misc"
+	mkfs "lite.io/liteio/pkg/util/mount"
+)
+
+const (
+	// fsFreezeSyncInterval is the interval of checking timeouts of a snapshot which asks this node to freeze filesystem
+	fsFreezeSyncInterval = time.Second
+)
+
+// FsFreezer freezes the filesystem of a volume mounted on this node before the snapshot of the volume is cut,
+// and thaws it after the cut, or after spec.fsFreezeTimeoutSeconds of the snapshot.
+// The agent of the origin volume asks for freezing by label obnvmf/fs-freeze-node on the AntstorSnapshot.
+// Snapshots with the label of this node are watched, and synced on events and every fsFreezeSyncInterval until the label is removed.
+type FsFreezer struct {
+	nodeID string
+	cli    client.AntstorClientIface
+	// listWatcher watches snapshots with label obnvmf/fs-freeze-node=<nodeID>
+	listWatcher cache.ListerWatcher
+	indexer     cache.Indexer
+	queue       workqueue.RateLimitingInterface
+	// freezeFs and thawFs are replaced in tests
+	freezeFs func(path string) error
+	thawFs   func(path string) error
+}
+
+func NewFsFreezer(nodeID string, cli client.AntstorClientIface, storeCli versioned.Interface) *FsFreezer {
+	return &FsFreezer{
+		nodeID: nodeID,
+		cli:    cli,
+		listWatcher: cache.NewFilteredListWatchFromClient(storeCli.VolumeV1().RESTClient(), "antstorsnapshots",
+			v1.DefaultNamespace, func(options *metav1.ListOptions) {
+				options.LabelSelector = fmt.Sprintf("%s=%s", v1.FsFreezeNodeLabelKey, nodeID)
+			}),
+		queue:    workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		freezeFs: mkfs.FreezeFs,
+		thawFs:   mkfs.ThawFs,
+	}
+}
+
+// Start watches snapshots and syncs them until stopCh is closed
+func (ff *FsFreezer) Start(stopCh <-chan struct{}) {
+	klog.Infof("start fs freezer of node %s", ff.nodeID)
+	var informer cache.Controller
+	ff.indexer, informer = cache.NewIndexerInformer(ff.listWatcher, &v1.AntstorSnapshot{}, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc: ff.enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			ff.enqueue(new)
+		},
+		DeleteFunc: ff.enqueue,
+	}, cache.Indexers{})
+
+	defer runtimeutil.HandleCrash()
+	defer ff.queue.ShutDown()
+
+	go informer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, informer.HasSynced) {
+		runtimeutil.HandleError(fmt.Errorf("timed out waiting for caches to sync"))
+		return
+	}
+
+	go wait.Until(func() {
+		for ff.processNextItem() {
+		}
+	}, time.Second, stopCh)
+
+	<-stopCh
+	klog.Infof("stop fs freezer of node %s", ff.nodeID)
+}
+
+func (ff *FsFreezer) enqueue(obj interface{}) {
+	// the snapshot is deleted, or its label is removed
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err == nil {
+		ff.queue.Add(key)
+	}
+}
+
+func (ff *FsFreezer) processNextItem() bool {
+	key, quit := ff.queue.Get()
+	if quit {
+		return false
+	}
+	defer ff.queue.Done(key)
+
+	requeue, err := ff.syncKey(key.(string))
+	if err != nil {
+		klog.Errorf("sync fs freeze of snapshot %s failed: %+v", key, err)
+		ff.queue.AddRateLimited(key)
+		return true
+	}
+	ff.queue.Forget(key)
+	// timeouts of freezing and waiting for the cut are not notified by events
+	if requeue {
+		ff.queue.AddAfter(key, fsFreezeSyncInterval)
+	}
+	return true
+}
+
+// syncKey syncs the snapshot in cache. It returns true if the snapshot is still waiting for this node.
+func (ff *FsFreezer) syncKey(key string) (requeue bool, err error) {
+	obj, exists, err := ff.indexer.GetByKey(key)
+	if err != nil || !exists {
+		return false, err
+	}
+	snap, ok := obj.(*v1.AntstorSnapshot)
+	if !ok {
+		return false, fmt.Errorf("object %s is not AntstorSnapshot", key)
+	}
+	return true, ff.syncSnapshot(snap.DeepCopy())
+}
+
+// syncSnapshot moves the snapshot one step forward. All steps are recorded in the snapshot, so they are safe to retry.
+func (ff *FsFreezer) syncSnapshot(snap *v1.AntstorSnapshot) (err error) {
+	var (
+		path         = snap.Annotations[v1.FsFreezePathAnnoKey]
+		timeout      = time.Duration(snap.Spec.FsFreezeTimeoutSeconds) * time.Second
+		_, frozen    = snap.Annotations[v1.FsFrozenAtAnnoKey]
+		_, thawed    = snap.Annotations[v1.FsThawedAtAnnoKey]
+		_, failed    = snap.Annotations[v1.FsFreezeErrorAnnoKey]
+		hasFinalizer = misc.InSliceString(v1.FsFreezeFinalizer, snap.Finalizers)
+		// the agent adds SnapshotFinalizer after the snapshot is cut
+		cut      = misc.InSliceString(v1.SnapshotFinalizer, snap.Finalizers)
+		deleting = snap.DeletionTimestamp != nil
+		now      = strconv.FormatInt(time.Now().Unix(), 10)
+	)
+
+	switch {
+	case thawed || failed:
+		// thaw again, in case the node plugin restarted after setting the annotation
+		if err = ff.thawFs(path); err != nil {
+			return
+		}
+		return ff.finish(snap)
+
+	case !frozen && (cut || deleting || path == "" || time.Since(annotationUnix(snap, v1.FsFreezeRequestedAtAnnoKey)) >= timeout):
+		klog.Infof("snapshot %s is cut or deleted before filesystem is frozen, give up freezing", snap.Name)
+		snap.Annotations[v1.FsFreezeErrorAnnoKey] = "filesystem is not frozen in time"
+		// the filesystem may be frozen but not recorded
+		if hasFinalizer {
+			if err = ff.thawFs(path); err != nil {
+				return
+			}
+		}
+		return ff.finish(snap)
+
+	case !frozen && !hasFinalizer:
+		// the finalizer is added before freezing, so the filesystem is always thawed even if the snapshot is deleted right after freezing
+		snap.Finalizers = append(snap.Finalizers, v1.FsFreezeFinalizer)
+		_, err = ff.cli.UpdateSnapshot(snap)
+		return
+
+	case !frozen:
+		// the cache may not have the update of last sync. Freezing is not idempotent, so check the latest snapshot before it.
+		var latest *v1.AntstorSnapshot
+		if latest, err = ff.cli.GetSnapshotByName(snap.Namespace, snap.Name); err != nil {
+			return
+		}
+		if latest.ResourceVersion != snap.ResourceVersion {
+			return fmt.Errorf("snapshot %s in cache is stale, resourceVersion %s, latest %s", snap.Name, snap.ResourceVersion, latest.ResourceVersion)
+		}
+		klog.Infof("freezing filesystem at %s for snapshot %s, timeout %s", path, snap.Name, timeout)
+		if err = ff.freezeFs(path); err != nil {
+			klog.Error(err)
+			snap.Annotations[v1.FsFreezeErrorAnnoKey] = err.Error()
+			if thawErr := ff.thawFs(path); thawErr != nil {
+				return thawErr
+			}
+			return ff.finish(snap)
+		}
+		snap.Annotations[v1.FsFrozenAtAnnoKey] = now
+		if _, err = ff.cli.UpdateSnapshot(snap); err != nil {
+			// the agent does not know the filesystem is frozen, so it must not stay frozen
+			if thawErr := ff.thawFs(path); thawErr != nil {
+				klog.Error(thawErr)
+			}
+		}
+		return
+
+	case !cut && !deleting && time.Since(annotationUnix(snap, v1.FsFrozenAtAnnoKey)) < timeout:
+		// waiting for the cut
+		return
+	}
+
+	// The thaw is recorded before thawing. The agent records the cut by updating the snapshot read before the cut,
+	// so only one of the thaw before the cut and the cut is recorded successfully.
+	if !cut {
+		klog.Infof("snapshot %s is not cut in %s, thaw filesystem at %s", snap.Name, timeout, path)
+		snap.Annotations[v1.FsFreezeErrorAnnoKey] = "filesystem is thawed before the cut"
+	}
+	snap.Annotations[v1.FsThawedAtAnnoKey] = now
+	if snap, err = ff.cli.UpdateSnapshot(snap); err != nil {
+		return
+	}
+	if err = ff.thawFs(path); err != nil {
+		return
+	}
+	return ff.finish(snap)
+}
+
+// finish removes FsFreezeFinalizer and label obnvmf/fs-freeze-node, so the snapshot is not synced any more
+func (ff *FsFreezer) finish(snap *v1.AntstorSnapshot) (err error) {
+	var newFinalizers = make([]string, 0, len(snap.Finalizers))
+	for _, item := range snap.Finalizers {
+		if item != v1.FsFreezeFinalizer {
+			newFinalizers = append(newFinalizers, item)
+		}
+	}
+	snap.Finalizers = newFinalizers
+	delete(snap.Labels, v1.FsFreezeNodeLabelKey)
+	_, err = ff.cli.UpdateSnapshot(snap)
+	return
+}
+
+// annotationUnix parses the unix timestamp in annotation. It returns zero time if the annotation is invalid.
+func annotationUnix(snap *v1.AntstorSnapshot, key string) time.Time {
+	sec, err := strconv.ParseInt(snap.Annotations[key], 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
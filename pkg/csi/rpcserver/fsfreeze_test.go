@@ -0,0 +1,149 @@
+package rpcserver
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/csi/client"
+	"lite.io/liteio/pkg/generated/clientset/versioned/fake"
+)
+
+// fakeSnapshotClient keeps snapshots in memory, and rejects updates with stale resourceVersion
+type fakeSnapshotClient struct {
+	client.AntstorClientIface
+	snaps map[string]*v1.AntstorSnapshot
+}
+
+func (c *fakeSnapshotClient) GetSnapshotByName(ns, name string) (snapshot *client.Snapshot, err error) {
+	snap, has := c.snaps[name]
+	if !has {
+		return nil, errors.NewNotFound(schema.GroupResource{Resource: "antstorsnapshots"}, name)
+	}
+	return snap.DeepCopy(), nil
+}
+
+func (c *fakeSnapshotClient) UpdateSnapshot(snap *client.Snapshot) (snapshot *client.Snapshot, err error) {
+	old := c.snaps[snap.Name]
+	if old.ResourceVersion != snap.ResourceVersion {
+		return nil, errors.NewConflict(schema.GroupResource{Resource: "antstorsnapshots"}, snap.Name, nil)
+	}
+	rv, _ := strconv.Atoi(old.ResourceVersion)
+	snapshot = snap.DeepCopy()
+	snapshot.ResourceVersion = strconv.Itoa(rv + 1)
+	c.snaps[snap.Name] = snapshot
+	return snapshot.DeepCopy(), nil
+}
+
+func TestFsFreezer(t *testing.T) {
+	var (
+		frozen = make(map[string]bool)
+		unix   = func(t time.Time) string { return strconv.FormatInt(t.Unix(), 10) }
+	)
+	newSnap := func(name, path string, requestedAt time.Time) *v1.AntstorSnapshot {
+		return &v1.AntstorSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				ResourceVersion: "1",
+				Labels:          map[string]string{v1.FsFreezeNodeLabelKey: "node-1"},
+				Annotations: map[string]string{
+					v1.FsFreezePathAnnoKey:        path,
+					v1.FsFreezeRequestedAtAnnoKey: unix(requestedAt),
+				},
+			},
+			Spec: v1.AntstorSnapshotSpec{FsFreezeTimeoutSeconds: 10},
+		}
+	}
+	cli := &fakeSnapshotClient{snaps: map[string]*v1.AntstorSnapshot{
+		"snap-1":  newSnap("snap-1", "/mnt/vol-1", time.Now()),
+		"expired": newSnap("expired", "/mnt/vol-2", time.Now().Add(-time.Minute)),
+	}}
+	ff := NewFsFreezer("node-1", cli, fake.NewSimpleClientset())
+	ff.indexer = cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	var freezeCount int
+	ff.freezeFs = func(path string) error {
+		frozen[path] = true
+		freezeCount++
+		return nil
+	}
+	ff.thawFs = func(path string) error {
+		frozen[path] = false
+		return nil
+	}
+
+	// watch keeps snapshots with the label of node in cache
+	refresh := func() {
+		for _, item := range ff.indexer.List() {
+			assert.NoError(t, ff.indexer.Delete(item))
+		}
+		for _, snap := range cli.snaps {
+			if snap.Labels[v1.FsFreezeNodeLabelKey] == "node-1" {
+				assert.NoError(t, ff.indexer.Add(snap.DeepCopy()))
+			}
+		}
+	}
+	sync := func() {
+		refresh()
+		for _, key := range ff.indexer.ListKeys() {
+			requeue, err := ff.syncKey(key)
+			assert.NoError(t, err)
+			assert.True(t, requeue)
+		}
+	}
+
+	// add finalizer, then freeze
+	sync()
+	assert.Contains(t, cli.snaps["snap-1"].Finalizers, v1.FsFreezeFinalizer)
+	sync()
+	assert.True(t, frozen["/mnt/vol-1"])
+	assert.NotEmpty(t, cli.snaps["snap-1"].Annotations[v1.FsFrozenAtAnnoKey])
+	assert.Equal(t, 1, freezeCount)
+
+	// cache does not have the update of freezing, do not freeze again
+	_, err := ff.syncKey("snap-1")
+	assert.Error(t, err)
+	assert.Equal(t, 1, freezeCount)
+
+	// request is expired, never freeze
+	assert.False(t, frozen["/mnt/vol-2"])
+	assert.NotEmpty(t, cli.snaps["expired"].Annotations[v1.FsFreezeErrorAnnoKey])
+	assert.Empty(t, cli.snaps["expired"].Labels[v1.FsFreezeNodeLabelKey])
+
+	// waiting for the cut
+	sync()
+	assert.True(t, frozen["/mnt/vol-1"])
+
+	// the agent records the cut, thaw
+	cli.snaps["snap-1"].Finalizers = append(cli.snaps["snap-1"].Finalizers, v1.SnapshotFinalizer)
+	sync()
+	assert.False(t, frozen["/mnt/vol-1"])
+	snap := cli.snaps["snap-1"]
+	assert.NotEmpty(t, snap.Annotations[v1.FsThawedAtAnnoKey])
+	assert.Empty(t, snap.Annotations[v1.FsFreezeErrorAnnoKey])
+	assert.Equal(t, []string{v1.SnapshotFinalizer}, snap.Finalizers)
+	assert.Empty(t, snap.Labels[v1.FsFreezeNodeLabelKey])
+	// label is removed, snapshot is not synced any more
+	refresh()
+	requeue, err := ff.syncKey("snap-1")
+	assert.NoError(t, err)
+	assert.False(t, requeue)
+
+	// the cut does not come in time, thaw and record the error
+	snap = newSnap("timeout", "/mnt/vol-3", time.Now())
+	cli.snaps["timeout"] = snap
+	sync()
+	sync()
+	assert.True(t, frozen["/mnt/vol-3"])
+	cli.snaps["timeout"].Annotations[v1.FsFrozenAtAnnoKey] = unix(time.Now().Add(-11 * time.Second))
+	sync()
+	assert.False(t, frozen["/mnt/vol-3"])
+	assert.Equal(t, "filesystem is thawed before the cut", cli.snaps["timeout"].Annotations[v1.FsFreezeErrorAnnoKey])
+	assert.Empty(t, cli.snaps["timeout"].Finalizers)
+}
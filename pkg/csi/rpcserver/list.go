@@ -0,0 +1,206 @@
+package rpcserver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/csi/client"
+)
+
+// listPVs returns Volumes and DataControls sorted by UUID. Volumes of VolumeGroups are not PVs and are skipped.
+// Destination volumes of VolumeMigrations, named mig-<uuid>, are not PVs either. They have the uuid label of the source volume.
+func (cs *ControllerServer) listPVs() (list []client.PV, err error) {
+	vols, err := cs.cli.ListVolumes()
+	if err != nil {
+		return
+	}
+	dcs, err := cs.cli.ListDataControls()
+	if err != nil {
+		return
+	}
+
+	for i := range vols.Items {
+		vol := &vols.Items[i]
+		if _, has := vol.Labels[v1.VolumeGroupNameLabelKey]; has || vol.Spec.Uuid == "" {
+			continue
+		}
+		if _, has := vol.Labels[v1.MigrationLabelKeyMigrationName]; has {
+			continue
+		}
+		list = append(list, client.PV{
+			Namespace: vol.Namespace,
+			Name:      vol.Name,
+			UUID:      vol.Spec.Uuid,
+			Type:      client.PvTypeVolume,
+			Volume:    vol,
+		})
+	}
+	for i := range dcs.Items {
+		dc := &dcs.Items[i]
+		if dc.Spec.UUID == "" {
+			continue
+		}
+		list = append(list, client.PV{
+			Namespace:  dc.Namespace,
+			Name:       dc.Name,
+			UUID:       dc.Spec.UUID,
+			Type:       client.PvTypeVolumeGroup,
+			DataContrl: dc,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].UUID < list[j].UUID
+	})
+	return
+}
+
+// listPoolsByName returns StoragePools indexed by name, which is the node ID
+func (cs *ControllerServer) listPoolsByName() (pools map[string]*v1.StoragePool, err error) {
+	list, err := cs.cli.ListStoragePool(v1.DefaultNamespace)
+	if err != nil {
+		return
+	}
+	pools = make(map[string]*v1.StoragePool, len(list.Items))
+	for i := range list.Items {
+		pools[list.Items[i].Name] = &list.Items[i]
+	}
+	return
+}
+
+// listSnapshots returns snapshots sorted by ID. If snapID is not empty, only the snapshot is returned.
+// If sourceVolID is not empty, only snapshots of the volume are returned.
+func (cs *ControllerServer) listSnapshots(snapID, sourceVolID string) (list []*csi.Snapshot, err error) {
+	var snaps []v1.AntstorSnapshot
+	if snapID != "" {
+		var snap *v1.AntstorSnapshot
+		snap, err = cs.cli.GetSnapshotByID(snapID)
+		if err == client.ErrorNotFoundResource {
+			return nil, nil
+		}
+		if err != nil {
+			return
+		}
+		snaps = append(snaps, *snap)
+	} else {
+		var snapList *v1.AntstorSnapshotList
+		if snapList, err = cs.cli.ListSnapshots(); err != nil {
+			return
+		}
+		snaps = snapList.Items
+	}
+
+	// uuid of origin volumes
+	vols, err := cs.cli.ListVolumes()
+	if err != nil {
+		return
+	}
+	var volIDs = make(map[string]string, len(vols.Items))
+	for _, vol := range vols.Items {
+		volIDs[vol.Namespace+"/"+vol.Name] = vol.Spec.Uuid
+	}
+
+	for _, snap := range snaps {
+		var (
+			id    = snap.Labels[v1.SnapUuidLabelKey]
+			volID = volIDs[snap.Spec.OriginVolNamespace+"/"+snap.Spec.OriginVolName]
+		)
+		if id == "" || (sourceVolID != "" && volID != sourceVolID) {
+			continue
+		}
+		list = append(list, &csi.Snapshot{
+			SizeBytes:      snap.Spec.Size,
+			SnapshotId:     id,
+			SourceVolumeId: volID,
+			CreationTime:   timestamppb.New(snap.CreationTimestamp.Time),
+			ReadyToUse:     snap.Status.Status == v1.SnapshotStatusReady,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].SnapshotId < list[j].SnapshotId
+	})
+	return
+}
+
+// paginate returns the range [start, end) of a list with total items, and the token of the next page.
+// The token is the offset of the first item of the page.
+func paginate(total int, maxEntries int32, startingToken string) (start, end int, nextToken string, err error) {
+	if startingToken != "" {
+		start, err = strconv.Atoi(startingToken)
+		if err != nil || start < 0 || start > total {
+			err = fmt.Errorf("invalid starting_token %q", startingToken)
+			return
+		}
+	}
+
+	end = total
+	if maxEntries > 0 && start+int(maxEntries) < total {
+		end = start + int(maxEntries)
+		nextToken = strconv.Itoa(end)
+	}
+	return
+}
+
+// publishedNodeIDs returns the host node of PV, where the PV is connected and mounted
+func publishedNodeIDs(pv client.PV) (ids []string) {
+	var hostNode string
+	switch pv.Type {
+	case client.PvTypeVolume:
+		if pv.Volume.Spec.HostNode != nil {
+			hostNode = pv.Volume.Spec.HostNode.ID
+		}
+	case client.PvTypeVolumeGroup:
+		hostNode = pv.DataContrl.Spec.HostNode.ID
+	}
+	if hostNode != "" {
+		ids = append(ids, hostNode)
+	}
+	return
+}
+
+// volumeCondition reports PV as abnormal if the StoragePool on its target node is missing, offline or has error conditions
+func volumeCondition(pv client.PV, pools map[string]*v1.StoragePool) *csi.VolumeCondition {
+	var targetNode = pv.GetTargetNodeId()
+	if targetNode == "" {
+		return &csi.VolumeCondition{
+			Abnormal: false,
+			Message:  "volume is not scheduled",
+		}
+	}
+
+	pool, has := pools[targetNode]
+	if !has {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("StoragePool %s is not found", targetNode),
+		}
+	}
+
+	var errs []string
+	if pool.Status.Status == v1.PoolStatusOffline {
+		errs = append(errs, "pool is offline")
+	}
+	for _, item := range pool.Status.Conditions {
+		if item.Status == v1.StatusError {
+			errs = append(errs, fmt.Sprintf("%s: %s", item.Type, item.Message))
+		}
+	}
+	if len(errs) > 0 {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("StoragePool %s is abnormal, %s", targetNode, strings.Join(errs, "; ")),
+		}
+	}
+
+	return &csi.VolumeCondition{
+		Abnormal: false,
+		Message:  "status ok",
+	}
+}
@@ -0,0 +1,296 @@
+package rpcserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+	"lite.io/liteio/pkg/csi/client"
+)
+
+// fakeClient serves PVs, snapshots and pools from memory. Other methods of AntstorClientIface are not implemented.
+type fakeClient struct {
+	client.AntstorClientIface
+	vols  []v1.AntstorVolume
+	snaps []v1.AntstorSnapshot
+	pools []v1.StoragePool
+}
+
+func (c *fakeClient) ListVolumes() (*v1.AntstorVolumeList, error) {
+	return &v1.AntstorVolumeList{Items: c.vols}, nil
+}
+
+func (c *fakeClient) ListDataControls() (*v1.AntstorDataControlList, error) {
+	return &v1.AntstorDataControlList{}, nil
+}
+
+func (c *fakeClient) ListSnapshots() (*v1.AntstorSnapshotList, error) {
+	return &v1.AntstorSnapshotList{Items: c.snaps}, nil
+}
+
+func (c *fakeClient) GetSnapshotByID(id string) (*client.Snapshot, error) {
+	for i := range c.snaps {
+		if c.snaps[i].Labels[v1.SnapUuidLabelKey] == id {
+			return &c.snaps[i], nil
+		}
+	}
+	return nil, client.ErrorNotFoundResource
+}
+
+func (c *fakeClient) GetPvByID(id string) (pv client.PV, err error) {
+	for i := range c.vols {
+		if vol := &c.vols[i]; vol.Spec.Uuid == id {
+			return client.PV{Namespace: vol.Namespace, Name: vol.Name, UUID: id, Type: client.PvTypeVolume, Volume: vol}, nil
+		}
+	}
+	return pv, client.ErrorNotFoundResource
+}
+
+func (c *fakeClient) GetStoragePoolByName(ns, name string) (*client.StoragePool, error) {
+	for i := range c.pools {
+		if c.pools[i].Name == name {
+			return &c.pools[i], nil
+		}
+	}
+	return nil, errors.NewNotFound(v1.GroupVersion.WithResource("storagepools").GroupResource(), name)
+}
+
+func (c *fakeClient) ListStoragePool(ns string) (*v1.StoragePoolList, error) {
+	return &v1.StoragePoolList{Items: c.pools}, nil
+}
+
+func newTestVolume(name, uuid, targetNode, hostNode string) v1.AntstorVolume {
+	vol := v1.AntstorVolume{
+		ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: name},
+		Spec: v1.AntstorVolumeSpec{
+			Uuid:         uuid,
+			SizeByte:     1 << 30,
+			TargetNodeId: targetNode,
+		},
+	}
+	if hostNode != "" {
+		vol.Spec.HostNode = &v1.NodeInfo{ID: hostNode}
+	}
+	return vol
+}
+
+func newTestSnapshot(name, uuid, volName string, ready bool) v1.AntstorSnapshot {
+	snap := v1.AntstorSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         v1.DefaultNamespace,
+			Name:              name,
+			Labels:            map[string]string{v1.SnapUuidLabelKey: uuid},
+			CreationTimestamp: metav1.NewTime(time.Unix(1700000000, 0)),
+		},
+		Spec: v1.AntstorSnapshotSpec{
+			OriginVolName:      volName,
+			OriginVolNamespace: v1.DefaultNamespace,
+			Size:               1 << 30,
+		},
+	}
+	if ready {
+		snap.Status.Status = v1.SnapshotStatusReady
+	}
+	return snap
+}
+
+func TestListVolumes(t *testing.T) {
+	dest := newTestVolume("mig-uuid-dest", "uuid-dest", "node-2", "node-2")
+	dest.Labels = map[string]string{
+		v1.UuidLabelKey:                   "uuid-vol-1",
+		v1.MigrationLabelKeyMigrationName: "migration-1",
+	}
+	cs := &ControllerServer{cli: &fakeClient{
+		vols: []v1.AntstorVolume{
+			newTestVolume("vol-2", "uuid-vol-2", "node-1", ""),
+			newTestVolume("vol-1", "uuid-vol-1", "node-1", "node-2"),
+			// destination volume of migration is not a PV
+			dest,
+		},
+		pools: []v1.StoragePool{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "node-1"}},
+		},
+	}}
+
+	resp, err := cs.ListVolumes(context.Background(), &csi.ListVolumesRequest{})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Entries, 2)
+	assert.Equal(t, "uuid-vol-1", resp.Entries[0].Volume.VolumeId)
+	assert.Equal(t, []string{"node-2"}, resp.Entries[0].Status.PublishedNodeIds)
+	assert.Equal(t, "uuid-vol-2", resp.Entries[1].Volume.VolumeId)
+}
+
+func TestListSnapshots(t *testing.T) {
+	cs := &ControllerServer{cli: &fakeClient{
+		vols: []v1.AntstorVolume{
+			newTestVolume("vol-1", "uuid-vol-1", "node-1", ""),
+			newTestVolume("vol-2", "uuid-vol-2", "node-1", ""),
+		},
+		snaps: []v1.AntstorSnapshot{
+			newTestSnapshot("snap-c", "uuid-snap-c", "vol-2", true),
+			newTestSnapshot("snap-a", "uuid-snap-a", "vol-1", true),
+			newTestSnapshot("snap-b", "uuid-snap-b", "vol-1", false),
+			// snapshot without uuid is not created by CSI
+			newTestSnapshot("snap-x", "", "vol-1", true),
+		},
+	}}
+	ctx := context.Background()
+	idsOf := func(resp *csi.ListSnapshotsResponse) (ids []string) {
+		for _, item := range resp.Entries {
+			ids = append(ids, item.Snapshot.SnapshotId)
+		}
+		return
+	}
+
+	// pages are sorted by snapshot ID
+	resp, err := cs.ListSnapshots(ctx, &csi.ListSnapshotsRequest{MaxEntries: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"uuid-snap-a", "uuid-snap-b"}, idsOf(resp))
+	assert.Equal(t, "2", resp.NextToken)
+	assert.Equal(t, "uuid-vol-1", resp.Entries[0].Snapshot.SourceVolumeId)
+	assert.True(t, resp.Entries[0].Snapshot.ReadyToUse)
+	assert.False(t, resp.Entries[1].Snapshot.ReadyToUse)
+
+	resp, err = cs.ListSnapshots(ctx, &csi.ListSnapshotsRequest{MaxEntries: 2, StartingToken: resp.NextToken})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"uuid-snap-c"}, idsOf(resp))
+	assert.Equal(t, "", resp.NextToken)
+
+	_, err = cs.ListSnapshots(ctx, &csi.ListSnapshotsRequest{StartingToken: "4"})
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	_, err = cs.ListSnapshots(ctx, &csi.ListSnapshotsRequest{MaxEntries: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// filter by snapshot ID
+	resp, err = cs.ListSnapshots(ctx, &csi.ListSnapshotsRequest{SnapshotId: "uuid-snap-c"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"uuid-snap-c"}, idsOf(resp))
+	assert.Equal(t, "uuid-vol-2", resp.Entries[0].Snapshot.SourceVolumeId)
+
+	// not found snapshot is an empty list
+	resp, err = cs.ListSnapshots(ctx, &csi.ListSnapshotsRequest{SnapshotId: "uuid-snap-x"})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Entries)
+
+	// filter by source volume ID
+	resp, err = cs.ListSnapshots(ctx, &csi.ListSnapshotsRequest{SourceVolumeId: "uuid-vol-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"uuid-snap-a", "uuid-snap-b"}, idsOf(resp))
+
+	resp, err = cs.ListSnapshots(ctx, &csi.ListSnapshotsRequest{SnapshotId: "uuid-snap-c", SourceVolumeId: "uuid-vol-1"})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Entries)
+}
+
+func TestControllerGetVolume(t *testing.T) {
+	cli := &fakeClient{
+		vols: []v1.AntstorVolume{
+			newTestVolume("vol-1", "uuid-vol-1", "node-1", "node-2"),
+			newTestVolume("vol-2", "uuid-vol-2", "node-3", ""),
+		},
+		pools: []v1.StoragePool{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: v1.DefaultNamespace, Name: "node-1"}},
+		},
+	}
+	cs := &ControllerServer{cli: cli}
+	ctx := context.Background()
+
+	_, err := cs.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = cs.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: "uuid-vol-x"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// published to the host node
+	resp, err := cs.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: "uuid-vol-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "uuid-vol-1", resp.Volume.VolumeId)
+	assert.Equal(t, int64(1<<30), resp.Volume.CapacityBytes)
+	assert.Equal(t, []string{"node-2"}, resp.Status.PublishedNodeIds)
+	assert.False(t, resp.Status.VolumeCondition.Abnormal)
+
+	// pool of target node is in error
+	cli.pools[0].Status.Conditions = []v1.PoolCondition{
+		{Type: v1.PoolConditionLvmHealth, Status: v1.StatusError, Message: "vg is missing"},
+	}
+	resp, err = cs.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: "uuid-vol-1"})
+	assert.NoError(t, err)
+	assert.True(t, resp.Status.VolumeCondition.Abnormal)
+	assert.Contains(t, resp.Status.VolumeCondition.Message, "vg is missing")
+
+	// pool of target node is not found, and the volume is not published
+	resp, err = cs.ControllerGetVolume(ctx, &csi.ControllerGetVolumeRequest{VolumeId: "uuid-vol-2"})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Status.PublishedNodeIds)
+	assert.True(t, resp.Status.VolumeCondition.Abnormal)
+	assert.Contains(t, resp.Status.VolumeCondition.Message, "not found")
+}
+
+func TestPaginate(t *testing.T) {
+	start, end, next, err := paginate(5, 0, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{0, 5, ""}, []interface{}{start, end, next})
+
+	start, end, next, err = paginate(5, 2, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{0, 2, "2"}, []interface{}{start, end, next})
+
+	start, end, next, err = paginate(5, 2, "4")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{4, 5, ""}, []interface{}{start, end, next})
+
+	start, end, next, err = paginate(5, 2, "5")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{5, 5, ""}, []interface{}{start, end, next})
+
+	_, _, _, err = paginate(5, 2, "6")
+	assert.Error(t, err)
+	_, _, _, err = paginate(5, 2, "abc")
+	assert.Error(t, err)
+}
+
+func TestVolumeCondition(t *testing.T) {
+	pv := client.PV{
+		Type: client.PvTypeVolume,
+		Volume: &v1.AntstorVolume{
+			Spec: v1.AntstorVolumeSpec{
+				TargetNodeId: "node-1",
+				HostNode:     &v1.NodeInfo{ID: "node-2"},
+			},
+		},
+	}
+	pools := map[string]*v1.StoragePool{
+		"node-1": {ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+	}
+
+	assert.Equal(t, []string{"node-2"}, publishedNodeIDs(pv))
+	assert.False(t, volumeCondition(pv, pools).Abnormal)
+
+	pools["node-1"].Status.Status = v1.PoolStatusOffline
+	assert.True(t, volumeCondition(pv, pools).Abnormal)
+	pools["node-1"].Status.Status = v1.PoolStatusReady
+
+	pools["node-1"].Status.Conditions = []v1.PoolCondition{
+		{Type: v1.PoolConditionLvmHealth, Status: v1.StatusOK},
+		{Type: v1.PoolConditionSpkdHealth, Status: v1.StatusError},
+		{Type: v1.PoolConditionKubeNode, Status: v1.StatusError, Message: v1.KubeNodeMsgNcOffline},
+	}
+	cond := volumeCondition(pv, pools)
+	assert.True(t, cond.Abnormal)
+	assert.Contains(t, cond.Message, string(v1.PoolConditionKubeNode))
+	assert.Contains(t, cond.Message, string(v1.PoolConditionSpkdHealth))
+
+	delete(pools, "node-1")
+	assert.True(t, volumeCondition(pv, pools).Abnormal)
+
+	pv.Volume.Spec.TargetNodeId = ""
+	assert.False(t, volumeCondition(pv, pools).Abnormal)
+}
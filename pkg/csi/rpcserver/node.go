@@ -242,7 +242,9 @@ func (ns *NodeServer) NodeStageVolume(ctx context.Context, req *csi.NodeStageVol
 		// do format and mount
 		var _, hasSnapName = labels[v1.VolumeSourceSnapNameLabelKey]
 		var _, hasSnapNS = labels[v1.VolumeSourceSnapNamespaceLabelKey]
-		var isClonedVol = hasSnapName && hasSnapNS
+		var _, hasSrcVolName = labels[v1.VolumeSourceVolNameLabelKey]
+		var _, hasSrcVolNS = labels[v1.VolumeSourceVolNamespaceLabelKey]
+		var isClonedVol = (hasSnapName && hasSnapNS) || (hasSrcVolName && hasSrcVolNS)
 		var mountOpts = make([]string, 0, 1)
 		// if the volume is cloned, use nouuid option
 		// cloned volume has identical UUID with original volume. XFS needs UUID to be unique.
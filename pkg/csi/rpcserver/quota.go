@@ -0,0 +1,89 @@
+package rpcserver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	v1 "lite.io/liteio/pkg/api/volume.antstor.alipay.com/v1"
+)
+
+// lockQuota locks the quota of namespace ns, and returns the func to unlock it.
+// Checking quota and creating the volume or snapshot must be done with the lock,
+// otherwise concurrent requests may pass the check together and exceed the quota.
+// The lock is in memory, so requests handled by different CSI controller processes are not serialized.
+func (cs *ControllerServer) lockQuota(ns string) (unlock func()) {
+	if ns == "" {
+		return func() {}
+	}
+	cs.quotaLocks.Lock(ns)
+	return func() {
+		cs.quotaLocks.Unlock(ns)
+	}
+}
+
+// checkQuota returns ResourceExhausted error if the request exceeds any AntstorQuota in namespace ns.
+// If the volume of pvName already exists, the request is a retry and the check is skipped.
+// Volumes and snapshots are listed from APIServer directly, so those just created by the previous request are counted.
+func (cs *ControllerServer) checkQuota(ns, pvName string, req corev1.ResourceList) error {
+	if ns == "" {
+		return nil
+	}
+
+	quotas, err := cs.cli.ListQuota(ns)
+	if err != nil {
+		klog.Error(err)
+		return status.Error(codes.Internal, err.Error())
+	}
+	if len(quotas.Items) == 0 {
+		return nil
+	}
+
+	vols, err := cs.cli.ListVolumes()
+	if err != nil {
+		klog.Error(err)
+		return status.Error(codes.Internal, err.Error())
+	}
+	if pvName != "" {
+		for _, vol := range vols.Items {
+			if vol.Labels[v1.VolumePVNameLabelKey] == pvName {
+				klog.Infof("volume of pv %s already exists, skip checking quota", pvName)
+				return nil
+			}
+		}
+	}
+
+	snaps, err := cs.cli.ListSnapshots()
+	if err != nil {
+		klog.Error(err)
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	used := v1.QuotaUsageOfNamespace(ns, vols.Items, snaps.Items)
+	for _, quota := range quotas.Items {
+		if exceeded := quota.ExceededResources(used, req); len(exceeded) > 0 {
+			err = fmt.Errorf("exceeded quota %s/%s, requested: %s, used: %s, limited: %s", ns, quota.Name,
+				formatResources(req, exceeded), formatResources(used, exceeded), formatResources(quota.Spec.Hard, exceeded))
+			klog.Error(err)
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// formatResources prints resources in names, e.g. storage=10Gi,spdk.storage=10Gi
+func formatResources(list corev1.ResourceList, names []corev1.ResourceName) string {
+	var items = make([]string, 0, len(names))
+	for _, name := range names {
+		quan := list[name]
+		items = append(items, fmt.Sprintf("%s=%s", name, quan.String()))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
@@ -31,6 +31,10 @@ func (c *FakeVolumeV1) AntstorDataControls(namespace string) v1.AntstorDataContr
 	return &FakeAntstorDataControls{c, namespace}
 }
 
+func (c *FakeVolumeV1) AntstorQuotas(namespace string) v1.AntstorQuotaInterface {
+	return &FakeAntstorQuotas{c, namespace}
+}
+
 func (c *FakeVolumeV1) AntstorSnapshots(namespace string) v1.AntstorSnapshotInterface {
 	return &FakeAntstorSnapshots{c, namespace}
 }
@@ -43,10 +47,30 @@ func (c *FakeVolumeV1) AntstorVolumeGroups(namespace string) v1.AntstorVolumeGro
 	return &FakeAntstorVolumeGroups{c, namespace}
 }
 
+func (c *FakeVolumeV1) SnapshotBackups(namespace string) v1.SnapshotBackupInterface {
+	return &FakeSnapshotBackups{c, namespace}
+}
+
+func (c *FakeVolumeV1) SnapshotGroups(namespace string) v1.SnapshotGroupInterface {
+	return &FakeSnapshotGroups{c, namespace}
+}
+
+func (c *FakeVolumeV1) SnapshotRollbacks(namespace string) v1.SnapshotRollbackInterface {
+	return &FakeSnapshotRollbacks{c, namespace}
+}
+
+func (c *FakeVolumeV1) SnapshotSchedules(namespace string) v1.SnapshotScheduleInterface {
+	return &FakeSnapshotSchedules{c, namespace}
+}
+
 func (c *FakeVolumeV1) StoragePools(namespace string) v1.StoragePoolInterface {
 	return &FakeStoragePools{c, namespace}
 }
 
+func (c *FakeVolumeV1) StorageReservations(namespace string) v1.StorageReservationInterface {
+	return &FakeStorageReservations{c, namespace}
+}
+
 func (c *FakeVolumeV1) VolumeMigrations(namespace string) v1.VolumeMigrationInterface {
 	return &FakeVolumeMigrations{c, namespace}
 }
@@ -19,12 +19,24 @@ package v1
 
 type AntstorDataControlExpansion interface{}
 
+type AntstorQuotaExpansion interface{}
+
 type AntstorSnapshotExpansion interface{}
 
 type AntstorVolumeExpansion interface{}
 
 type AntstorVolumeGroupExpansion interface{}
 
+type SnapshotBackupExpansion interface{}
+
+type SnapshotGroupExpansion interface{}
+
+type SnapshotRollbackExpansion interface{}
+
+type SnapshotScheduleExpansion interface{}
+
 type StoragePoolExpansion interface{}
 
+type StorageReservationExpansion interface{}
+
 type VolumeMigrationExpansion interface{}
@@ -28,10 +28,16 @@ import (
 type VolumeV1Interface interface {
 	RESTClient() rest.Interface
 	AntstorDataControlsGetter
+	AntstorQuotasGetter
 	AntstorSnapshotsGetter
 	AntstorVolumesGetter
 	AntstorVolumeGroupsGetter
+	SnapshotBackupsGetter
+	SnapshotGroupsGetter
+	SnapshotRollbacksGetter
+	SnapshotSchedulesGetter
 	StoragePoolsGetter
+	StorageReservationsGetter
 	VolumeMigrationsGetter
 }
 
@@ -44,6 +50,10 @@ func (c *VolumeV1Client) AntstorDataControls(namespace string) AntstorDataContro
 	return newAntstorDataControls(c, namespace)
 }
 
+func (c *VolumeV1Client) AntstorQuotas(namespace string) AntstorQuotaInterface {
+	return newAntstorQuotas(c, namespace)
+}
+
 func (c *VolumeV1Client) AntstorSnapshots(namespace string) AntstorSnapshotInterface {
 	return newAntstorSnapshots(c, namespace)
 }
@@ -56,10 +66,30 @@ func (c *VolumeV1Client) AntstorVolumeGroups(namespace string) AntstorVolumeGrou
 	return newAntstorVolumeGroups(c, namespace)
 }
 
+func (c *VolumeV1Client) SnapshotBackups(namespace string) SnapshotBackupInterface {
+	return newSnapshotBackups(c, namespace)
+}
+
+func (c *VolumeV1Client) SnapshotGroups(namespace string) SnapshotGroupInterface {
+	return newSnapshotGroups(c, namespace)
+}
+
+func (c *VolumeV1Client) SnapshotRollbacks(namespace string) SnapshotRollbackInterface {
+	return newSnapshotRollbacks(c, namespace)
+}
+
+func (c *VolumeV1Client) SnapshotSchedules(namespace string) SnapshotScheduleInterface {
+	return newSnapshotSchedules(c, namespace)
+}
+
 func (c *VolumeV1Client) StoragePools(namespace string) StoragePoolInterface {
 	return newStoragePools(c, namespace)
 }
 
+func (c *VolumeV1Client) StorageReservations(namespace string) StorageReservationInterface {
+	return newStorageReservations(c, namespace)
+}
+
 func (c *VolumeV1Client) VolumeMigrations(namespace string) VolumeMigrationInterface {
 	return newVolumeMigrations(c, namespace)
 }
@@ -54,14 +54,26 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=volume.antstor.alipay.com, Version=v1
 	case v1.SchemeGroupVersion.WithResource("antstordatacontrols"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Volume().V1().AntstorDataControls().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("antstorquotas"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Volume().V1().AntstorQuotas().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("antstorsnapshots"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Volume().V1().AntstorSnapshots().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("antstorvolumes"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Volume().V1().AntstorVolumes().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("antstorvolumegroups"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Volume().V1().AntstorVolumeGroups().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("snapshotbackups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Volume().V1().SnapshotBackups().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("snapshotgroups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Volume().V1().SnapshotGroups().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("snapshotrollbacks"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Volume().V1().SnapshotRollbacks().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("snapshotschedules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Volume().V1().SnapshotSchedules().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("storagepools"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Volume().V1().StoragePools().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("storagereservations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Volume().V1().StorageReservations().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("volumemigrations"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Volume().V1().VolumeMigrations().Informer()}, nil
 
@@ -25,14 +25,26 @@ import (
 type Interface interface {
 	// AntstorDataControls returns a AntstorDataControlInformer.
 	AntstorDataControls() AntstorDataControlInformer
+	// AntstorQuotas returns a AntstorQuotaInformer.
+	AntstorQuotas() AntstorQuotaInformer
 	// AntstorSnapshots returns a AntstorSnapshotInformer.
 	AntstorSnapshots() AntstorSnapshotInformer
 	// AntstorVolumes returns a AntstorVolumeInformer.
 	AntstorVolumes() AntstorVolumeInformer
 	// AntstorVolumeGroups returns a AntstorVolumeGroupInformer.
 	AntstorVolumeGroups() AntstorVolumeGroupInformer
+	// SnapshotBackups returns a SnapshotBackupInformer.
+	SnapshotBackups() SnapshotBackupInformer
+	// SnapshotGroups returns a SnapshotGroupInformer.
+	SnapshotGroups() SnapshotGroupInformer
+	// SnapshotRollbacks returns a SnapshotRollbackInformer.
+	SnapshotRollbacks() SnapshotRollbackInformer
+	// SnapshotSchedules returns a SnapshotScheduleInformer.
+	SnapshotSchedules() SnapshotScheduleInformer
 	// StoragePools returns a StoragePoolInformer.
 	StoragePools() StoragePoolInformer
+	// StorageReservations returns a StorageReservationInformer.
+	StorageReservations() StorageReservationInformer
 	// VolumeMigrations returns a VolumeMigrationInformer.
 	VolumeMigrations() VolumeMigrationInformer
 }
@@ -53,6 +65,11 @@ func (v *version) AntstorDataControls() AntstorDataControlInformer {
 	return &antstorDataControlInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// AntstorQuotas returns a AntstorQuotaInformer.
+func (v *version) AntstorQuotas() AntstorQuotaInformer {
+	return &antstorQuotaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // AntstorSnapshots returns a AntstorSnapshotInformer.
 func (v *version) AntstorSnapshots() AntstorSnapshotInformer {
 	return &antstorSnapshotInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -68,11 +85,36 @@ func (v *version) AntstorVolumeGroups() AntstorVolumeGroupInformer {
 	return &antstorVolumeGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// SnapshotBackups returns a SnapshotBackupInformer.
+func (v *version) SnapshotBackups() SnapshotBackupInformer {
+	return &snapshotBackupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// SnapshotGroups returns a SnapshotGroupInformer.
+func (v *version) SnapshotGroups() SnapshotGroupInformer {
+	return &snapshotGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// SnapshotRollbacks returns a SnapshotRollbackInformer.
+func (v *version) SnapshotRollbacks() SnapshotRollbackInformer {
+	return &snapshotRollbackInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// SnapshotSchedules returns a SnapshotScheduleInformer.
+func (v *version) SnapshotSchedules() SnapshotScheduleInformer {
+	return &snapshotScheduleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // StoragePools returns a StoragePoolInformer.
 func (v *version) StoragePools() StoragePoolInformer {
 	return &storagePoolInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// StorageReservations returns a StorageReservationInformer.
+func (v *version) StorageReservations() StorageReservationInformer {
+	return &storageReservationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // VolumeMigrations returns a VolumeMigrationInformer.
 func (v *version) VolumeMigrations() VolumeMigrationInformer {
 	return &volumeMigrationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -25,6 +25,14 @@ type AntstorDataControlListerExpansion interface{}
 // AntstorDataControlNamespaceLister.
 type AntstorDataControlNamespaceListerExpansion interface{}
 
+// AntstorQuotaListerExpansion allows custom methods to be added to
+// AntstorQuotaLister.
+type AntstorQuotaListerExpansion interface{}
+
+// AntstorQuotaNamespaceListerExpansion allows custom methods to be added to
+// AntstorQuotaNamespaceLister.
+type AntstorQuotaNamespaceListerExpansion interface{}
+
 // AntstorSnapshotListerExpansion allows custom methods to be added to
 // AntstorSnapshotLister.
 type AntstorSnapshotListerExpansion interface{}
@@ -49,6 +57,38 @@ type AntstorVolumeGroupListerExpansion interface{}
 // AntstorVolumeGroupNamespaceLister.
 type AntstorVolumeGroupNamespaceListerExpansion interface{}
 
+// SnapshotBackupListerExpansion allows custom methods to be added to
+// SnapshotBackupLister.
+type SnapshotBackupListerExpansion interface{}
+
+// SnapshotBackupNamespaceListerExpansion allows custom methods to be added to
+// SnapshotBackupNamespaceLister.
+type SnapshotBackupNamespaceListerExpansion interface{}
+
+// SnapshotGroupListerExpansion allows custom methods to be added to
+// SnapshotGroupLister.
+type SnapshotGroupListerExpansion interface{}
+
+// SnapshotGroupNamespaceListerExpansion allows custom methods to be added to
+// SnapshotGroupNamespaceLister.
+type SnapshotGroupNamespaceListerExpansion interface{}
+
+// SnapshotRollbackListerExpansion allows custom methods to be added to
+// SnapshotRollbackLister.
+type SnapshotRollbackListerExpansion interface{}
+
+// SnapshotRollbackNamespaceListerExpansion allows custom methods to be added to
+// SnapshotRollbackNamespaceLister.
+type SnapshotRollbackNamespaceListerExpansion interface{}
+
+// SnapshotScheduleListerExpansion allows custom methods to be added to
+// SnapshotScheduleLister.
+type SnapshotScheduleListerExpansion interface{}
+
+// SnapshotScheduleNamespaceListerExpansion allows custom methods to be added to
+// SnapshotScheduleNamespaceLister.
+type SnapshotScheduleNamespaceListerExpansion interface{}
+
 // StoragePoolListerExpansion allows custom methods to be added to
 // StoragePoolLister.
 type StoragePoolListerExpansion interface{}
@@ -57,6 +97,14 @@ type StoragePoolListerExpansion interface{}
 // StoragePoolNamespaceLister.
 type StoragePoolNamespaceListerExpansion interface{}
 
+// StorageReservationListerExpansion allows custom methods to be added to
+// StorageReservationLister.
+type StorageReservationListerExpansion interface{}
+
+// StorageReservationNamespaceListerExpansion allows custom methods to be added to
+// StorageReservationNamespaceLister.
+type StorageReservationNamespaceListerExpansion interface{}
+
 // VolumeMigrationListerExpansion allows custom methods to be added to
 // VolumeMigrationLister.
 type VolumeMigrationListerExpansion interface{}
@@ -232,6 +232,48 @@ func (_m *LvmIface) MergeSnapshot(vgName string, snapName string) error {
 	return r0
 }
 
+// CreateThinSnapshot provides a mock function with given fields: vgName, snapName, originVol
+func (_m *LvmIface) CreateThinSnapshot(vgName string, snapName string, originVol string) error {
+	ret := _m.Called(vgName, snapName, originVol)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(vgName, snapName, originVol)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ActivateLV provides a mock function with given fields: vgName, lvName
+func (_m *LvmIface) ActivateLV(vgName string, lvName string) error {
+	ret := _m.Called(vgName, lvName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(vgName, lvName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MergeThinSnapshot provides a mock function with given fields: vgName, snapName, originVol
+func (_m *LvmIface) MergeThinSnapshot(vgName string, snapName string, originVol string) error {
+	ret := _m.Called(vgName, snapName, originVol)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(vgName, snapName, originVol)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RemoveLV provides a mock function with given fields: vgName, lvName
 func (_m *LvmIface) RemoveLV(vgName string, lvName string) error {
 	ret := _m.Called(vgName, lvName)
@@ -274,6 +316,34 @@ func (_m *LvmIface) RemoveVG(vgName string) error {
 	return r0
 }
 
+// ResumeLV provides a mock function with given fields: vgName, lvName
+func (_m *LvmIface) ResumeLV(vgName string, lvName string) error {
+	ret := _m.Called(vgName, lvName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(vgName, lvName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SuspendLV provides a mock function with given fields: vgName, lvName
+func (_m *LvmIface) SuspendLV(vgName string, lvName string) error {
+	ret := _m.Called(vgName, lvName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(vgName, lvName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewLvmIface interface {
 	mock.TestingT
 	Cleanup(func())
@@ -872,6 +872,102 @@ func (_m *SPDKClientIface) NVMFSubsystemAddNS(req client.NVMFSubsystemAddNSReq)
 	return r0, r1
 }
 
+// NVMFSubsystemPause provides a mock function with given fields: req
+func (_m *SPDKClientIface) NVMFSubsystemPause(req client.NVMFSubsystemPauseReq) (bool, error) {
+	ret := _m.Called(req)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(client.NVMFSubsystemPauseReq) (bool, error)); ok {
+		return rf(req)
+	}
+	if rf, ok := ret.Get(0).(func(client.NVMFSubsystemPauseReq) bool); ok {
+		r0 = rf(req)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(client.NVMFSubsystemPauseReq) error); ok {
+		r1 = rf(req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NVMFSubsystemResume provides a mock function with given fields: req
+func (_m *SPDKClientIface) NVMFSubsystemResume(req client.NVMFSubsystemResumeReq) (bool, error) {
+	ret := _m.Called(req)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(client.NVMFSubsystemResumeReq) (bool, error)); ok {
+		return rf(req)
+	}
+	if rf, ok := ret.Get(0).(func(client.NVMFSubsystemResumeReq) bool); ok {
+		r0 = rf(req)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(client.NVMFSubsystemResumeReq) error); ok {
+		r1 = rf(req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NbdStartDisk provides a mock function with given fields: req
+func (_m *SPDKClientIface) NbdStartDisk(req client.NbdStartDiskReq) (string, error) {
+	ret := _m.Called(req)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(client.NbdStartDiskReq) (string, error)); ok {
+		return rf(req)
+	}
+	if rf, ok := ret.Get(0).(func(client.NbdStartDiskReq) string); ok {
+		r0 = rf(req)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(client.NbdStartDiskReq) error); ok {
+		r1 = rf(req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NbdStopDisk provides a mock function with given fields: req
+func (_m *SPDKClientIface) NbdStopDisk(req client.NbdStopDiskReq) (bool, error) {
+	ret := _m.Called(req)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(client.NbdStopDiskReq) (bool, error)); ok {
+		return rf(req)
+	}
+	if rf, ok := ret.Get(0).(func(client.NbdStopDiskReq) bool); ok {
+		r0 = rf(req)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(client.NbdStopDiskReq) error); ok {
+		r1 = rf(req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RpcGetMethods provides a mock function with given fields:
 func (_m *SPDKClientIface) RpcGetMethods() ([]string, error) {
 	ret := _m.Called()
@@ -64,6 +64,7 @@ type SPDKClientIface interface {
 	SpdkBdevRaidIface
 	SpdkMigrateIface
 	SpdkMallocIface
+	SpdkNbdIface
 }
 
 type SPDK struct {
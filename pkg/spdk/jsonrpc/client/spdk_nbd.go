@@ -0,0 +1,41 @@
+package client
+
+import "encoding/json"
+
+type SpdkNbdIface interface {
+	// nbd_start_disk
+	NbdStartDisk(req NbdStartDiskReq) (device string, err error)
+	// nbd_stop_disk
+	NbdStopDisk(req NbdStopDiskReq) (ok bool, err error)
+}
+
+type NbdStartDiskReq struct {
+	// required
+	BdevName string `json:"bdev_name"`
+	// optional, SPDK picks a free /dev/nbdX if it is empty
+	NbdDevice string `json:"nbd_device,omitempty"`
+}
+
+type NbdStopDiskReq struct {
+	// required
+	NbdDevice string `json:"nbd_device"`
+}
+
+// NbdStartDisk exports the bdev as a NBD device, and returns the path of the device
+func (s *SPDK) NbdStartDisk(req NbdStartDiskReq) (device string, err error) {
+	bs, err := s.rawCli.Call("nbd_start_disk", req)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(bs, &device)
+	return
+}
+
+func (s *SPDK) NbdStopDisk(req NbdStopDiskReq) (ok bool, err error) {
+	bs, err := s.rawCli.Call("nbd_stop_disk", req)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(bs, &ok)
+	return
+}
@@ -23,6 +23,10 @@ type SpdkSubsystemIface interface {
 	NVMFGetStats() (result SubsystemStat, err error)
 	// nvmf_subsystem_add_host
 	NVMFSubsystemAddHost(req NVMFSubsystemAddHostReq) (result bool, err error)
+	// nvmf_subsystem_pause
+	NVMFSubsystemPause(req NVMFSubsystemPauseReq) (result bool, err error)
+	// nvmf_subsystem_resume
+	NVMFSubsystemResume(req NVMFSubsystemResumeReq) (result bool, err error)
 }
 
 // nvmf_get_subsystems
@@ -103,3 +107,21 @@ func (s *SPDK) NVMFSubsystemAddHost(req NVMFSubsystemAddHostReq) (res bool, err
 	err = json.Unmarshal(bs, &res)
 	return
 }
+
+func (s *SPDK) NVMFSubsystemPause(req NVMFSubsystemPauseReq) (res bool, err error) {
+	bs, err := s.rawCli.Call("nvmf_subsystem_pause", req)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(bs, &res)
+	return
+}
+
+func (s *SPDK) NVMFSubsystemResume(req NVMFSubsystemResumeReq) (res bool, err error) {
+	bs, err := s.rawCli.Call("nvmf_subsystem_resume", req)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(bs, &res)
+	return
+}
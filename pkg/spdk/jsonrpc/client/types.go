@@ -224,6 +224,19 @@ type NVMFDeleteSubsystemReq struct {
 	TgtName string `json:"tgt_name,omitempty"`
 }
 
+// NVMFSubsystemPauseReq is the request of nvmf_subsystem_pause. I/O of the subsystem is queued until it is resumed.
+type NVMFSubsystemPauseReq struct {
+	NQN string `json:"nqn"`
+	// opt
+	TgtName string `json:"tgt_name,omitempty"`
+}
+
+type NVMFSubsystemResumeReq struct {
+	NQN string `json:"nqn"`
+	// opt
+	TgtName string `json:"tgt_name,omitempty"`
+}
+
 type BdevAioDeleteReq struct {
 	Name string `json:"name"`
 }
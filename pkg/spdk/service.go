@@ -43,6 +43,7 @@ type SpdkServiceIface interface {
 	SpdkVersionIface
 	MallocServiceIface
 	BdevServiceIface
+	NbdServiceIface
 }
 
 type Reconnector interface {
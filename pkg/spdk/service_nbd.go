@@ -0,0 +1,39 @@
+package spdk
+
+import (
+	spdkrpc "lite.io/liteio/pkg/spdk/jsonrpc/client"
+	"k8s.io/klog/v2"
+)
+
+// NbdServiceIface exports bdevs as NBD devices, so that data of lvols can be read or written by the agent
+type NbdServiceIface interface {
+	StartNbdDisk(bdevName string) (device string, err error)
+	StopNbdDisk(device string) (err error)
+}
+
+func (ss *SpdkService) StartNbdDisk(bdevName string) (device string, err error) {
+	ss.cli, err = ss.client()
+	if err != nil {
+		klog.Error("spdk client is nil, try to reconnect spdk socket", err)
+		return
+	}
+
+	klog.Infof("starting nbd disk of bdev %s", bdevName)
+	return ss.cli.NbdStartDisk(spdkrpc.NbdStartDiskReq{
+		BdevName: bdevName,
+	})
+}
+
+func (ss *SpdkService) StopNbdDisk(device string) (err error) {
+	ss.cli, err = ss.client()
+	if err != nil {
+		klog.Error("spdk client is nil, try to reconnect spdk socket", err)
+		return
+	}
+
+	klog.Infof("stopping nbd disk %s", device)
+	_, err = ss.cli.NbdStopDisk(spdkrpc.NbdStopDiskReq{
+		NbdDevice: device,
+	})
+	return
+}
@@ -76,6 +76,9 @@ type TargetServiceIface interface {
 	SubsysAddHost(req SubsystemAddHostRequest) (err error)
 	// GetSubsystemByNQN
 	GetSubsystemByNQN(nqn string) (subsys Subsystem, err error)
+	// PauseSubsystem queues I/O of the subsystem until ResumeSubsystem
+	PauseSubsystem(nqn string) (err error)
+	ResumeSubsystem(nqn string) (err error)
 }
 
 func (ss *SpdkService) CreateTarget(req TargetCreateRequest) (result Target, err error) {
@@ -387,3 +390,49 @@ func (ss *SpdkService) GetSubsystemByNQN(nqn string) (subsys Subsystem, err erro
 
 	return
 }
+
+func (ss *SpdkService) PauseSubsystem(nqn string) (err error) {
+	ss.cli, err = ss.client()
+	if err != nil {
+		klog.Error("spdk client is nil, try to reconnect spdk socket", err)
+		return
+	}
+
+	var result bool
+	result, err = ss.cli.NVMFSubsystemPause(client.NVMFSubsystemPauseReq{
+		NQN: nqn,
+	})
+	if err != nil {
+		klog.Error("pause subsystem failed", err)
+		return
+	}
+
+	if !result {
+		err = fmt.Errorf("pause subsystem %s failed", nqn)
+	}
+
+	return
+}
+
+func (ss *SpdkService) ResumeSubsystem(nqn string) (err error) {
+	ss.cli, err = ss.client()
+	if err != nil {
+		klog.Error("spdk client is nil, try to reconnect spdk socket", err)
+		return
+	}
+
+	var result bool
+	result, err = ss.cli.NVMFSubsystemResume(client.NVMFSubsystemResumeReq{
+		NQN: nqn,
+	})
+	if err != nil {
+		klog.Error("resume subsystem failed", err)
+		return
+	}
+
+	if !result {
+		err = fmt.Errorf("resume subsystem %s failed", nqn)
+	}
+
+	return
+}
@@ -0,0 +1,167 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears bounds the search of Next, so that an impossible schedule like "0 0 30 2 *" does not loop forever
+const maxSearchYears = 5
+
+var (
+	macros = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+
+	fieldBounds = []struct {
+		name     string
+		min, max int
+	}{
+		{"minute", 0, 59},
+		{"hour", 0, 23},
+		{"day of month", 1, 31},
+		{"month", 1, 12},
+		{"day of week", 0, 6},
+	}
+)
+
+// Schedule is a parsed cron expression. Each field is a bitmap of the allowed values.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar are true if the field is "*". If both day fields are restricted, a day matching either of them is allowed.
+	domStar, dowStar bool
+}
+
+// Parse parses a standard 5-field cron expression "minute hour day-of-month month day-of-week", or a macro like @daily.
+// Each field supports "*", numbers, ranges "a-b", lists "a,b" and steps "*/n" or "a-b/n". Day of week 7 is Sunday.
+func Parse(spec string) (s *Schedule, err error) {
+	spec = strings.TrimSpace(spec)
+	if expanded, has := macros[spec]; has {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != len(fieldBounds) {
+		return nil, fmt.Errorf("invalid cron expression %q, expected 5 fields but got %d", spec, len(fields))
+	}
+
+	var bits = make([]uint64, len(fields))
+	for i, field := range fields {
+		max := fieldBounds[i].max
+		// allow 7 as Sunday
+		if i == 4 {
+			max = 7
+		}
+		bits[i], err = parseField(field, fieldBounds[i].min, max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", fieldBounds[i].name, field, err)
+		}
+	}
+	if bits[4]&(1<<7) > 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	s = &Schedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*" || strings.HasPrefix(fields[2], "*/"),
+		dowStar: fields[4] == "*" || strings.HasPrefix(fields[4], "*/"),
+	}
+	return
+}
+
+func parseField(field string, min, max int) (bits uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		var (
+			rangePart = part
+			step      = 1
+			lo, hi    int
+		)
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			rangePart = part[:idx]
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		switch {
+		case rangePart == "*":
+			lo, hi = min, max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+			if hi, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			if lo, err = strconv.Atoi(rangePart); err != nil {
+				return 0, fmt.Errorf("invalid value %q", rangePart)
+			}
+			hi = lo
+			// "a/n" means from a to max
+			if step > 1 {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range [%d, %d]", rangePart, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return
+}
+
+// Next returns the first time after t which matches the schedule, in the location of t.
+// A zero time is returned if no time matches in the next few years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	var yearLimit = t.Year() + maxSearchYears
+
+	for t.Year() <= yearLimit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) > 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) > 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,44 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAndNext(t *testing.T) {
+	base := time.Date(2024, 1, 31, 10, 17, 30, 0, time.UTC)
+
+	cases := []struct {
+		spec string
+		next time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2024, 2, 1, 2, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 31, 11, 0, 0, 0, time.UTC)},
+		{"30 1 1,15 * *", time.Date(2024, 2, 1, 1, 30, 0, 0, time.UTC)},
+		// 2024-02-04 is Sunday, 7 is also Sunday
+		{"0 0 * * 7", time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 1-5", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		// day of month or day of week
+		{"0 0 29 * 0", time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// never matches
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, c := range cases {
+		s, err := Parse(c.spec)
+		assert.NoError(t, err, c.spec)
+		assert.Equal(t, c.next, s.Next(base), c.spec)
+	}
+
+	// exactly on schedule, the next one is returned
+	s, _ := Parse("*/15 * * * *")
+	assert.Equal(t, time.Date(2024, 1, 31, 10, 45, 0, 0, time.UTC), s.Next(time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC)))
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
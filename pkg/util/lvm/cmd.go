@@ -670,6 +670,77 @@ func (c *cmd) MergeSnapshot(vgName, snapName string) (err error) {
 	return
 }
 
+// CreateThinSnapshot command is lvcreate -s -kn -n name_snap antstore-vg/origin-lv
+// The origin must be a thin LV. The snapshot shares blocks with the origin in the thin pool, so it has no size.
+func (c *cmd) CreateThinSnapshot(vgName, snapName, originVol string) (err error) {
+	var out []byte
+	var createCmd = getCreateThinSnapshotCmd(vgName, snapName, originVol)
+	var cmd = filepath.Join(c.binDir, createCmd.cmd)
+	out, err = c.exec.ExecCmd(cmd, createCmd.args)
+	if err != nil {
+		klog.Errorf("err %+v, output: %s", err, string(out))
+		return
+	}
+	return
+}
+
+// MergeThinSnapshot merges the thin snapshot into its origin. A thin snapshot is merged when the origin is activated,
+// so the origin is deactivated before lvconvert --merge and activated afterwards. The origin must not be open.
+func (c *cmd) MergeThinSnapshot(vgName, snapName, originVol string) (err error) {
+	var out []byte
+	for _, item := range []cmdArgs{
+		getLvChangeActivateCmd(vgName, originVol, false),
+		getMergeSnapshotCmd(vgName, snapName),
+		getLvChangeActivateCmd(vgName, originVol, true),
+	} {
+		out, err = c.exec.ExecCmd(filepath.Join(c.binDir, item.cmd), item.args)
+		if err != nil {
+			klog.Errorf("err %+v, output: %s", err, string(out))
+			return
+		}
+	}
+	return
+}
+
+// ActivateLV command is lvchange -ay antstore-vg/lv
+func (c *cmd) ActivateLV(vgName, lvName string) (err error) {
+	var out []byte
+	var activateCmd = getLvChangeActivateCmd(vgName, lvName, true)
+	var cmd = filepath.Join(c.binDir, activateCmd.cmd)
+	out, err = c.exec.ExecCmd(cmd, activateCmd.args)
+	if err != nil {
+		klog.Errorf("err %+v, output: %s", err, string(out))
+		return
+	}
+	return
+}
+
+// SuspendLV command is dmsetup suspend vg-lv. I/O to the LV is blocked until ResumeLV, and the mounted filesystem is frozen.
+func (c *cmd) SuspendLV(vgName, lvName string) (err error) {
+	var out []byte
+	var suspendCmd = getDmsetupCmd("suspend", vgName, lvName)
+	var cmd = filepath.Join(c.binDir, suspendCmd.cmd)
+	out, err = c.exec.ExecCmd(cmd, suspendCmd.args)
+	if err != nil {
+		klog.Errorf("err %+v, output: %s", err, string(out))
+		return
+	}
+	return
+}
+
+// ResumeLV command is dmsetup resume vg-lv. Resuming an active LV does nothing.
+func (c *cmd) ResumeLV(vgName, lvName string) (err error) {
+	var out []byte
+	var resumeCmd = getDmsetupCmd("resume", vgName, lvName)
+	var cmd = filepath.Join(c.binDir, resumeCmd.cmd)
+	out, err = c.exec.ExecCmd(cmd, resumeCmd.args)
+	if err != nil {
+		klog.Errorf("err %+v, output: %s", err, string(out))
+		return
+	}
+	return
+}
+
 // ExpandVolume command is lvextend --size +104857600B antstore-vg/lvol
 // Format of targetVol could be /dev/vg/lvol or vg/lvol
 func (c *cmd) ExpandVolume(deltaBytes int64, targetVol string) (err error) {
@@ -711,6 +782,32 @@ func getCreateSnapshotLinearCmd(vg, snapName, originName string, sizeByte uint64
 	}
 }
 
+// cmd example: lvcreate -s -kn -n name_snap antstore-vg/origin-lv
+// -kn clears the activation skip flag of thin snapshot, so the snapshot is active and can be read.
+func getCreateThinSnapshotCmd(vg, snapName, originName string) cmdArgs {
+	return cmdArgs{
+		cmd: "lvcreate",
+		args: []string{
+			"-s",
+			"-kn",
+			"-n", snapName,
+			fmt.Sprintf("%s/%s", vg, originName),
+		},
+	}
+}
+
+// cmd example: lvchange -an antstore-vg/origin-lv
+func getLvChangeActivateCmd(vg, lv string, active bool) cmdArgs {
+	var flag = "-an"
+	if active {
+		flag = "-ay"
+	}
+	return cmdArgs{
+		cmd:  "lvchange",
+		args: []string{flag, fmt.Sprintf("%s/%s", vg, lv)},
+	}
+}
+
 func getMergeSnapshotCmd(vg, snapName string) cmdArgs {
 	return cmdArgs{
 		cmd: "lvconvert",
@@ -720,6 +817,19 @@ func getMergeSnapshotCmd(vg, snapName string) cmdArgs {
 	}
 }
 
+// cmd example: dmsetup suspend antstore--vg-origin--lv
+func getDmsetupCmd(op, vg, lv string) cmdArgs {
+	return cmdArgs{
+		cmd:  "dmsetup",
+		args: []string{op, DmName(vg, lv)},
+	}
+}
+
+// DmName returns the device-mapper name of LV. Hyphens in VG and LV names are doubled by LVM.
+func DmName(vg, lv string) string {
+	return fmt.Sprintf("%s-%s", strings.ReplaceAll(vg, "-", "--"), strings.ReplaceAll(lv, "-", "--"))
+}
+
 func getStripeLVCreateCmd(vg, lv string, sizeByte uint64, pvNum int) cmdArgs {
 	return cmdArgs{
 		cmd: "lvcreate",
@@ -88,3 +88,27 @@ func TestLvmCmd(t *testing.T) {
 	assert.Equal(t, uint64(1073741824), lvs[0].SizeByte)
 
 }
+
+func TestThinSnapshotCmd(t *testing.T) {
+	mockExec := utilmock.NewShellExec(t)
+	createCmd := getCreateThinSnapshotCmd("vg", "snap", "lv")
+	assert.Equal(t, []string{"-s", "-kn", "-n", "snap", "vg/lv"}, createCmd.args)
+	mockExec.On("ExecCmd", createCmd.cmd, createCmd.args).Return([]byte(""), nil)
+
+	deactivateCmd := getLvChangeActivateCmd("vg", "lv", false)
+	mergeCmd := getMergeSnapshotCmd("vg", "snap")
+	activateCmd := getLvChangeActivateCmd("vg", "lv", true)
+	assert.Equal(t, []string{"-ay", "vg/lv"}, activateCmd.args)
+	mockExec.On("ExecCmd", deactivateCmd.cmd, deactivateCmd.args).Return([]byte(""), nil).Once()
+	mockExec.On("ExecCmd", mergeCmd.cmd, mergeCmd.args).Return([]byte(""), nil).Once()
+	mockExec.On("ExecCmd", activateCmd.cmd, activateCmd.args).Return([]byte(""), nil).Once()
+
+	cmdObj := &cmd{
+		exec: mockExec,
+	}
+	assert.NoError(t, cmdObj.CreateThinSnapshot("vg", "snap", "lv"))
+	assert.NoError(t, cmdObj.MergeThinSnapshot("vg", "snap", "lv"))
+
+	mockExec.On("ExecCmd", activateCmd.cmd, activateCmd.args).Return([]byte(""), nil).Once()
+	assert.NoError(t, cmdObj.ActivateLV("vg", "lv"))
+}
@@ -81,4 +81,11 @@ type LvmIface interface {
 	CreateSnapshotLinear(vgName, snapName, originVol string, sizeByte uint64) (err error)
 	CreateSnapshotStripe(vgName, snapName, originVol string, sizeByte uint64) (err error)
 	MergeSnapshot(vgName, snapName string) (err error)
+	// CreateThinSnapshot creates a thin snapshot of thin LV
+	CreateThinSnapshot(vgName, snapName, originVol string) (err error)
+	MergeThinSnapshot(vgName, snapName, originVol string) (err error)
+	// ActivateLV activates the LV. Activating an active LV does nothing.
+	ActivateLV(vgName, lvName string) (err error)
+	SuspendLV(vgName, lvName string) (err error)
+	ResumeLV(vgName, lvName string) (err error)
 }
@@ -0,0 +1,27 @@
+package misc
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"k8s.io/klog/v2"
+)
+
+// ErrorResponse is the body of a failed JSON API request
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// WriteJSON writes body in JSON with the status code. If body cannot be marshaled, it writes an ErrorResponse with code 500.
+func WriteJSON(writer http.ResponseWriter, code int, body interface{}) {
+	bs, err := json.Marshal(body)
+	if err != nil {
+		klog.Error(err)
+		code = http.StatusInternalServerError
+		bs, _ = json.Marshal(ErrorResponse{Error: err.Error()})
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(code)
+	writer.Write(bs)
+}
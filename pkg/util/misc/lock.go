@@ -41,3 +41,50 @@ func (lock *ResourceLocks) Release(resourceID string) {
 	defer lock.mux.Unlock()
 	lock.locks.Delete(resourceID)
 }
+
+// KeyedMutex is a set of mutexes by key. Callers holding different keys do not block each other.
+type KeyedMutex struct {
+	mux   sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	sync.Mutex
+	// count of callers holding or waiting for the lock
+	ref int
+}
+
+func NewKeyedMutex() *KeyedMutex {
+	return &KeyedMutex{
+		locks: make(map[string]*keyedLock),
+	}
+}
+
+// Lock locks the mutex of key. It blocks until the mutex is available.
+func (km *KeyedMutex) Lock(key string) {
+	km.mux.Lock()
+	lock, has := km.locks[key]
+	if !has {
+		lock = &keyedLock{}
+		km.locks[key] = lock
+	}
+	lock.ref++
+	km.mux.Unlock()
+
+	lock.Lock()
+}
+
+// Unlock unlocks the mutex of key. The mutex is removed if no one else is waiting for it.
+func (km *KeyedMutex) Unlock(key string) {
+	km.mux.Lock()
+	defer km.mux.Unlock()
+	lock, has := km.locks[key]
+	if !has {
+		return
+	}
+	lock.ref--
+	if lock.ref == 0 {
+		delete(km.locks, key)
+	}
+	lock.Unlock()
+}
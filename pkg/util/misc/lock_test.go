@@ -0,0 +1,42 @@
+package misc
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	var (
+		km    = NewKeyedMutex()
+		wg    sync.WaitGroup
+		a, b  int
+		count = map[string]*int{"a": &a, "b": &b}
+	)
+
+	for i := 0; i < 100; i++ {
+		for _, key := range []string{"a", "b"} {
+			wg.Add(1)
+			go func(key string) {
+				defer wg.Done()
+				km.Lock(key)
+				defer km.Unlock(key)
+				// counter of each key is only changed with the lock of key
+				*count[key]++
+			}(key)
+		}
+	}
+	wg.Wait()
+
+	assert.Equal(t, 100, a)
+	assert.Equal(t, 100, b)
+	assert.Empty(t, km.locks)
+
+	// keys do not block each other
+	km.Lock("a")
+	km.Lock("b")
+	km.Unlock("b")
+	km.Unlock("a")
+	assert.Empty(t, km.locks)
+}
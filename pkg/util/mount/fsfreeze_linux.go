@@ -0,0 +1,42 @@
+//go:build linux
+// +build linux
+
+package mount
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// FreezeFs flushes dirty data of the filesystem mounted at path and blocks new writes, by fsfreeze
+func FreezeFs(path string) (err error) {
+	out, err := exec.Command("fsfreeze", "--freeze", path).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("fsfreeze --freeze %s failed: %s, %w", path, strings.TrimSpace(string(out)), err)
+	}
+	klog.Infof("filesystem at %s is frozen", path)
+	return
+}
+
+// ThawFs unblocks writes to the filesystem mounted at path. It returns nil if the filesystem is not frozen or not mounted.
+func ThawFs(path string) (err error) {
+	if _, err = os.Stat(path); os.IsNotExist(err) {
+		klog.Infof("%s does not exist, no need to thaw", path)
+		return nil
+	}
+	out, err := exec.Command("fsfreeze", "--unfreeze", path).CombinedOutput()
+	if err != nil {
+		// EINVAL means the filesystem is not frozen
+		if strings.Contains(string(out), "Invalid argument") {
+			klog.Infof("filesystem at %s is not frozen", path)
+			return nil
+		}
+		return fmt.Errorf("fsfreeze --unfreeze %s failed: %s, %w", path, strings.TrimSpace(string(out)), err)
+	}
+	klog.Infof("filesystem at %s is thawed", path)
+	return
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+// +build !linux
+
+package mount
+
+func FreezeFs(path string) (err error) {
+	return
+}
+
+func ThawFs(path string) (err error) {
+	return
+}
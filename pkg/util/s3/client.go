@@ -0,0 +1,165 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRegion is used if region is not set. MinIO accepts any region.
+	DefaultRegion = "us-east-1"
+
+	defaultTimeout = 5 * time.Minute
+	// maxErrorBody is the max bytes of error response kept in error message
+	maxErrorBody = 1024
+)
+
+// ErrNotFound is returned if the object or bucket does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Config is the address and credentials of a S3-compatible endpoint
+type Config struct {
+	// Endpoint is the URL of service, e.g. http://minio.minio:9000. Scheme defaults to https.
+	Endpoint  string
+	Region    string
+	AccessKey string
+	SecretKey string
+	// Insecure skips verifying TLS certificate of the endpoint
+	Insecure bool
+}
+
+// Client is a minimal client of S3-compatible object storage. Objects are addressed in path-style, i.e. <endpoint>/<bucket>/<key>,
+// and requests are signed by AWS Signature Version 4.
+type Client struct {
+	cfg      Config
+	endpoint *url.URL
+	httpCli  *http.Client
+	// now is used to sign requests, it is replaced in tests
+	now func() time.Time
+}
+
+// NewClient returns a Client of the endpoint
+func NewClient(cfg Config) (cli *Client, err error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("endpoint is empty")
+	}
+	if !strings.Contains(cfg.Endpoint, "://") {
+		cfg.Endpoint = "https://" + cfg.Endpoint
+	}
+	if cfg.Region == "" {
+		cfg.Region = DefaultRegion
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid scheme of endpoint %s", cfg.Endpoint)
+	}
+
+	var transport = http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	return &Client{
+		cfg:      cfg,
+		endpoint: endpoint,
+		httpCli: &http.Client{
+			Transport: transport,
+			Timeout:   defaultTimeout,
+		},
+		now: time.Now,
+	}, nil
+}
+
+// PutObject uploads data as the object. The sha256 of data is verified by the server.
+func (c *Client) PutObject(ctx context.Context, bucket, key string, data []byte) (err error) {
+	resp, err := c.do(ctx, http.MethodPut, bucket, key, data)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp, bucket, key)
+}
+
+// GetObject downloads the object. It returns ErrNotFound if the object does not exist.
+func (c *Client) GetObject(ctx context.Context, bucket, key string) (data []byte, err error) {
+	resp, err := c.do(ctx, http.MethodGet, bucket, key, nil)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if err = checkResponse(resp, bucket, key); err != nil {
+		return
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// HeadObject returns true if the object exists
+func (c *Client) HeadObject(ctx context.Context, bucket, key string) (exists bool, err error) {
+	resp, err := c.do(ctx, http.MethodHead, bucket, key, nil)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	err = checkResponse(resp, bucket, key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DeleteObject deletes the object. It succeeds if the object does not exist.
+func (c *Client) DeleteObject(ctx context.Context, bucket, key string) (err error) {
+	resp, err := c.do(ctx, http.MethodDelete, bucket, key, nil)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	err = checkResponse(resp, bucket, key)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return
+}
+
+func (c *Client) do(ctx context.Context, method, bucket, key string, body []byte) (resp *http.Response, err error) {
+	var u = *c.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + bucket + "/" + strings.TrimPrefix(key, "/")
+	u.RawPath = escapePath(u.Path)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.ContentLength = int64(len(body))
+	if len(body) == 0 {
+		req.Body = http.NoBody
+		req.Header.Set(headerAmzContentSha256, EmptyPayloadHash)
+	} else {
+		req.Header.Set(headerAmzContentSha256, hexSha256(body))
+	}
+	signV4(req, c.cfg.AccessKey, c.cfg.SecretKey, c.cfg.Region, c.now())
+
+	return c.httpCli.Do(req)
+}
+
+func checkResponse(resp *http.Response, bucket, key string) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s/%s", ErrNotFound, bucket, key)
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return fmt.Errorf("%s %s/%s failed, status %d: %s", resp.Request.Method, bucket, key, resp.StatusCode, strings.TrimSpace(string(msg)))
+}
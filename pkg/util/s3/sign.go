@@ -0,0 +1,127 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	signAlgorithm = "AWS4-HMAC-SHA256"
+	signService   = "s3"
+	amzDateFormat = "20060102T150405Z"
+
+	headerAmzDate          = "X-Amz-Date"
+	headerAmzContentSha256 = "X-Amz-Content-Sha256"
+
+	// EmptyPayloadHash is the sha256 of empty body
+	EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// signV4 signs the request by AWS Signature Version 4. Host and all headers of req are signed.
+// X-Amz-Content-Sha256 must be set to the hex sha256 of the body before signing.
+func signV4(req *http.Request, accessKey, secretKey, region string, now time.Time) {
+	var (
+		amzDate     = now.UTC().Format(amzDateFormat)
+		date        = amzDate[:8]
+		scope       = fmt.Sprintf("%s/%s/%s/aws4_request", date, region, signService)
+		payloadHash = req.Header.Get(headerAmzContentSha256)
+	)
+	req.Header.Set(headerAmzDate, amzDate)
+
+	// canonical headers are lower case and sorted
+	var headers = map[string]string{"host": req.URL.Host}
+	for key, vals := range req.Header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(strings.Join(vals, ","))
+	}
+	var names = make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		signAlgorithm,
+		amzDate,
+		scope,
+		hexSha256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSha256([]byte("AWS4"+secretKey), date)
+	key = hmacSha256(key, region)
+	key = hmacSha256(key, signService)
+	key = hmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signAlgorithm, accessKey, scope, signedHeaders, signature))
+}
+
+// escapePath encodes each segment of the path by URI encoding of S3, and keeps "/"
+func escapePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	segments := strings.Split(p, "/")
+	for i, seg := range segments {
+		segments[i] = uriEncode(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(query url.Values) string {
+	var pairs = make([]string, 0, len(query))
+	for key, vals := range query {
+		for _, val := range vals {
+			pairs = append(pairs, uriEncode(key)+"="+uriEncode(val))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode encodes every byte except the unreserved characters A-Z, a-z, 0-9, '-', '.', '_', and '~'
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSha256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}